@@ -10,7 +10,16 @@ import (
 )
 
 func runCLI(ctx context.Context, stdout io.Writer, opts *rootOptions, command string, arg string, mcpTransport string, mcpAddr string) error {
+	return runCLIWithTransport(ctx, stdout, opts, command, arg, mcpTransport, mcpAddr, core.CommandFlags{})
+}
+
+func runCLIWithFlags(ctx context.Context, stdout io.Writer, opts *rootOptions, command string, arg string, flags core.CommandFlags) error {
+	return runCLIWithTransport(ctx, stdout, opts, command, arg, defaultMCPTransport, defaultMCPAddr, flags)
+}
+
+func runCLIWithTransport(ctx context.Context, stdout io.Writer, opts *rootOptions, command string, arg string, mcpTransport string, mcpAddr string, flags core.CommandFlags) error {
 	cli := core.DefaultCLI(stdout, core.DefaultConfig())
+	cli.Flags = flags
 	return cli.Run(ctx, command, arg, mcpTransport, mcpAddr, opts.output)
 }
 
@@ -343,74 +352,105 @@ func newSkillsCmd(opts *rootOptions, stdout io.Writer) *cobra.Command {
 	}
 	addSkillDirFlag(init)
 
+	newCmd := &cobra.Command{
+		Use:     "new [skill-dir]",
+		Short:   "Create a skill with the Quick Skill Builder",
+		Long:    "Creates a skill from a template for its type: summarizer, reviewer, extractor or planner. In a terminal a wizard asks for the directory, type, description, inputs and outputs; with --type the skill is created from flags instead. The inputs and outputs become the JSON schemas, prompt.md is drafted from the template, and a starter fixture is written. The new skill is then linted and tested. Fields are written as name[?]:type:description, where type is string, number, integer, boolean or array and ? marks an optional field.",
+		Example: "  aios skills new\n  aios skills new ./skills/roadmap-summary --type summarizer\n  aios skills new ./skills/ticket-triage --type reviewer --input-field \"ticket:string:The support ticket\" --input-field \"priority?:integer\" --output-field \"reply:string:A reply to send\" --output-field escalate:boolean",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-new", argOrFlag(cmd, args, ""), skillFlags(cmd))
+		},
+	}
+	newCmd.Flags().String("type", "", "skill type: summarizer, reviewer, extractor or planner (skips the wizard)")
+	newCmd.Flags().String("description", "", "skill description; defaults to the type's")
+	newCmd.Flags().StringArray("input-field", nil, "input field as name[?]:type:description (repeatable); defaults to the type's")
+	newCmd.Flags().StringArray("output-field", nil, "output field as name[?]:type:description (repeatable); defaults to the type's")
+
 	sync := &cobra.Command{
 		Use:     "sync <skill-dir>",
 		Short:   "Sync a skill to agents",
-		Long:    "Synchronizes a skill to all configured agent directories, creating symlinks and updating registry.",
-		Example: "  aios skills sync ./my-skill\n  aios skills sync ~/skills/ddd-expert",
+		Long:    "Synchronizes a skill to all configured agent directories, creating symlinks and updating registry. With --global the skill is installed into each detected agent's user-level skills directory instead of the project. With --all-projects or --project it is installed into tracked projects instead, several at a time, followed by a summary of successes and failures.",
+		Example: "  aios skills sync ./my-skill\n  aios skills sync ~/skills/ddd-expert\n  aios skills sync ./my-skill --global\n  aios skills sync ./my-skill --force\n  aios skills sync ./my-skill --agents claude-code,cursor\n  aios skills sync ./review --all-projects --parallel 8\n  aios skills sync ./review --project ~/src/api --project ~/src/web",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir, err := requireArgOrFlag(cmd, args, "skill-dir", "skill-dir")
 			if err != nil {
 				return err
 			}
-			return runCLI(cmd.Context(), stdout, opts, "sync", skillDir, defaultMCPTransport, defaultMCPAddr)
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "sync", skillDir, skillFlags(cmd))
 		},
 	}
 	addSkillDirFlag(sync)
+	addGlobalFlag(sync)
+	addForceFlag(sync)
+	addAgentsFlag(sync)
+	sync.Flags().Bool("all-projects", false, "sync into every tracked project")
+	sync.Flags().StringSlice("project", nil, "sync into these tracked projects (id or path, repeatable)")
+	sync.Flags().Int("parallel", 0, "number of projects to sync concurrently with --all-projects or --project (default 4)")
 
 	plan := &cobra.Command{
 		Use:     "plan <skill-dir>",
 		Short:   "Plan skill writes",
 		Long:    "Shows what files would be written to agent directories without making changes (dry-run).",
-		Example: "  aios skills plan ./my-skill",
+		Example: "  aios skills plan ./my-skill\n  aios skills plan ./my-skill --global\n  aios skills plan ./my-skill --agents cursor",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir, err := requireArgOrFlag(cmd, args, "skill-dir", "skill-dir")
 			if err != nil {
 				return err
 			}
-			return runCLI(cmd.Context(), stdout, opts, "sync-plan", skillDir, defaultMCPTransport, defaultMCPAddr)
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "sync-plan", skillDir, skillFlags(cmd))
 		},
 	}
 	addSkillDirFlag(plan)
+	addGlobalFlag(plan)
+	addAgentsFlag(plan)
 
 	testCmd := &cobra.Command{
 		Use:     "test <skill-dir>",
 		Short:   "Run skill fixture suite",
-		Long:    "Executes the skill's fixture test suite to validate skill behavior.",
-		Example: "  aios skills test ./my-skill",
+		Long:    "Executes the skill's fixture test suite to validate skill behavior. With --update each fixture's output is written to its expected file, showing a diff of what changed; with --check the command fails when an expected file no longer matches the output. Expected files that use assertions are never rewritten. --recursive tests every skill under the directory, --parallel runs several fixtures at once, and --report writes JUnit XML or TAP reports for CI.",
+		Example: "  aios skills test ./my-skill\n  aios skills test ./my-skill --update\n  aios skills test ./my-skill --check\n  aios skills test ./skills --recursive --parallel 4 --timeout 30s\n  aios skills test ./skills --recursive --report junit=reports/skills.xml\n  aios skills test ./my-skill --report tap",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir, err := requireArgOrFlag(cmd, args, "skill-dir", "skill-dir")
 			if err != nil {
 				return err
 			}
-			return runCLI(cmd.Context(), stdout, opts, "test-skill", skillDir, defaultMCPTransport, defaultMCPAddr)
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "test-skill", skillDir, skillFlags(cmd))
 		},
 	}
 	addSkillDirFlag(testCmd)
+	testCmd.Flags().Bool("update", false, "write fixture outputs to their expected files")
+	testCmd.Flags().Bool("check", false, "fail when expected files do not match fixture outputs")
+	testCmd.Flags().Bool("recursive", false, "test every skill under the directory")
+	testCmd.Flags().Int("parallel", 1, "number of fixtures to run at once")
+	testCmd.Flags().Duration("timeout", 0, "fail a fixture that runs longer than this (e.g. 30s); 0 means no limit")
+	testCmd.Flags().StringArray("report", nil, "write a report: junit[=path] or tap[=path]; without a path it goes to stdout (repeatable)")
 
 	lint := &cobra.Command{
 		Use:     "lint <skill-dir>",
 		Short:   "Lint a skill",
-		Long:    "Validates skill structure, SKILL.md syntax, and fixture consistency.",
-		Example: "  aios skills lint ./my-skill\n  aios skills lint ./my-skill --fix",
+		Long:    "Validates skill structure, SKILL.md syntax, and fixture consistency. Every finding names the rule that reported it, its severity and, where known, its file and line. Rules can be turned off or given another severity in a .aioslint.yaml in the skill directory or a parent. --fix repairs what the rules know how to, such as a missing expected_ file or a non-semver version.",
+		Example: "  aios skills lint ./my-skill\n  aios skills lint ./my-skill --fix\n  aios skills lint ./my-skill --format sarif > lint.sarif",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir, err := requireArgOrFlag(cmd, args, "skill-dir", "skill-dir")
 			if err != nil {
 				return err
 			}
-			return runCLI(cmd.Context(), stdout, opts, "lint-skill", skillDir, defaultMCPTransport, defaultMCPAddr)
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "lint-skill", skillDir, skillFlags(cmd))
 		},
 	}
 	addSkillDirFlag(lint)
+	lint.Flags().Bool("fix", false, "repair fixable findings before reporting")
+	lint.Flags().String("format", "", "output format: text, json or sarif (defaults to --output)")
 
 	packageCmd := &cobra.Command{
 		Use:     "package <skill-dir>",
 		Short:   "Package a skill",
-		Long:    "Creates a distributable package (.tar.gz) of the skill for sharing or publishing.",
+		Long:    "Creates a reproducible zip of the skill for sharing or publishing, with a MANIFEST.json holding the skill's metadata and the SHA-256 of every file. Dot files, editor swap files, earlier zips and paths listed in .aiosignore are left out.",
 		Example: "  aios skills package ./my-skill",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -426,20 +466,119 @@ func newSkillsCmd(opts *rootOptions, stdout io.Writer) *cobra.Command {
 	uninstall := &cobra.Command{
 		Use:     "uninstall <skill-dir>",
 		Short:   "Uninstall a skill",
-		Long:    "Removes a skill from all agent directories, cleaning up symlinks and registry entries.",
-		Example: "  aios skills uninstall ./my-skill\n  aios skills uninstall ddd-expert",
+		Long:    "Removes a skill from all agent directories, cleaning up symlinks and registry entries. With --agents only the named agents' entries are removed; the skill stays installed for the others.",
+		Example: "  aios skills uninstall ./my-skill\n  aios skills uninstall ddd-expert\n  aios skills uninstall ./my-skill --global\n  aios skills uninstall ./my-skill --agents cursor",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir, err := requireArgOrFlag(cmd, args, "skill-dir", "skill-dir")
 			if err != nil {
 				return err
 			}
-			return runCLI(cmd.Context(), stdout, opts, "uninstall-skill", skillDir, defaultMCPTransport, defaultMCPAddr)
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "uninstall-skill", skillDir, skillFlags(cmd))
 		},
 	}
 	addSkillDirFlag(uninstall)
+	addGlobalFlag(uninstall)
+	addForceFlag(uninstall)
+	addAgentsFlag(uninstall)
+
+	status := &cobra.Command{
+		Use:     "status",
+		Short:   "Compare installed skills with the lockfile",
+		Long:    "Compares the skills installed in the project against .agents/skills.lock, reporting modified or missing files, broken agent links and untracked skills. Exits non-zero when anything differs.",
+		Example: "  aios skills status\n  aios skills status --output json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLI(cmd.Context(), stdout, opts, "skills-status", "", defaultMCPTransport, defaultMCPAddr)
+		},
+	}
+
+	install := &cobra.Command{
+		Use:     "install",
+		Short:   "Install skills recorded in the lockfile",
+		Long:    "Reinstalls every skill recorded in .agents/skills.lock from its source directory. With --frozen the install fails if a source no longer matches its locked version or content hashes, and the lockfile is left untouched.",
+		Example: "  aios skills install\n  aios skills install --frozen",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-install", "", skillFlags(cmd))
+		},
+	}
+	install.Flags().Bool("frozen", false, "fail instead of updating the lockfile when sources have changed")
+
+	unpack := &cobra.Command{
+		Use:     "unpack <package.zip> [dest-dir]",
+		Short:   "Unpack a skill package",
+		Long:    "Verifies a skill package made by 'aios skills package' and extracts it. Every entry must be listed in MANIFEST.json with a matching SHA-256, no entry may point outside the destination, and the skill spec must validate; otherwise nothing is written. Without a destination the skill is unpacked into a directory named after it beside the package.",
+		Example: "  aios skills unpack roadmap-reader-0.1.0.zip\n  aios skills unpack roadmap-reader-0.1.0.zip ./skills/roadmap-reader",
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := skillFlags(cmd)
+			if len(args) == 2 {
+				flags.Dest = args[1]
+			}
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-unpack", args[0], flags)
+		},
+	}
+
+	verifyPackage := &cobra.Command{
+		Use:     "verify-package <package.zip>",
+		Short:   "Verify a skill package",
+		Long:    "Checks a skill package without installing it: entry paths, the SHA-256 of every file listed in MANIFEST.json, and the skill spec.",
+		Example: "  aios skills verify-package roadmap-reader-0.1.0.zip",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLI(cmd.Context(), stdout, opts, "skills-verify-package", args[0], defaultMCPTransport, defaultMCPAddr)
+		},
+	}
+
+	prune := &cobra.Command{
+		Use:     "prune",
+		Short:   "Remove orphaned skill entries",
+		Long:    "Finds skill entries left behind by removed or partial installs: agent symlinks whose canonical skill directory is gone, aios-managed copies whose canonical source was removed, and aios-managed canonical skills that are not in the lockfile and that no agent links to. Lists them by default; --apply removes them. Hand-written skills are never touched.",
+		Example: "  aios skills prune\n  aios skills prune --apply\n  aios skills prune --global --apply",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-prune", "", skillFlags(cmd))
+		},
+	}
+	addGlobalFlag(prune)
+	prune.Flags().Bool("apply", false, "remove the entries found instead of only listing them")
+
+	scan := &cobra.Command{
+		Use:     "scan [skill-dir]",
+		Short:   "Scan skills for embedded secrets",
+		Long:    "Scans a skill directory for credentials with provider-specific detectors and a Shannon-entropy check on string literals, and reports each finding with its file, line and a redacted excerpt. Findings accepted in the skill's .aios-secrets-baseline.json are not reported; --update-baseline accepts the current findings. Without a directory, every installed skill is scanned against the baseline of its source. Publishing runs the same scan.",
+		Example: "  aios skills scan ./skills/roadmap-reader\n  aios skills scan ./skills/roadmap-reader --update-baseline\n  aios skills scan\n  aios skills scan --global",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := ""
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-scan", dir, skillFlags(cmd))
+		},
+	}
+	addGlobalFlag(scan)
+	scan.Flags().Bool("update-baseline", false, "accept the current findings in .aios-secrets-baseline.json")
+
+	schema := &cobra.Command{
+		Use:     "schema",
+		Short:   "Skill schema commands",
+		Long:    "Work with a skill's input and output JSON schemas.",
+		Example: "  aios skills schema infer ./skills/roadmap-reader",
+	}
+	inferSchema := &cobra.Command{
+		Use:     "infer <skill-dir>",
+		Short:   "Infer schemas from fixtures",
+		Long:    "Infers the input schema from the skill's tests/fixture_*.json files and the output schema from its tests/expected_*.json files, and shows how they differ from the schema files named in skill.yaml. Properties get the types seen in the samples and are required when every sample has them; nested objects and arrays are inferred the same way, strings that repeat a few values become enums, and descriptions in the current schemas are kept. Expected files that use assertions are skipped. --write replaces the schema files.",
+		Example: "  aios skills schema infer ./skills/roadmap-reader\n  aios skills schema infer ./skills/roadmap-reader --write",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCLIWithFlags(cmd.Context(), stdout, opts, "skills-schema-infer", args[0], skillFlags(cmd))
+		},
+	}
+	inferSchema.Flags().Bool("write", false, "write the inferred schemas to the schema files")
+	schema.AddCommand(inferSchema)
 
-	cmd.AddCommand(init, sync, plan, testCmd, lint, packageCmd, uninstall)
+	cmd.AddCommand(init, newCmd, sync, plan, testCmd, lint, packageCmd, unpack, verifyPackage, scan, schema, uninstall, status, install, prune)
 	return cmd
 }
 
@@ -621,6 +760,66 @@ func addSkillDirFlag(cmd *cobra.Command) {
 	_ = cmd.Flags().MarkDeprecated("skill-dir", "use positional argument instead")
 }
 
+func addGlobalFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("global", false, "use user-level agent skill directories instead of the project")
+}
+
+func addForceFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("force", false, "replace or remove skill entries not created by aios, after backing them up")
+}
+
+func addAgentsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("agents", nil, "only target these agents (comma-separated names, overrides the skill's clients)")
+}
+
+// skillFlags collects the optional skill flags registered on cmd.
+func skillFlags(cmd *cobra.Command) core.CommandFlags {
+	global, _ := cmd.Flags().GetBool("global")
+	agentNames, _ := cmd.Flags().GetStringSlice("agents")
+	force, _ := cmd.Flags().GetBool("force")
+	allProjects, _ := cmd.Flags().GetBool("all-projects")
+	projects, _ := cmd.Flags().GetStringSlice("project")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	frozen, _ := cmd.Flags().GetBool("frozen")
+	apply, _ := cmd.Flags().GetBool("apply")
+	update, _ := cmd.Flags().GetBool("update")
+	check, _ := cmd.Flags().GetBool("check")
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	reports, _ := cmd.Flags().GetStringArray("report")
+	fix, _ := cmd.Flags().GetBool("fix")
+	format, _ := cmd.Flags().GetString("format")
+	updateBaseline, _ := cmd.Flags().GetBool("update-baseline")
+	skillType, _ := cmd.Flags().GetString("type")
+	description, _ := cmd.Flags().GetString("description")
+	inputFields, _ := cmd.Flags().GetStringArray("input-field")
+	outputFields, _ := cmd.Flags().GetStringArray("output-field")
+	write, _ := cmd.Flags().GetBool("write")
+	return core.CommandFlags{
+		Global:         global,
+		Agents:         agentNames,
+		Force:          force,
+		AllProjects:    allProjects,
+		Projects:       projects,
+		Parallel:       parallel,
+		Frozen:         frozen,
+		Apply:          apply,
+		Update:         update,
+		Check:          check,
+		Recursive:      recursive,
+		Timeout:        timeout,
+		Reports:        reports,
+		Fix:            fix,
+		Format:         format,
+		UpdateBaseline: updateBaseline,
+		SkillType:      skillType,
+		Description:    description,
+		InputFields:    inputFields,
+		OutputFields:   outputFields,
+		Write:          write,
+	}
+}
+
 func addSkillIDFlag(cmd *cobra.Command) {
 	cmd.Flags().String("skill-id", "", "skill identifier")
 	_ = cmd.Flags().MarkDeprecated("skill-id", "use positional argument instead")
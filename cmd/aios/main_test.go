@@ -3,10 +3,29 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
 	"testing"
 )
 
+// TestMain runs the tests from a temporary directory, so the workspace and
+// project state the commands write never lands in the package directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "aios-cmd-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestMainCallsRun(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -520,6 +539,19 @@ func TestRootNoArgsShowsHelp(t *testing.T) {
 	}
 }
 
+func TestMCPServePassesTransportToCLI(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	code := run([]string{"mcp", "serve", "--transport", "carrier-pigeon", "--addr", ":9999"}, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `unsupported mcp transport "carrier-pigeon"`) {
+		t.Fatalf("expected the --transport value to reach the CLI, got %q", stderr.String())
+	}
+}
+
 type testError struct {
 	msg string
 }
@@ -0,0 +1,190 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+// ArtifactMarker is inserted into every rendered artifact, after any
+// frontmatter, so aios can tell the rule files it wrote from hand-written
+// ones.
+const ArtifactMarker = "<!-- managed by aios; changes are overwritten on sync -->"
+
+// Artifact is an agent-native file rendered from a skill, such as a Cursor
+// .mdc rule, written in addition to the agent's skills entry.
+type Artifact struct {
+	// Path is the project-relative, slash-separated location of the file.
+	Path    string
+	Content string
+}
+
+// plannedArtifact is an artifact resolved against the project, with the
+// managed marker applied.
+type plannedArtifact struct {
+	// agents are the agents the artifact was rendered for, in target order.
+	agents  []string
+	rel     string
+	path    string
+	content string
+}
+
+// planArtifacts resolves the artifacts of every target agent. Global
+// installs write none: rule formats are read from the project only.
+// Agents sharing a path must render identical content.
+func planArtifacts(opts InstallOptions, targets []agentregistry.AgentDefinition) ([]plannedArtifact, error) {
+	if opts.Global || len(opts.Artifacts) == 0 {
+		return nil, nil
+	}
+	byPath := make(map[string]plannedArtifact)
+	for _, agent := range targets {
+		for _, a := range opts.Artifacts[agent.Name] {
+			rel := path.Clean(a.Path)
+			if a.Path == "" || path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+				return nil, fmt.Errorf("artifact path %q for %s must be inside the project", a.Path, agent.DisplayName)
+			}
+			planned := plannedArtifact{
+				agents:  []string{agent.Name},
+				rel:     rel,
+				path:    filepath.Join(opts.ProjectDir, filepath.FromSlash(rel)),
+				content: markArtifact(a.Content),
+			}
+			if prev, ok := byPath[rel]; ok {
+				if prev.content != planned.content {
+					return nil, fmt.Errorf("agents %s and %s render different content to %s", prev.agents[0], agent.Name, rel)
+				}
+				prev.agents = append(prev.agents, agent.Name)
+				byPath[rel] = prev
+				continue
+			}
+			byPath[rel] = planned
+		}
+	}
+	out := make([]plannedArtifact, 0, len(byPath))
+	for _, a := range byPath {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].rel < out[j].rel })
+	return out, nil
+}
+
+// artifactHashes returns the lockfile digests of planned artifacts.
+func artifactHashes(planned []plannedArtifact) map[string]string {
+	if len(planned) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(planned))
+	for _, a := range planned {
+		out[a.rel] = hashBytes([]byte(a.content))
+	}
+	return out
+}
+
+// artifactOwners maps each agent to the artifacts rendered for it.
+func artifactOwners(planned []plannedArtifact) map[string][]string {
+	if len(planned) == 0 {
+		return nil
+	}
+	out := make(map[string][]string)
+	for _, a := range planned {
+		for _, agent := range a.agents {
+			out[agent] = append(out[agent], a.rel)
+		}
+	}
+	return out
+}
+
+// markArtifact inserts ArtifactMarker after the frontmatter of content, or
+// at the top when there is none, so frontmatter stays on the first line
+// where agents expect it.
+func markArtifact(content string) string {
+	const fence = "---\n"
+	if strings.HasPrefix(content, fence) {
+		rest := content[len(fence):]
+		end := -1
+		if strings.HasPrefix(rest, fence) {
+			end = 0
+		} else if i := strings.Index(rest, "\n"+fence); i >= 0 {
+			end = i + 1
+		}
+		if end >= 0 {
+			split := len(fence) + end + len(fence)
+			return content[:split] + ArtifactMarker + "\n" + content[split:]
+		}
+	}
+	return ArtifactMarker + "\n" + content
+}
+
+// isManagedArtifact reports whether the file at p carries ArtifactMarker.
+func isManagedArtifact(p string) (exists, managed bool) {
+	info, err := os.Lstat(p)
+	if err != nil {
+		return false, false
+	}
+	if !info.Mode().IsRegular() {
+		return true, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return true, false
+	}
+	return true, strings.Contains(string(data), ArtifactMarker)
+}
+
+// swapArtifact stages a rendered artifact beside its target and swaps it in.
+func (p swapPlan) swapArtifact(tx *installTxn, a plannedArtifact) error {
+	dir := filepath.Dir(a.path)
+	if err := tx.mkdirAll(dir); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(a.path)+".aios-staging-")
+	if err != nil {
+		return fmt.Errorf("staging %s: %w", a.rel, err)
+	}
+	staged := f.Name()
+	defer func() { _ = os.Remove(staged) }()
+	_, err = f.WriteString(a.content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(staged, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("staging %s: %w", a.rel, err)
+	}
+	keep, err := p.keepAt(tx, a.path)
+	if err != nil {
+		return err
+	}
+	if err := tx.replace(staged, a.path, CommittedEntry{Agent: a.agents[0], Path: a.path, LinkType: LinkRendered}, keep); err != nil {
+		return fmt.Errorf("writing %s: %w", a.rel, err)
+	}
+	return nil
+}
+
+// staleArtifacts returns the managed artifacts a previous install of the
+// skill recorded that the new install no longer writes.
+func staleArtifacts(projectDir string, previous map[string]string, planned []plannedArtifact) []string {
+	keep := make(map[string]bool, len(planned))
+	for _, a := range planned {
+		keep[a.rel] = true
+	}
+	var out []string
+	for rel := range previous {
+		if keep[rel] {
+			continue
+		}
+		p := filepath.Join(projectDir, filepath.FromSlash(rel))
+		if exists, managed := isManagedArtifact(p); exists && managed {
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,203 @@
+package agents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func cursorArtifacts(content string) map[string][]Artifact {
+	return map[string][]Artifact{
+		"cursor": {{Path: ".cursor/rules/review.mdc", Content: content}},
+	}
+}
+
+func TestMarkArtifact(t *testing.T) {
+	cases := map[string]string{
+		"---\na: b\n---\n\nbody\n": "---\na: b\n---\n" + ArtifactMarker + "\n\nbody\n",
+		"---\n---\nbody\n":         "---\n---\n" + ArtifactMarker + "\nbody\n",
+		"# plain\n":                ArtifactMarker + "\n# plain\n",
+	}
+	for in, want := range cases {
+		if got := markArtifact(in); got != want {
+			t.Errorf("markArtifact(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInstallSkill_WritesRenderedArtifacts(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+
+	result, err := si.InstallSkill("review", InstallOptions{
+		ProjectDir: tmp,
+		Artifacts:  cursorArtifacts("---\nglobs: *.go\n---\n\n# Review\n"),
+	})
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	path := filepath.Join(tmp, ".cursor", "rules", "review.mdc")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected rendered artifact: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\nglobs: *.go\n---\n"+ArtifactMarker+"\n") {
+		t.Errorf("expected marker after frontmatter, got:\n%s", data)
+	}
+	last := result.Committed[len(result.Committed)-1]
+	if last.Path != path || last.LinkType != LinkRendered || last.Agent != "cursor" {
+		t.Errorf("unexpected committed entry: %#v", last)
+	}
+
+	lf, err := ReadLockfile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := lf.Find("review")
+	if entry.Artifacts[".cursor/rules/review.mdc"] != hashBytes(data) {
+		t.Errorf("expected artifact hash in lockfile, got %#v", entry.Artifacts)
+	}
+}
+
+func TestInstallSkill_ArtifactsOnlyForTargetAgents(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	opts := InstallOptions{
+		ProjectDir:   tmp,
+		TargetAgents: testAgentDefs()[:1],
+		Artifacts:    cursorArtifacts("# Review\n"),
+	}
+	if _, err := si.InstallSkill("review", opts); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".cursor", "rules", "review.mdc")); !os.IsNotExist(err) {
+		t.Error("expected no artifact for an agent outside the targets")
+	}
+}
+
+func TestInstallSkill_GlobalSkipsArtifacts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	si := NewSkillInstaller(testAgentDefs())
+
+	plan, err := si.PlanInstall("review", InstallOptions{Global: true, Artifacts: cursorArtifacts("# Review\n")})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	for _, f := range plan.Files {
+		if strings.HasSuffix(f, ".mdc") {
+			t.Errorf("expected no artifacts in a global plan, got %v", plan.Files)
+		}
+	}
+}
+
+func TestInstallSkill_RefusesHandWrittenRule(t *testing.T) {
+	tmp := t.TempDir()
+	rule := filepath.Join(tmp, ".cursor", "rules", "review.mdc")
+	if err := os.MkdirAll(filepath.Dir(rule), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rule, []byte("my rule"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	si := NewSkillInstaller(testAgentDefs())
+	opts := InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}
+
+	_, err := si.InstallSkill("review", opts)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Paths) != 1 || conflict.Paths[0] != rule {
+		t.Fatalf("expected conflict on %s, got %v", rule, err)
+	}
+
+	opts.Force = true
+	result, err := si.InstallSkill("review", opts)
+	if err != nil {
+		t.Fatalf("forced install: %v", err)
+	}
+	var backup string
+	for _, c := range result.Committed {
+		if c.Path == rule {
+			backup = c.BackupPath
+		}
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != "my rule" {
+		t.Errorf("expected hand-written rule backed up, got %q (%v)", data, err)
+	}
+}
+
+func TestInstallSkill_RemovesStaleArtifacts(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatal(err)
+	}
+	// The cursor renderer was switched off.
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".cursor", "rules", "review.mdc")); !os.IsNotExist(err) {
+		t.Error("expected stale artifact to be removed")
+	}
+	lf, _ := ReadLockfile(ProjectLockfilePath(tmp))
+	if entry, _ := lf.Find("review"); len(entry.Artifacts) != 0 {
+		t.Errorf("expected no artifacts recorded, got %#v", entry.Artifacts)
+	}
+}
+
+func TestInstallSkill_RejectsArtifactOutsideProject(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	opts := InstallOptions{
+		ProjectDir: tmp,
+		Artifacts:  map[string][]Artifact{"cursor": {{Path: "../escape.md", Content: "x"}}},
+	}
+	if _, err := si.InstallSkill("review", opts); err == nil {
+		t.Fatal("expected error for artifact path outside the project")
+	}
+}
+
+func TestUninstall_RemovesArtifacts(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatal(err)
+	}
+	rule := filepath.Join(tmp, ".cursor", "rules", "review.mdc")
+
+	result, err := si.Uninstall("review", UninstallOptions{ProjectDir: tmp})
+	if err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if _, err := os.Stat(rule); !os.IsNotExist(err) {
+		t.Error("expected rendered artifact to be removed")
+	}
+	if len(result.Removed) == 0 || result.Removed[0] != rule {
+		t.Errorf("expected artifact first in removed entries, got %v", result.Removed)
+	}
+}
+
+func TestStatus_ReportsModifiedArtifact(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatal(err)
+	}
+	rule := filepath.Join(tmp, ".cursor", "rules", "review.mdc")
+	if err := os.WriteFile(rule, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := si.Status(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].State != StatusModified {
+		t.Fatalf("expected modified status, got %#v", statuses)
+	}
+	if statuses[0].ArtifactIssues[".cursor/rules/review.mdc"] != StatusModified {
+		t.Errorf("unexpected artifact issues: %#v", statuses[0].ArtifactIssues)
+	}
+}
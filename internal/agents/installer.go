@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
@@ -38,6 +39,93 @@ type InstallOptions struct {
 	// this replaces the default stub marker. Typically composed from
 	// skill.yaml metadata and prompt.md body by the caller.
 	SkillContent string
+
+	// SourceDir and Files name the supporting files (scripts, references,
+	// assets) copied next to SKILL.md, as slash-separated paths relative to
+	// SourceDir. When SourceDir is set the canonical directory is rebuilt
+	// from scratch, so files dropped from the skill are removed.
+	SourceDir string
+	Files     []string
+
+	// Generated are files composed at install time and written next to
+	// SKILL.md, such as the reference files of optional prompt sections,
+	// keyed by slash-separated path relative to the skill directory.
+	Generated map[string]string
+
+	// Artifacts are agent-native files rendered from the skill, keyed by
+	// agent name. Each target agent with an entry also gets those files
+	// written inside ProjectDir. Ignored for global installs.
+	Artifacts map[string][]Artifact
+
+	// Global installs into the user-level canonical directory
+	// (~/.agents/skills) and each agent's global skills directory instead
+	// of ProjectDir, which is then ignored.
+	Global bool
+
+	// Version and Source are recorded in the lockfile entry for the skill.
+	// Source is the skill directory or registry reference installed from.
+	Version string
+	Source  string
+
+	// Locked pins the install to an existing lockfile entry: the content
+	// to be written must hash to Locked.Files and Locked.Artifacts, and the
+	// lockfile is left unchanged. Used for frozen installs.
+	Locked *LockedSkill
+
+	// Force replaces existing entries that aios did not create. Their
+	// content is first moved to a timestamped directory under
+	// .agents/backups. Without Force such entries fail the install with a
+	// *ConflictError.
+	Force bool
+}
+
+// installLayout resolves the canonical skills root and the per-agent skill
+// roots for either a project or the user's global skill directories.
+type installLayout struct {
+	canonicalRoot string
+
+	// agentRoot returns the directory an agent reads skills from and whether
+	// that directory needs a link to the canonical entry. An empty dir means
+	// the agent has no usable location in this layout and is skipped.
+	agentRoot func(agent agentregistry.AgentDefinition) (dir string, linked bool)
+}
+
+// projectLayout places skills under projectDir. Universal agents read the
+// canonical directory directly; every other agent gets a link.
+func projectLayout(projectDir string) installLayout {
+	canonicalRoot := filepath.Join(projectDir, agentregistry.CanonicalSkillsDir)
+	return installLayout{
+		canonicalRoot: canonicalRoot,
+		agentRoot: func(agent agentregistry.AgentDefinition) (string, bool) {
+			if agent.Universal {
+				return canonicalRoot, false
+			}
+			return filepath.Join(projectDir, agent.SkillsDir), true
+		},
+	}
+}
+
+// globalLayout places skills under the user's home directory. Universal
+// agents do not share a global directory, so every agent whose resolved
+// global skills dir differs from the canonical root gets a link.
+func globalLayout() (installLayout, error) {
+	canonicalRoot := ResolveGlobalCanonicalSkillsDir()
+	if canonicalRoot == "" {
+		return installLayout{}, fmt.Errorf("resolving home directory for global skills")
+	}
+	return installLayout{
+		canonicalRoot: canonicalRoot,
+		agentRoot: func(agent agentregistry.AgentDefinition) (string, bool) {
+			dir := ResolveGlobalSkillsDir(agent)
+			if dir == "" {
+				return "", false
+			}
+			if filepath.Clean(dir) == filepath.Clean(canonicalRoot) {
+				return canonicalRoot, false
+			}
+			return dir, true
+		},
+	}, nil
 }
 
 // InstallResult represents the outcome of a skill installation.
@@ -50,144 +138,754 @@ type InstallResult struct {
 
 	// Agents lists the display names of agents that received the skill.
 	Agents []string
+
+	// Committed lists every entry swapped into place, canonical directory
+	// first, in commit order.
+	Committed []CommittedEntry
 }
 
 // InstallSkill installs a skill to the canonical location and creates symlinks
-// for non-universal agents. The skillID is used as the directory name (sanitized).
+// for agents that do not read it directly. The skillID is used as the
+// directory name (sanitized). With opts.Global the user-level layout is used.
+//
+// The install is transactional: the canonical directory and each agent
+// entry are staged beside their targets and swapped in with renames. If any
+// step fails, every entry already swapped in is replaced by what was there
+// before and an *InstallError is returned.
 func (si *SkillInstaller) InstallSkill(skillID string, opts InstallOptions) (*InstallResult, error) {
 	if skillID == "" {
 		return nil, fmt.Errorf("skill id is required")
 	}
-	if opts.ProjectDir == "" {
-		return nil, fmt.Errorf("project directory is required")
+	var layout installLayout
+	if opts.Global {
+		l, err := globalLayout()
+		if err != nil {
+			return nil, err
+		}
+		layout = l
+	} else {
+		if opts.ProjectDir == "" {
+			return nil, fmt.Errorf("project directory is required")
+		}
+		layout = projectLayout(opts.ProjectDir)
 	}
 
 	sanitized := SanitizeName(skillID)
-	canonicalDir := filepath.Join(opts.ProjectDir, agentregistry.CanonicalSkillsDir, sanitized)
+	canonicalDir := filepath.Join(layout.canonicalRoot, sanitized)
 
-	// Create canonical directory.
-	if err := os.MkdirAll(canonicalDir, 0o755); err != nil {
-		return nil, fmt.Errorf("creating canonical dir: %w", err)
+	// Determine target agents.
+	targets := si.agents
+	if len(opts.TargetAgents) > 0 {
+		targets = opts.TargetAgents
+	}
+	artifacts, err := planArtifacts(opts, targets)
+	if err != nil {
+		return nil, err
 	}
 
-	// Write skill marker file. If SkillContent is provided, always write it
-	// (overwriting any previous stub). Otherwise, write a default stub only
-	// when no SKILL.md exists yet.
-	markerPath := filepath.Join(canonicalDir, "SKILL.md")
-	if opts.SkillContent != "" {
-		if err := os.WriteFile(markerPath, []byte(opts.SkillContent), 0o644); err != nil {
-			return nil, fmt.Errorf("writing SKILL.md: %w", err)
+	if opts.Locked != nil {
+		if err := verifyLocked(skillID, opts, artifacts); err != nil {
+			return nil, err
 		}
-	} else if _, err := os.Stat(markerPath); os.IsNotExist(err) {
-		content := fmt.Sprintf("---\nname: %s\ndescription: \"\"\n---\n", skillID)
-		if err := os.WriteFile(markerPath, []byte(content), 0o644); err != nil {
-			return nil, fmt.Errorf("writing SKILL.md: %w", err)
+	}
+
+	unmanaged, err := unmanagedEntries(layout, skillID, linkPaths(layout, targets, filepath.Base(canonicalDir)))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range artifacts {
+		if exists, managed := isManagedArtifact(a.path); exists && !managed {
+			unmanaged = append(unmanaged, a.path)
 		}
 	}
+	sort.Strings(unmanaged)
+	if len(unmanaged) > 0 && !opts.Force {
+		return nil, &ConflictError{SkillID: skillID, Paths: unmanaged}
+	}
 
-	// Determine target agents.
-	targets := si.agents
-	if len(opts.TargetAgents) > 0 {
-		targets = opts.TargetAgents
+	tx := &installTxn{}
+	plan := swapPlan{
+		layout:       layout,
+		skillID:      skillID,
+		canonicalDir: canonicalDir,
+		targets:      targets,
+		known:        si.agents,
+		unmanaged:    make(map[string]bool, len(unmanaged)),
+		backups:      newBackupSet(layout),
+	}
+	for _, p := range unmanaged {
+		plan.unmanaged[p] = true
+	}
+	installedAgents, linkTypes, err := plan.stageAndSwap(tx, opts)
+	if err == nil {
+		err = plan.swapArtifacts(tx, artifacts)
+	}
+	if err == nil {
+		err = plan.removeStale(tx, opts, linkTypes, artifacts)
 	}
+	if err == nil && opts.Locked == nil {
+		entry := LockedSkill{
+			ID:             skillID,
+			Version:        opts.Version,
+			Source:         opts.Source,
+			Agents:         linkTypes,
+			Artifacts:      artifactHashes(artifacts),
+			ArtifactAgents: artifactOwners(artifacts),
+		}
+		if lockErr := recordInstall(layout, entry, canonicalDir); lockErr != nil {
+			err = fmt.Errorf("updating lockfile: %w", lockErr)
+		}
+	}
+	if err != nil {
+		return nil, &InstallError{SkillID: skillID, Err: err, RollbackErrors: tx.rollback()}
+	}
+
+	return &InstallResult{
+		SkillID:       skillID,
+		CanonicalPath: canonicalDir,
+		Agents:        installedAgents,
+		Committed:     tx.commit(),
+	}, nil
+}
 
-	// Create symlinks for non-universal agents.
+// swapPlan holds what a single install swaps into place.
+type swapPlan struct {
+	layout       installLayout
+	skillID      string
+	canonicalDir string
+	targets      []agentregistry.AgentDefinition
+	known        []agentregistry.AgentDefinition
+	unmanaged    map[string]bool
+	backups      *backupSet
+}
+
+// keepAt returns the backup location for target when it is user-owned.
+func (p swapPlan) keepAt(tx *installTxn, target string) (string, error) {
+	if !p.unmanaged[target] {
+		return "", nil
+	}
+	return p.backups.pathFor(tx, target)
+}
+
+// stageAndSwap swaps in the canonical directory and then each agent entry,
+// recording every step in tx.
+func (p swapPlan) stageAndSwap(tx *installTxn, opts InstallOptions) ([]string, map[string]string, error) {
+	if err := tx.mkdirAll(p.layout.canonicalRoot); err != nil {
+		return nil, nil, fmt.Errorf("creating canonical dir: %w", err)
+	}
+	stage, err := stagingDir(p.canonicalDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating staging dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stage) }()
+
+	staged := filepath.Join(stage, "entry")
+	carryOver := opts.SourceDir == "" && !p.unmanaged[p.canonicalDir]
+	if err := stageCanonical(staged, p.canonicalDir, p.skillID, opts, carryOver); err != nil {
+		return nil, nil, err
+	}
+	keep, err := p.keepAt(tx, p.canonicalDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.replace(staged, p.canonicalDir, CommittedEntry{LinkType: LinkCanonical}, keep); err != nil {
+		return nil, nil, err
+	}
+
+	// Link agents that do not read the canonical dir.
 	var installedAgents []string
-	for _, agent := range targets {
-		if agent.Universal {
+	linkTypes := make(map[string]string, len(p.targets))
+	for _, agent := range p.targets {
+		agentSkillDir, linked := p.layout.agentRoot(agent)
+		if agentSkillDir == "" {
+			continue
+		}
+		if !linked {
 			installedAgents = append(installedAgents, agent.DisplayName)
+			linkTypes[agent.Name] = LinkCanonical
 			continue
 		}
 
-		agentSkillDir := filepath.Join(opts.ProjectDir, agent.SkillsDir)
-		if err := os.MkdirAll(agentSkillDir, 0o755); err != nil {
-			return nil, fmt.Errorf("creating agent dir for %s: %w", agent.DisplayName, err)
+		if err := tx.mkdirAll(agentSkillDir); err != nil {
+			return nil, nil, fmt.Errorf("creating agent dir for %s: %w", agent.DisplayName, err)
+		}
+		linkPath := filepath.Join(agentSkillDir, filepath.Base(p.canonicalDir))
+		linkType, err := p.swapAgentEntry(tx, agent, linkPath)
+		if err != nil {
+			return nil, nil, err
 		}
+		linkTypes[agent.Name] = linkType
+		installedAgents = append(installedAgents, agent.DisplayName)
+	}
+	return installedAgents, linkTypes, nil
+}
 
-		linkPath := filepath.Join(agentSkillDir, sanitized)
+// swapArtifacts writes each rendered artifact.
+func (p swapPlan) swapArtifacts(tx *installTxn, artifacts []plannedArtifact) error {
+	for _, a := range artifacts {
+		if err := p.swapArtifact(tx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// Remove existing link/dir if present.
-		_ = os.RemoveAll(linkPath)
+// removeStale removes the managed entries a previous install of the skill
+// recorded that this install no longer writes: links of agents that are no
+// longer targeted, and artifacts no longer rendered, for example after an
+// agent's renderer changed.
+func (p swapPlan) removeStale(tx *installTxn, opts InstallOptions, linkTypes map[string]string, artifacts []plannedArtifact) error {
+	lf, err := ReadLockfile(lockfilePath(p.layout))
+	if err != nil {
+		return err
+	}
+	previous, ok := lf.Find(p.skillID)
+	if !ok {
+		return nil
+	}
+	name := filepath.Base(p.canonicalDir)
+	seen := make(map[string]bool)
+	for _, link := range linkPaths(p.layout, p.targets, name) {
+		seen[link] = true
+	}
+	var stale []string
+	for _, agent := range p.known {
+		if _, was := previous.Agents[agent.Name]; !was {
+			continue
+		}
+		if _, still := linkTypes[agent.Name]; still {
+			continue
+		}
+		dir, linked := p.layout.agentRoot(agent)
+		if dir == "" || !linked {
+			continue
+		}
+		link := filepath.Join(dir, name)
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		if exists, managed := isManagedLink(link, p.canonicalDir); exists && managed {
+			stale = append(stale, link)
+		}
+	}
+	if !opts.Global {
+		stale = append(stale, staleArtifacts(opts.ProjectDir, previous.Artifacts, artifacts)...)
+	}
+	for _, path := range stale {
+		if err := tx.remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// Create relative symlink from agent dir to canonical location.
-		rel, err := filepath.Rel(agentSkillDir, canonicalDir)
-		if err != nil {
-			return nil, fmt.Errorf("computing relative path for %s: %w", agent.DisplayName, err)
+// stageCanonical builds the new canonical directory at staged. With
+// carryOver, files already in the canonical directory are kept. Supporting
+// files from opts are copied in and generated files written, then SKILL.md:
+// opts.SkillContent always replaces it; otherwise a default stub is written
+// only when no SKILL.md exists yet.
+func stageCanonical(staged, canonicalDir, skillID string, opts InstallOptions, carryOver bool) error {
+	if carryOver && dirExists(canonicalDir) {
+		if err := copyDirectory(canonicalDir, staged); err != nil {
+			return fmt.Errorf("staging existing skill: %w", err)
 		}
+	} else if err := os.MkdirAll(staged, 0o755); err != nil {
+		return fmt.Errorf("creating staging dir: %w", err)
+	}
 
-		if err := os.Symlink(rel, linkPath); err != nil {
-			// Fall back to copy if symlink fails (e.g., Windows without privileges).
-			if copyErr := copyDirectory(canonicalDir, linkPath); copyErr != nil {
-				return nil, fmt.Errorf("symlink and copy both failed for %s: symlink: %w, copy: %v",
-					agent.DisplayName, err, copyErr)
-			}
+	for _, rel := range opts.Files {
+		dst := filepath.Join(staged, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("staging %s: %w", rel, err)
+		}
+		if err := copyFile(filepath.Join(opts.SourceDir, filepath.FromSlash(rel)), dst); err != nil {
+			return fmt.Errorf("staging %s: %w", rel, err)
+		}
+	}
+	for _, rel := range sortedKeys(opts.Generated) {
+		dst := filepath.Join(staged, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("staging %s: %w", rel, err)
+		}
+		if err := os.WriteFile(dst, []byte(opts.Generated[rel]), 0o644); err != nil {
+			return fmt.Errorf("staging %s: %w", rel, err)
 		}
+	}
 
-		installedAgents = append(installedAgents, agent.DisplayName)
+	markerPath := filepath.Join(staged, "SKILL.md")
+	if content := opts.SkillContent; content != "" {
+		if err := os.WriteFile(markerPath, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("writing SKILL.md: %w", err)
+		}
+	} else if _, err := os.Stat(markerPath); os.IsNotExist(err) {
+		if err := os.WriteFile(markerPath, []byte(stubSkillMd(skillID)), 0o644); err != nil {
+			return fmt.Errorf("writing SKILL.md: %w", err)
+		}
 	}
+	if err := writeManagedMarker(staged, skillID); err != nil {
+		return fmt.Errorf("writing %s: %w", ManagedMarker, err)
+	}
+	return nil
+}
 
-	return &InstallResult{
-		SkillID:       skillID,
-		CanonicalPath: canonicalDir,
-		Agents:        installedAgents,
-	}, nil
+// swapAgentEntry stages a relative symlink to the canonical directory, or a
+// copy when symlinks are unavailable, and swaps it in at linkPath.
+func (p swapPlan) swapAgentEntry(tx *installTxn, agent agentregistry.AgentDefinition, linkPath string) (string, error) {
+	stage, err := stagingDir(linkPath)
+	if err != nil {
+		return "", fmt.Errorf("creating staging dir for %s: %w", agent.DisplayName, err)
+	}
+	defer func() { _ = os.RemoveAll(stage) }()
+
+	// The link target is resolved relative to linkPath once swapped in.
+	rel, err := filepath.Rel(filepath.Dir(linkPath), p.canonicalDir)
+	if err != nil {
+		return "", fmt.Errorf("computing relative path for %s: %w", agent.DisplayName, err)
+	}
+
+	staged := filepath.Join(stage, "entry")
+	linkType := LinkSymlink
+	if err := os.Symlink(rel, staged); err != nil {
+		// Fall back to copy if symlink fails (e.g., Windows without privileges).
+		if copyErr := copyDirectory(p.canonicalDir, staged); copyErr != nil {
+			return "", fmt.Errorf("symlink and copy both failed for %s: symlink: %w, copy: %v",
+				agent.DisplayName, err, copyErr)
+		}
+		if markErr := writeManagedMarker(staged, p.skillID); markErr != nil {
+			return "", fmt.Errorf("marking copy for %s: %w", agent.DisplayName, markErr)
+		}
+		linkType = LinkCopy
+	}
+	keep, err := p.keepAt(tx, linkPath)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.replace(staged, linkPath, CommittedEntry{Agent: agent.Name, LinkType: linkType}, keep); err != nil {
+		return "", fmt.Errorf("installing for %s: %w", agent.DisplayName, err)
+	}
+	return linkType, nil
+}
+
+// stubSkillMd is the placeholder SKILL.md written when no content is given.
+func stubSkillMd(skillID string) string {
+	return fmt.Sprintf("---\nname: %s\ndescription: \"\"\n---\n", skillID)
+}
+
+// verifyLocked checks, before anything is written, that the content about
+// to be installed matches the lockfile entry it is pinned to.
+func verifyLocked(skillID string, opts InstallOptions, artifacts []plannedArtifact) error {
+	content := opts.SkillContent
+	if content == "" {
+		content = stubSkillMd(skillID)
+	}
+	planned := map[string]string{"SKILL.md": hashBytes([]byte(content))}
+	for _, rel := range opts.Files {
+		data, err := os.ReadFile(filepath.Join(opts.SourceDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", rel, err)
+		}
+		planned[rel] = hashBytes(data)
+	}
+	for rel, content := range opts.Generated {
+		planned[rel] = hashBytes([]byte(content))
+	}
+	modified, missing, extra := diffFiles(opts.Locked.Files, planned)
+	if len(modified)+len(missing)+len(extra) > 0 {
+		return fmt.Errorf("skill %s does not match lockfile: modified %v, missing %v, unexpected %v",
+			skillID, modified, missing, extra)
+	}
+	modified, missing, extra = diffFiles(opts.Locked.Artifacts, artifactHashes(artifacts))
+	if len(modified)+len(missing)+len(extra) > 0 {
+		return fmt.Errorf("skill %s renders artifacts that do not match lockfile: modified %v, missing %v, unexpected %v",
+			skillID, modified, missing, extra)
+	}
+	return nil
 }
 
 // UninstallSkill removes a skill from the canonical location and removes
 // symlinks/copies from all agent skill directories.
 func (si *SkillInstaller) UninstallSkill(skillID string, projectDir string) error {
+	_, err := si.Uninstall(skillID, UninstallOptions{ProjectDir: projectDir})
+	return err
+}
+
+// UninstallGlobalSkill removes a skill from the user-level canonical location
+// and from every agent's global skills directory.
+func (si *SkillInstaller) UninstallGlobalSkill(skillID string) error {
+	_, err := si.Uninstall(skillID, UninstallOptions{Global: true})
+	return err
+}
+
+// UninstallOptions configures a skill removal.
+type UninstallOptions struct {
+	// ProjectDir is the project root the skill was installed into.
+	ProjectDir string
+
+	// Global removes the skill from the user-level directories instead.
+	Global bool
+
+	// Force removes entries that aios did not create after moving them to
+	// a timestamped directory under .agents/backups.
+	Force bool
+
+	// TargetAgents limits the removal to these agents. Their links and
+	// rendered artifacts are removed while the canonical directory stays
+	// for the remaining agents. When the targets cover every agent the
+	// skill is installed for, the skill is removed entirely. Nil removes it
+	// from all agents.
+	TargetAgents []agentregistry.AgentDefinition
+}
+
+// UninstallResult lists what an uninstall removed.
+type UninstallResult struct {
+	// Removed lists every entry that was deleted or moved to a backup.
+	Removed []string
+
+	// Backups maps each entry not managed by aios to where it was preserved.
+	Backups map[string]string
+}
+
+// Uninstall removes a skill's agent entries and canonical directory. Entries
+// that aios did not create make it fail with a *ConflictError before
+// anything is removed, unless opts.Force is set.
+func (si *SkillInstaller) Uninstall(skillID string, opts UninstallOptions) (*UninstallResult, error) {
 	if skillID == "" {
-		return fmt.Errorf("skill id is required")
+		return nil, fmt.Errorf("skill id is required")
 	}
-	if projectDir == "" {
-		return fmt.Errorf("project directory is required")
+	var layout installLayout
+	if opts.Global {
+		l, err := globalLayout()
+		if err != nil {
+			return nil, err
+		}
+		layout = l
+	} else {
+		if opts.ProjectDir == "" {
+			return nil, fmt.Errorf("project directory is required")
+		}
+		layout = projectLayout(opts.ProjectDir)
 	}
 
 	sanitized := SanitizeName(skillID)
+	canonicalDir := filepath.Join(layout.canonicalRoot, sanitized)
+	lf, err := ReadLockfile(lockfilePath(layout))
+	if err != nil {
+		return nil, err
+	}
+	locked, tracked := lf.Find(skillID)
+	if remaining := si.remainingAgents(locked, tracked, opts.TargetAgents); len(remaining) > 0 {
+		return si.uninstallAgents(layout, lf, skillID, opts, remaining)
+	}
 
-	// Remove symlinks from non-universal agents first.
-	for _, agent := range si.agents {
-		if agent.Universal {
+	links := linkPaths(layout, si.agents, sanitized)
+	unmanaged, err := unmanagedEntries(layout, skillID, links)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []string
+	if tracked && !opts.Global {
+		for rel := range locked.Artifacts {
+			p := filepath.Join(opts.ProjectDir, filepath.FromSlash(rel))
+			artifacts = append(artifacts, p)
+			if exists, managed := isManagedArtifact(p); exists && !managed {
+				unmanaged = append(unmanaged, p)
+			}
+		}
+		sort.Strings(artifacts)
+		sort.Strings(unmanaged)
+	}
+	if len(unmanaged) > 0 && !opts.Force {
+		return nil, &ConflictError{SkillID: skillID, Paths: unmanaged}
+	}
+
+	r := newRemover(layout, unmanaged)
+	// Remove rendered artifacts and agent links first, then the canonical
+	// directory.
+	for _, path := range append(append(artifacts, links...), canonicalDir) {
+		if err := r.remove(path); err != nil {
+			return r.result, err
+		}
+	}
+	if err := forgetInstall(layout, skillID); err != nil {
+		return r.result, fmt.Errorf("updating lockfile: %w", err)
+	}
+	return r.result, nil
+}
+
+// remainingAgents returns the names of agents that keep the skill after
+// removing it from targets: those recorded in the lockfile, or every known
+// agent for untracked skills, minus the targets. Nil targets remove the
+// skill everywhere.
+func (si *SkillInstaller) remainingAgents(locked LockedSkill, tracked bool, targets []agentregistry.AgentDefinition) []string {
+	if len(targets) == 0 {
+		return nil
+	}
+	removed := make(map[string]bool, len(targets))
+	for _, a := range targets {
+		removed[a.Name] = true
+	}
+	var installed []string
+	if tracked {
+		for name := range locked.Agents {
+			installed = append(installed, name)
+		}
+	} else {
+		for _, a := range si.agents {
+			installed = append(installed, a.Name)
+		}
+	}
+	var out []string
+	for _, name := range installed {
+		if !removed[name] {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// uninstallAgents removes a skill from opts.TargetAgents only, keeping the
+// canonical directory and every entry still used by a remaining agent.
+// Agents that read the canonical directory directly cannot be removed on
+// their own.
+func (si *SkillInstaller) uninstallAgents(layout installLayout, lf *Lockfile, skillID string, opts UninstallOptions, remaining []string) (*UninstallResult, error) {
+	sanitized := SanitizeName(skillID)
+	canonicalDir := filepath.Join(layout.canonicalRoot, sanitized)
+	for _, agent := range opts.TargetAgents {
+		if dir, linked := layout.agentRoot(agent); dir != "" && !linked {
+			return nil, fmt.Errorf("%s reads the shared %s directory and keeps the skill while %s still use it; uninstall without --agents to remove it everywhere",
+				agent.DisplayName, layout.canonicalRoot, strings.Join(remaining, ", "))
+		}
+	}
+
+	keepLinks := make(map[string]bool)
+	keepArtifacts := make(map[string]bool)
+	var kept []agentregistry.AgentDefinition
+	for _, a := range si.agents {
+		for _, name := range remaining {
+			if a.Name == name {
+				kept = append(kept, a)
+			}
+		}
+	}
+	for _, p := range linkPaths(layout, kept, sanitized) {
+		keepLinks[p] = true
+	}
+	locked, tracked := lf.Find(skillID)
+	for _, name := range remaining {
+		for _, rel := range locked.ArtifactAgents[name] {
+			keepArtifacts[rel] = true
+		}
+	}
+
+	var links, artifactRels, unmanaged []string
+	for _, p := range linkPaths(layout, opts.TargetAgents, sanitized) {
+		if keepLinks[p] {
 			continue
 		}
-		linkPath := filepath.Join(projectDir, agent.SkillsDir, sanitized)
-		_ = os.RemoveAll(linkPath)
+		links = append(links, p)
+		if exists, managed := isManagedLink(p, canonicalDir); exists && !managed {
+			unmanaged = append(unmanaged, p)
+		}
+	}
+	var artifacts []string
+	if !opts.Global {
+		for _, agent := range opts.TargetAgents {
+			for _, rel := range locked.ArtifactAgents[agent.Name] {
+				if keepArtifacts[rel] {
+					continue
+				}
+				keepArtifacts[rel] = true
+				p := filepath.Join(opts.ProjectDir, filepath.FromSlash(rel))
+				artifactRels = append(artifactRels, rel)
+				artifacts = append(artifacts, p)
+				if exists, managed := isManagedArtifact(p); exists && !managed {
+					unmanaged = append(unmanaged, p)
+				}
+			}
+		}
+	}
+	sort.Strings(unmanaged)
+	if len(unmanaged) > 0 && !opts.Force {
+		return nil, &ConflictError{SkillID: skillID, Paths: unmanaged}
 	}
 
-	// Remove canonical directory.
-	canonicalDir := filepath.Join(projectDir, agentregistry.CanonicalSkillsDir, sanitized)
-	if err := os.RemoveAll(canonicalDir); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("removing canonical dir: %w", err)
+	r := newRemover(layout, unmanaged)
+	for _, path := range append(artifacts, links...) {
+		if err := r.remove(path); err != nil {
+			return r.result, err
+		}
 	}
+	if !tracked {
+		return r.result, nil
+	}
+	for _, agent := range opts.TargetAgents {
+		delete(locked.Agents, agent.Name)
+		delete(locked.ArtifactAgents, agent.Name)
+	}
+	for _, rel := range artifactRels {
+		delete(locked.Artifacts, rel)
+	}
+	lf.upsert(locked)
+	if err := WriteLockfile(lockfilePath(layout), lf); err != nil {
+		return r.result, fmt.Errorf("updating lockfile: %w", err)
+	}
+	return r.result, nil
+}
+
+// remover deletes uninstalled entries, moving those not managed by aios to
+// a backup directory first.
+type remover struct {
+	unmanaged map[string]bool
+	backups   *backupSet
+	tx        *installTxn
+	result    *UninstallResult
+}
 
+func newRemover(layout installLayout, unmanaged []string) *remover {
+	r := &remover{
+		unmanaged: make(map[string]bool, len(unmanaged)),
+		backups:   newBackupSet(layout),
+		tx:        &installTxn{},
+		result:    &UninstallResult{Backups: map[string]string{}},
+	}
+	for _, p := range unmanaged {
+		r.unmanaged[p] = true
+	}
+	return r
+}
+
+func (r *remover) remove(path string) error {
+	if _, err := os.Lstat(path); err != nil {
+		return nil
+	}
+	if r.unmanaged[path] {
+		dest, err := r.backups.pathFor(r.tx, path)
+		if err != nil {
+			return err
+		}
+		if err := os.Rename(path, dest); err != nil {
+			return fmt.Errorf("backing up %s: %w", path, err)
+		}
+		r.result.Backups[path] = dest
+	} else if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+	r.result.Removed = append(r.result.Removed, path)
 	return nil
 }
 
 // PlanWriteTargets returns the list of paths that would be written to
 // when installing a skill. Used for dry-run planning.
 func (si *SkillInstaller) PlanWriteTargets(skillID string, projectDir string) []string {
+	return planWriteTargets(skillID, projectLayout(projectDir), si.agents)
+}
+
+// PlanGlobalWriteTargets returns the list of paths that would be written to
+// when installing a skill globally.
+func (si *SkillInstaller) PlanGlobalWriteTargets(skillID string) ([]string, error) {
+	layout, err := globalLayout()
+	if err != nil {
+		return nil, err
+	}
+	return planWriteTargets(skillID, layout, si.agents), nil
+}
+
+func planWriteTargets(skillID string, layout installLayout, agents []agentregistry.AgentDefinition) []string {
 	sanitized := SanitizeName(skillID)
 	targets := []string{
-		filepath.Join(projectDir, agentregistry.CanonicalSkillsDir, sanitized),
+		filepath.Join(layout.canonicalRoot, sanitized),
 	}
-	for _, agent := range si.agents {
-		if agent.Universal {
+	for _, agent := range agents {
+		agentSkillDir, linked := layout.agentRoot(agent)
+		if agentSkillDir == "" || !linked {
 			continue
 		}
-		targets = append(targets,
-			filepath.Join(projectDir, agent.SkillsDir, sanitized))
+		targets = append(targets, filepath.Join(agentSkillDir, sanitized))
 	}
 	return targets
 }
 
+// InstallPlan lists everything an install would write.
+type InstallPlan struct {
+	// Agents are the names of the agents the skill is installed for.
+	Agents []string
+
+	// Targets are the canonical directory followed by each agent link.
+	Targets []string
+
+	// Files are the files written into the canonical directory, SKILL.md
+	// first, followed by rendered agent artifacts.
+	Files []string
+}
+
+// PlanInstall returns what InstallSkill would write for skillID and opts
+// without touching the filesystem.
+func (si *SkillInstaller) PlanInstall(skillID string, opts InstallOptions) (*InstallPlan, error) {
+	var layout installLayout
+	if opts.Global {
+		l, err := globalLayout()
+		if err != nil {
+			return nil, err
+		}
+		layout = l
+	} else {
+		layout = projectLayout(opts.ProjectDir)
+	}
+	targets := si.agents
+	if len(opts.TargetAgents) > 0 {
+		targets = opts.TargetAgents
+	}
+	canonicalDir := filepath.Join(layout.canonicalRoot, SanitizeName(skillID))
+	plan := &InstallPlan{
+		Agents:  make([]string, 0, len(targets)),
+		Targets: planWriteTargets(skillID, layout, targets),
+		Files:   []string{filepath.Join(canonicalDir, "SKILL.md")},
+	}
+	for _, agent := range targets {
+		if dir, _ := layout.agentRoot(agent); dir != "" {
+			plan.Agents = append(plan.Agents, agent.Name)
+		}
+	}
+	for _, rel := range opts.Files {
+		plan.Files = append(plan.Files, filepath.Join(canonicalDir, filepath.FromSlash(rel)))
+	}
+	for _, rel := range sortedKeys(opts.Generated) {
+		plan.Files = append(plan.Files, filepath.Join(canonicalDir, filepath.FromSlash(rel)))
+	}
+	artifacts, err := planArtifacts(opts, targets)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range artifacts {
+		plan.Files = append(plan.Files, a.path)
+	}
+	return plan, nil
+}
+
 // CollectInstalledSkills scans all agent skill directories in a project
 // and returns a deduplicated sorted list of installed skill IDs.
 func (si *SkillInstaller) CollectInstalledSkills(projectDir string) ([]string, error) {
+	return si.collectInstalledSkills(projectLayout(projectDir)), nil
+}
+
+// CollectGlobalInstalledSkills scans the user-level canonical directory and
+// every agent's global skills directory and returns a deduplicated sorted
+// list of installed skill IDs.
+func (si *SkillInstaller) CollectGlobalInstalledSkills() ([]string, error) {
+	layout, err := globalLayout()
+	if err != nil {
+		return nil, err
+	}
+	return si.collectInstalledSkills(layout), nil
+}
+
+func (si *SkillInstaller) collectInstalledSkills(layout installLayout) []string {
 	seen := make(map[string]struct{})
 
 	// Scan canonical directory first.
-	canonicalDir := filepath.Join(projectDir, agentregistry.CanonicalSkillsDir)
-	if entries, err := os.ReadDir(canonicalDir); err == nil {
+	if entries, err := os.ReadDir(layout.canonicalRoot); err == nil {
 		for _, e := range entries {
 			if e.IsDir() {
 				seen[e.Name()] = struct{}{}
@@ -195,12 +893,12 @@ func (si *SkillInstaller) CollectInstalledSkills(projectDir string) ([]string, e
 		}
 	}
 
-	// Also scan non-universal agent directories for skills not yet in canonical.
+	// Also scan linked agent directories for skills not yet in canonical.
 	for _, agent := range si.agents {
-		if agent.Universal {
+		agentDir, linked := layout.agentRoot(agent)
+		if agentDir == "" || !linked {
 			continue
 		}
-		agentDir := filepath.Join(projectDir, agent.SkillsDir)
 		entries, err := os.ReadDir(agentDir)
 		if err != nil {
 			continue
@@ -218,7 +916,7 @@ func (si *SkillInstaller) CollectInstalledSkills(projectDir string) ([]string, e
 		out = append(out, id)
 	}
 	sortStrings(out)
-	return out, nil
+	return out
 }
 
 // SanitizeName normalizes a skill name for use as a directory name.
@@ -299,3 +997,13 @@ func sortStrings(s []string) {
 		s[j+1] = key
 	}
 }
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
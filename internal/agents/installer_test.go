@@ -3,6 +3,7 @@ package agents
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
@@ -222,11 +223,14 @@ func TestInstallSkill_DoesNotOverwriteExistingSkillMd(t *testing.T) {
 	tmp := t.TempDir()
 	si := NewSkillInstaller(testAgentDefs())
 
-	// Pre-create SKILL.md with custom content.
+	// Pre-create SKILL.md with custom content in a managed skill dir.
 	canonicalDir := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
 	if err := os.MkdirAll(canonicalDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
+	if err := writeManagedMarker(canonicalDir, "test-skill"); err != nil {
+		t.Fatal(err)
+	}
 	customContent := "---\nname: custom\n---\nCustom content"
 	if err := os.WriteFile(filepath.Join(canonicalDir, "SKILL.md"), []byte(customContent), 0o644); err != nil {
 		t.Fatal(err)
@@ -535,3 +539,395 @@ func TestSortStrings_Single(t *testing.T) {
 		t.Errorf("expected %q, got %q", "only", input[0])
 	}
 }
+
+func globalTestAgentDefs() []agentregistry.AgentDefinition {
+	return []agentregistry.AgentDefinition{
+		{Name: "opencode", DisplayName: "OpenCode", SkillsDir: agentregistry.CanonicalSkillsDir, GlobalSkillsDir: "~/.agents/skills", Universal: true},
+		{Name: "codex", DisplayName: "Codex", SkillsDir: agentregistry.CanonicalSkillsDir, GlobalSkillsDir: "$CODEX_HOME/skills", Universal: true},
+		{Name: "cursor", DisplayName: "Cursor", SkillsDir: ".cursor/skills", GlobalSkillsDir: "~/.cursor/skills"},
+		{Name: "cline", DisplayName: "Cline", SkillsDir: ".cline/skills"},
+	}
+}
+
+func TestInstallSkill_GlobalUsesHomeLayout(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+	si := NewSkillInstaller(globalTestAgentDefs())
+
+	result, err := si.InstallSkill("test-skill", InstallOptions{Global: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	canonicalDir := filepath.Join(home, ".agents", "skills", "test-skill")
+	if result.CanonicalPath != canonicalDir {
+		t.Errorf("expected canonical path %q, got %q", canonicalDir, result.CanonicalPath)
+	}
+	for _, link := range []string{
+		filepath.Join(home, ".codex", "skills", "test-skill"),
+		filepath.Join(home, ".cursor", "skills", "test-skill"),
+	} {
+		info, err := os.Lstat(link)
+		if err != nil {
+			t.Fatalf("expected link %s: %v", link, err)
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("expected %s to be a symlink", link)
+		}
+	}
+	// Cline has no global skills dir and is skipped.
+	if len(result.Agents) != 3 {
+		t.Errorf("expected 3 installed agents, got %v", result.Agents)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cline")); !os.IsNotExist(err) {
+		t.Error("agent without global skills dir should not be touched")
+	}
+}
+
+func TestInstallSkill_GlobalSkipsUnsetEnvDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", "") // restores the original value after the test
+	_ = os.Unsetenv("CODEX_HOME")
+	si := NewSkillInstaller(globalTestAgentDefs())
+
+	if _, err := si.InstallSkill("test-skill", InstallOptions{Global: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join("/skills", "test-skill")); !os.IsNotExist(err) {
+		t.Error("unset $CODEX_HOME should not resolve to /skills")
+	}
+	targets, err := si.PlanGlobalWriteTargets("test-skill")
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Errorf("expected canonical + cursor targets, got %v", targets)
+	}
+}
+
+func TestUninstallGlobalSkill_RemovesCanonicalAndLinks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+	si := NewSkillInstaller(globalTestAgentDefs())
+
+	if _, err := si.InstallSkill("test-skill", InstallOptions{Global: true}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	skills, err := si.CollectGlobalInstalledSkills()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if len(skills) != 1 || skills[0] != "test-skill" {
+		t.Fatalf("expected [test-skill], got %v", skills)
+	}
+
+	if err := si.UninstallGlobalSkill("test-skill"); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	for _, p := range []string{
+		filepath.Join(home, ".agents", "skills", "test-skill"),
+		filepath.Join(home, ".codex", "skills", "test-skill"),
+		filepath.Join(home, ".cursor", "skills", "test-skill"),
+	} {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", p)
+		}
+	}
+}
+
+func TestPlanGlobalWriteTargets_ReturnsHomePaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+	si := NewSkillInstaller(globalTestAgentDefs())
+
+	targets, err := si.PlanGlobalWriteTargets("test-skill")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		filepath.Join(home, ".agents", "skills", "test-skill"),
+		filepath.Join(home, ".codex", "skills", "test-skill"),
+		filepath.Join(home, ".cursor", "skills", "test-skill"),
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("expected %v, got %v", want, targets)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("target %d: expected %q, got %q", i, want[i], targets[i])
+		}
+	}
+}
+
+func writeSourceFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInstallSkill_CopiesSupportingFiles(t *testing.T) {
+	tmp := t.TempDir()
+	src := t.TempDir()
+	writeSourceFiles(t, src, map[string]string{"scripts/run.sh": "#!/bin/sh\n", "references/api.md": "# API\n"})
+	si := NewSkillInstaller(testAgentDefs())
+
+	if _, err := si.InstallSkill("test-skill", InstallOptions{
+		ProjectDir:   tmp,
+		SkillContent: "# Test\n",
+		SourceDir:    src,
+		Files:        []string{"references/api.md", "scripts/run.sh"},
+	}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	canonicalDir := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
+	info, err := os.Stat(filepath.Join(canonicalDir, "scripts", "run.sh"))
+	if err != nil {
+		t.Fatalf("expected script to be installed: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("expected script to stay executable, got %v", info.Mode())
+	}
+	// Agents linked by symlink see the same files.
+	if _, err := os.Stat(filepath.Join(tmp, ".cursor", "skills", "test-skill", "references", "api.md")); err != nil {
+		t.Errorf("expected reference through cursor link: %v", err)
+	}
+
+	lf, err := ReadLockfile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := lf.Find("test-skill")
+	if entry.Files["scripts/run.sh"] != hashBytes([]byte("#!/bin/sh\n")) {
+		t.Errorf("expected script hash in lockfile, got %#v", entry.Files)
+	}
+}
+
+func TestInstallSkill_SourceDirDropsStaleFiles(t *testing.T) {
+	tmp := t.TempDir()
+	src := t.TempDir()
+	writeSourceFiles(t, src, map[string]string{"scripts/old.sh": "old\n", "scripts/new.sh": "new\n"})
+	si := NewSkillInstaller(testAgentDefs())
+
+	install := func(files ...string) {
+		t.Helper()
+		if _, err := si.InstallSkill("test-skill", InstallOptions{
+			ProjectDir:   tmp,
+			SkillContent: "# Test\n",
+			SourceDir:    src,
+			Files:        files,
+		}); err != nil {
+			t.Fatalf("install: %v", err)
+		}
+	}
+	install("scripts/old.sh")
+	install("scripts/new.sh")
+
+	canonicalDir := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
+	if _, err := os.Stat(filepath.Join(canonicalDir, "scripts", "old.sh")); !os.IsNotExist(err) {
+		t.Error("expected file removed from the source to be dropped")
+	}
+	if _, err := os.Stat(filepath.Join(canonicalDir, "scripts", "new.sh")); err != nil {
+		t.Errorf("expected new file: %v", err)
+	}
+}
+
+func TestInstallSkill_LockedVerifiesSupportingFiles(t *testing.T) {
+	tmp := t.TempDir()
+	src := t.TempDir()
+	writeSourceFiles(t, src, map[string]string{"scripts/run.sh": "v1\n"})
+	si := NewSkillInstaller(testAgentDefs())
+	opts := InstallOptions{ProjectDir: tmp, SkillContent: "# Test\n", SourceDir: src, Files: []string{"scripts/run.sh"}}
+	if _, err := si.InstallSkill("test-skill", opts); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	lf, err := ReadLockfile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned, _ := lf.Find("test-skill")
+
+	writeSourceFiles(t, src, map[string]string{"scripts/run.sh": "v2\n"})
+	opts.Locked = &pinned
+	if _, err := si.InstallSkill("test-skill", opts); err == nil {
+		t.Fatal("expected frozen install to reject a changed supporting file")
+	}
+}
+
+func TestPlanInstall_ListsFiles(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+
+	plan, err := si.PlanInstall("test-skill", InstallOptions{ProjectDir: tmp, Files: []string{"scripts/run.sh"}})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	canonicalDir := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
+	want := []string{filepath.Join(canonicalDir, "SKILL.md"), filepath.Join(canonicalDir, "scripts", "run.sh")}
+	if len(plan.Files) != len(want) || plan.Files[0] != want[0] || plan.Files[1] != want[1] {
+		t.Errorf("expected files %v, got %v", want, plan.Files)
+	}
+	if len(plan.Targets) == 0 || plan.Targets[0] != canonicalDir {
+		t.Errorf("expected canonical dir first in targets, got %v", plan.Targets)
+	}
+}
+
+func TestInstallSkill_WritesGeneratedFiles(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	opts := InstallOptions{
+		ProjectDir:   tmp,
+		SkillContent: "# Test\n",
+		SourceDir:    t.TempDir(),
+		Generated:    map[string]string{"references/code.md": "Write clean code.\n"},
+	}
+	if _, err := si.InstallSkill("test-skill", opts); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	canonicalDir := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
+	data, err := os.ReadFile(filepath.Join(canonicalDir, "references", "code.md"))
+	if err != nil || string(data) != "Write clean code.\n" {
+		t.Fatalf("expected generated reference, got %q (%v)", data, err)
+	}
+	lf, err := ReadLockfile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned, _ := lf.Find("test-skill")
+	if pinned.Files["references/code.md"] != hashBytes(data) {
+		t.Errorf("expected generated file hash in lockfile, got %#v", pinned.Files)
+	}
+
+	opts.Locked = &pinned
+	if _, err := si.InstallSkill("test-skill", opts); err != nil {
+		t.Fatalf("expected frozen install of unchanged content to pass: %v", err)
+	}
+	opts.Generated = map[string]string{"references/code.md": "Write any code.\n"}
+	if _, err := si.InstallSkill("test-skill", opts); err == nil {
+		t.Fatal("expected frozen install to reject a changed generated file")
+	}
+
+	plan, err := si.PlanInstall("test-skill", opts)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.Files) != 2 || plan.Files[1] != filepath.Join(canonicalDir, "references", "code.md") {
+		t.Errorf("expected generated file in plan, got %v", plan.Files)
+	}
+}
+
+func TestInstallSkill_NarrowingTargetsRemovesStaleLinks(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	claudeOnly := testAgentDefs()[3:]
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, TargetAgents: claudeOnly, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatalf("narrowed install: %v", err)
+	}
+	for _, p := range []string{
+		filepath.Join(tmp, ".cursor", "skills", "review"),
+		filepath.Join(tmp, ".cursor", "rules", "review.mdc"),
+	} {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", p)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(tmp, ".claude", "skills", "review")); err != nil {
+		t.Errorf("expected claude-code link to remain: %v", err)
+	}
+	lf, _ := ReadLockfile(ProjectLockfilePath(tmp))
+	entry, _ := lf.Find("review")
+	if _, ok := entry.Agents["cursor"]; ok || len(entry.Agents) != 1 {
+		t.Errorf("expected only claude-code locked, got %#v", entry.Agents)
+	}
+}
+
+func TestUninstall_TargetAgentsKeepsOthers(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp, Artifacts: cursorArtifacts("# Review\n")}); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor := testAgentDefs()[2:3]
+	if _, err := si.Uninstall("review", UninstallOptions{ProjectDir: tmp, TargetAgents: cursor}); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	for _, p := range []string{
+		filepath.Join(tmp, ".cursor", "skills", "review"),
+		filepath.Join(tmp, ".cursor", "rules", "review.mdc"),
+	} {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", p)
+		}
+	}
+	for _, p := range []string{
+		filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review"),
+		filepath.Join(tmp, ".claude", "skills", "review"),
+	} {
+		if _, err := os.Lstat(p); err != nil {
+			t.Errorf("expected %s to remain: %v", p, err)
+		}
+	}
+	lf, _ := ReadLockfile(ProjectLockfilePath(tmp))
+	entry, tracked := lf.Find("review")
+	if !tracked || len(entry.Artifacts) != 0 {
+		t.Fatalf("expected skill tracked without artifacts, got %#v", entry)
+	}
+	if _, ok := entry.Agents["cursor"]; ok {
+		t.Errorf("expected cursor dropped from lockfile, got %#v", entry.Agents)
+	}
+
+	// Removing the last remaining agents removes the skill entirely.
+	if _, err := si.Uninstall("review", UninstallOptions{ProjectDir: tmp, TargetAgents: testAgentDefs()}); err != nil {
+		t.Fatalf("full uninstall: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review")); !os.IsNotExist(err) {
+		t.Error("expected canonical directory removed")
+	}
+}
+
+func TestUninstall_TargetAgentsRejectsUniversalAgent(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := si.Uninstall("review", UninstallOptions{ProjectDir: tmp, TargetAgents: testAgentDefs()[:1]})
+	if err == nil || !strings.Contains(err.Error(), "OpenCode") {
+		t.Fatalf("expected universal agent error, got %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(tmp, ".cursor", "skills", "review")); err != nil {
+		t.Errorf("expected nothing removed: %v", err)
+	}
+}
+
+func TestPlanInstall_ListsTargetAgents(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	plan, err := si.PlanInstall("review", InstallOptions{ProjectDir: tmp, TargetAgents: testAgentDefs()[2:3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Agents) != 1 || plan.Agents[0] != "cursor" {
+		t.Errorf("expected cursor only, got %v", plan.Agents)
+	}
+	if len(plan.Targets) != 2 || plan.Targets[1] != filepath.Join(tmp, ".cursor", "skills", "review") {
+		t.Errorf("unexpected targets %v", plan.Targets)
+	}
+}
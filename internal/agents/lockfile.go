@@ -0,0 +1,402 @@
+package agents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+// LockfileName is the lockfile written next to the canonical skills
+// directory, e.g. .agents/skills.lock.
+const LockfileName = "skills.lock"
+
+// lockfileVersion is the schema version written to new lockfiles.
+const lockfileVersion = 1
+
+// Link types recorded per agent in the lockfile.
+const (
+	// LinkCanonical means the agent reads the canonical directory directly.
+	LinkCanonical = "canonical"
+	// LinkSymlink means the agent directory holds a symlink to the canonical entry.
+	LinkSymlink = "symlink"
+	// LinkCopy means symlinking failed and the agent directory holds a copy.
+	LinkCopy = "copy"
+	// LinkRendered marks a committed entry that is a rendered artifact
+	// rather than a skills directory entry.
+	LinkRendered = "rendered"
+)
+
+// Skill states reported by Status.
+const (
+	StatusOK        = "ok"
+	StatusModified  = "modified"
+	StatusMissing   = "missing"
+	StatusUntracked = "untracked"
+)
+
+// Lockfile records the exact set of skills installed into a project.
+type Lockfile struct {
+	Version int           `json:"version"`
+	Skills  []LockedSkill `json:"skills"`
+}
+
+// LockedSkill is one installed skill as recorded in the lockfile.
+type LockedSkill struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+
+	// Source is the skill directory the install came from. Directories
+	// inside the project are stored relative to it so the lockfile can be
+	// shared.
+	Source string `json:"source,omitempty"`
+
+	// Files maps each installed file (slash-separated, relative to the
+	// canonical skill directory) to its SHA-256 digest.
+	Files map[string]string `json:"files"`
+
+	// Agents maps agent names to the link type used for that agent.
+	Agents map[string]string `json:"agents"`
+
+	// Artifacts maps each rendered agent artifact (slash-separated,
+	// relative to the project) to its SHA-256 digest.
+	Artifacts map[string]string `json:"artifacts,omitempty"`
+
+	// ArtifactAgents maps agent names to the artifacts rendered for them,
+	// so a skill can be removed from some agents only.
+	ArtifactAgents map[string][]string `json:"artifact_agents,omitempty"`
+}
+
+// SkillStatus compares one skill on disk against the lockfile.
+type SkillStatus struct {
+	ID            string            `json:"id"`
+	Version       string            `json:"version,omitempty"`
+	State         string            `json:"state"`
+	ModifiedFiles []string          `json:"modified_files,omitempty"`
+	MissingFiles  []string          `json:"missing_files,omitempty"`
+	ExtraFiles    []string          `json:"extra_files,omitempty"`
+	LinkIssues    map[string]string `json:"link_issues,omitempty"`
+
+	// ArtifactIssues maps rendered artifacts that are missing or were
+	// edited since install to "missing" or "modified".
+	ArtifactIssues map[string]string `json:"artifact_issues,omitempty"`
+}
+
+// ProjectLockfilePath returns the lockfile location for a project.
+func ProjectLockfilePath(projectDir string) string {
+	return lockfilePath(projectLayout(projectDir))
+}
+
+func lockfilePath(layout installLayout) string {
+	return filepath.Join(filepath.Dir(layout.canonicalRoot), LockfileName)
+}
+
+// ReadLockfile loads a lockfile. A missing file yields an empty lockfile.
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Lockfile{Version: lockfileVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lockfile: %w", err)
+	}
+	var lf Lockfile
+	if err := json.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("parsing lockfile %s: %w", path, err)
+	}
+	if lf.Version > lockfileVersion {
+		return nil, fmt.Errorf("lockfile %s has unsupported version %d", path, lf.Version)
+	}
+	return &lf, nil
+}
+
+// WriteLockfile writes the lockfile with skills sorted by id so that
+// repeated installs produce identical output.
+func WriteLockfile(path string, lf *Lockfile) error {
+	lf.Version = lockfileVersion
+	sort.Slice(lf.Skills, func(i, j int) bool { return lf.Skills[i].ID < lf.Skills[j].ID })
+	data, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating lockfile dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Find returns the entry for a skill id.
+func (lf *Lockfile) Find(skillID string) (LockedSkill, bool) {
+	for _, s := range lf.Skills {
+		if s.ID == skillID {
+			return s, true
+		}
+	}
+	return LockedSkill{}, false
+}
+
+func (lf *Lockfile) upsert(entry LockedSkill) {
+	for i, s := range lf.Skills {
+		if s.ID == entry.ID {
+			lf.Skills[i] = entry
+			return
+		}
+	}
+	lf.Skills = append(lf.Skills, entry)
+}
+
+func (lf *Lockfile) remove(skillID string) bool {
+	for i, s := range lf.Skills {
+		if s.ID == skillID {
+			lf.Skills = append(lf.Skills[:i], lf.Skills[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HashSkillDir returns the SHA-256 of every regular file under dir, keyed
+// by slash-separated relative path. Dot files are skipped, matching what
+// copyDirectory installs.
+func HashSkillDir(dir string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(rel)] = hashBytes(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// diffFiles compares recorded hashes against actual ones.
+func diffFiles(want, got map[string]string) (modified, missing, extra []string) {
+	for name, sum := range want {
+		actual, ok := got[name]
+		switch {
+		case !ok:
+			missing = append(missing, name)
+		case actual != sum:
+			modified = append(modified, name)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			extra = append(extra, name)
+		}
+	}
+	sortStrings(modified)
+	sortStrings(missing)
+	sortStrings(extra)
+	return modified, missing, extra
+}
+
+// recordInstall updates the lockfile entry for a completed install.
+func recordInstall(layout installLayout, entry LockedSkill, canonicalDir string) error {
+	files, err := HashSkillDir(canonicalDir)
+	if err != nil {
+		return fmt.Errorf("hashing installed files: %w", err)
+	}
+	entry.Files = files
+	path := lockfilePath(layout)
+	lf, err := ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	lf.upsert(entry)
+	return WriteLockfile(path, lf)
+}
+
+// forgetInstall drops a skill from the lockfile if one exists.
+func forgetInstall(layout installLayout, skillID string) error {
+	path := lockfilePath(layout)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	lf, err := ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	if !lf.remove(skillID) {
+		return nil
+	}
+	return WriteLockfile(path, lf)
+}
+
+// Status compares the skills installed in a project against its lockfile.
+// Skills present on disk but absent from the lockfile are reported as
+// untracked.
+func (si *SkillInstaller) Status(projectDir string) ([]SkillStatus, error) {
+	if projectDir == "" {
+		return nil, fmt.Errorf("project directory is required")
+	}
+	layout := projectLayout(projectDir)
+	lf, err := ReadLockfile(lockfilePath(layout))
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]agentregistry.AgentDefinition, len(si.agents))
+	for _, a := range si.agents {
+		byName[a.Name] = a
+	}
+
+	tracked := make(map[string]struct{}, len(lf.Skills))
+	out := make([]SkillStatus, 0, len(lf.Skills))
+	for _, locked := range lf.Skills {
+		tracked[SanitizeName(locked.ID)] = struct{}{}
+		status := SkillStatus{ID: locked.ID, Version: locked.Version, State: StatusOK}
+		canonicalDir := filepath.Join(layout.canonicalRoot, SanitizeName(locked.ID))
+		if !dirExists(canonicalDir) {
+			status.State = StatusMissing
+			out = append(out, status)
+			continue
+		}
+		actual, err := HashSkillDir(canonicalDir)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %w", locked.ID, err)
+		}
+		status.ModifiedFiles, status.MissingFiles, status.ExtraFiles = diffFiles(locked.Files, actual)
+		status.LinkIssues = checkLinks(layout, byName, locked, canonicalDir)
+		status.ArtifactIssues = checkArtifacts(projectDir, locked)
+		if len(status.ModifiedFiles)+len(status.MissingFiles)+len(status.ExtraFiles)+len(status.LinkIssues)+len(status.ArtifactIssues) > 0 {
+			status.State = StatusModified
+		}
+		out = append(out, status)
+	}
+
+	if entries, err := os.ReadDir(layout.canonicalRoot); err == nil {
+		for _, e := range entries {
+			if _, ok := tracked[e.Name()]; ok || !e.IsDir() {
+				continue
+			}
+			out = append(out, SkillStatus{ID: e.Name(), State: StatusUntracked})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// checkLinks verifies that every agent recorded for a skill still sees it
+// through the recorded link type.
+func checkLinks(layout installLayout, byName map[string]agentregistry.AgentDefinition, locked LockedSkill, canonicalDir string) map[string]string {
+	issues := make(map[string]string)
+	for name, linkType := range locked.Agents {
+		if linkType == LinkCanonical {
+			continue
+		}
+		agent, ok := byName[name]
+		if !ok {
+			issues[name] = "unknown agent"
+			continue
+		}
+		agentDir, linked := layout.agentRoot(agent)
+		if agentDir == "" || !linked {
+			issues[name] = fmt.Sprintf("expected %s, agent now reads the canonical dir", linkType)
+			continue
+		}
+		linkPath := filepath.Join(agentDir, SanitizeName(locked.ID))
+		info, err := os.Lstat(linkPath)
+		if err != nil {
+			issues[name] = "missing"
+			continue
+		}
+		isLink := info.Mode()&os.ModeSymlink != 0
+		switch linkType {
+		case LinkSymlink:
+			if !isLink {
+				issues[name] = "expected symlink, found " + describeMode(info)
+				continue
+			}
+			target, err := filepath.EvalSymlinks(linkPath)
+			want, wantErr := filepath.EvalSymlinks(canonicalDir)
+			if err != nil || wantErr != nil || target != want {
+				issues[name] = "symlink does not point to canonical dir"
+			}
+		case LinkCopy:
+			if isLink || !info.IsDir() {
+				issues[name] = "expected copy, found " + describeMode(info)
+				continue
+			}
+			actual, err := HashSkillDir(linkPath)
+			if err != nil {
+				issues[name] = "unreadable copy"
+				continue
+			}
+			if modified, missing, extra := diffFiles(locked.Files, actual); len(modified)+len(missing)+len(extra) > 0 {
+				issues[name] = "copy differs from lockfile"
+			}
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+// checkArtifacts compares the rendered artifacts on disk with their
+// recorded digests.
+func checkArtifacts(projectDir string, locked LockedSkill) map[string]string {
+	issues := make(map[string]string)
+	for rel, sum := range locked.Artifacts {
+		data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(rel)))
+		switch {
+		case err != nil:
+			issues[rel] = StatusMissing
+		case hashBytes(data) != sum:
+			issues[rel] = StatusModified
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+func describeMode(info os.FileInfo) string {
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		return "symlink"
+	case info.IsDir():
+		return "directory"
+	default:
+		return "file"
+	}
+}
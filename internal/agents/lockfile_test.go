@@ -0,0 +1,184 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+func TestInstallSkill_WritesLockfileEntry(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+
+	if _, err := si.InstallSkill("test-skill", InstallOptions{
+		ProjectDir:   tmp,
+		SkillContent: "# Test\n",
+		Version:      "1.2.0",
+		Source:       "skills/test-skill",
+	}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	lf, err := ReadLockfile(filepath.Join(tmp, ".agents", LockfileName))
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	entry, ok := lf.Find("test-skill")
+	if !ok {
+		t.Fatalf("expected lockfile entry, got %#v", lf)
+	}
+	if entry.Version != "1.2.0" || entry.Source != "skills/test-skill" {
+		t.Errorf("unexpected entry metadata: %#v", entry)
+	}
+	if entry.Files["SKILL.md"] != hashBytes([]byte("# Test\n")) {
+		t.Errorf("unexpected SKILL.md hash: %q", entry.Files["SKILL.md"])
+	}
+	if entry.Agents["opencode"] != LinkCanonical || entry.Agents["cursor"] != LinkSymlink {
+		t.Errorf("unexpected link types: %#v", entry.Agents)
+	}
+}
+
+func TestInstallSkill_LockfileIsDeterministic(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	lockPath := ProjectLockfilePath(tmp)
+
+	for _, id := range []string{"beta", "alpha"} {
+		if _, err := si.InstallSkill(id, InstallOptions{ProjectDir: tmp}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := si.InstallSkill("beta", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("lockfile changed on identical reinstall:\n%s\n---\n%s", first, second)
+	}
+	lf, _ := ReadLockfile(lockPath)
+	if len(lf.Skills) != 2 || lf.Skills[0].ID != "alpha" {
+		t.Errorf("expected skills sorted by id, got %#v", lf.Skills)
+	}
+}
+
+func TestUninstallSkill_RemovesLockfileEntry(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	for _, id := range []string{"alpha", "beta"} {
+		if _, err := si.InstallSkill(id, InstallOptions{ProjectDir: tmp}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := si.UninstallSkill("alpha", tmp); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	lf, err := ReadLockfile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lf.Find("alpha"); ok {
+		t.Error("expected alpha to be removed from lockfile")
+	}
+	if _, ok := lf.Find("beta"); !ok {
+		t.Error("expected beta to remain in lockfile")
+	}
+}
+
+func TestInstallSkill_LockedMismatchFailsBeforeWriting(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	locked := &LockedSkill{ID: "test-skill", Files: map[string]string{"SKILL.md": hashBytes([]byte("old"))}}
+
+	_, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp, SkillContent: "new", Locked: locked})
+	if err == nil {
+		t.Fatal("expected lockfile mismatch error")
+	}
+	if _, statErr := os.Stat(filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")); !os.IsNotExist(statErr) {
+		t.Error("mismatched frozen install should not write the skill")
+	}
+}
+
+func TestInstallSkill_LockedLeavesLockfileUntouched(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp, SkillContent: "same", Version: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	lf, _ := ReadLockfile(ProjectLockfilePath(tmp))
+	entry, _ := lf.Find("test-skill")
+
+	if _, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp, SkillContent: "same", Version: "2.0.0", Locked: &entry}); err != nil {
+		t.Fatalf("frozen install: %v", err)
+	}
+	lf, _ = ReadLockfile(ProjectLockfilePath(tmp))
+	if got, _ := lf.Find("test-skill"); got.Version != "1.0.0" {
+		t.Errorf("frozen install rewrote lockfile version to %q", got.Version)
+	}
+}
+
+func TestStatus_ReportsDrift(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	for _, id := range []string{"clean", "edited", "gone", "unlinked"} {
+		if _, err := si.InstallSkill(id, InstallOptions{ProjectDir: tmp, SkillContent: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	canonical := filepath.Join(tmp, agentregistry.CanonicalSkillsDir)
+	if err := os.WriteFile(filepath.Join(canonical, "edited", "SKILL.md"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(canonical, "gone")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(tmp, ".cursor", "skills", "unlinked")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(canonical, "stray"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := si.Status(tmp)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	got := make(map[string]SkillStatus, len(statuses))
+	for _, st := range statuses {
+		got[st.ID] = st
+	}
+	if got["clean"].State != StatusOK {
+		t.Errorf("clean: %#v", got["clean"])
+	}
+	if st := got["edited"]; st.State != StatusModified || len(st.ModifiedFiles) != 1 || st.ModifiedFiles[0] != "SKILL.md" {
+		t.Errorf("edited: %#v", st)
+	}
+	if got["gone"].State != StatusMissing {
+		t.Errorf("gone: %#v", got["gone"])
+	}
+	if st := got["unlinked"]; st.State != StatusModified || st.LinkIssues["cursor"] != "missing" {
+		t.Errorf("unlinked: %#v", st)
+	}
+	if got["stray"].State != StatusUntracked {
+		t.Errorf("stray: %#v", got["stray"])
+	}
+}
+
+func TestReadLockfile_MissingFileIsEmpty(t *testing.T) {
+	lf, err := ReadLockfile(filepath.Join(t.TempDir(), LockfileName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lf.Skills) != 0 {
+		t.Errorf("expected empty lockfile, got %#v", lf)
+	}
+}
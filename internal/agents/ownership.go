@@ -0,0 +1,183 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+// ManagedMarker is the file aios writes into every skill directory it
+// creates. Entries without it, and symlinks that do not resolve to the
+// canonical skill directory, are treated as user-owned.
+const ManagedMarker = ".aios-managed"
+
+// ConflictError reports existing entries that aios did not create and
+// therefore refuses to replace or remove without force.
+type ConflictError struct {
+	SkillID string
+	Paths   []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("skill %s conflicts with entries not managed by aios:\n  %s\n\nMove them out of the way, or rerun with --force to back them up and replace them",
+		e.SkillID, strings.Join(e.Paths, "\n  "))
+}
+
+type managedMarker struct {
+	ManagedBy string `json:"managed_by"`
+	SkillID   string `json:"skill_id"`
+}
+
+func writeManagedMarker(dir, skillID string) error {
+	data, err := json.Marshal(managedMarker{ManagedBy: "aios", SkillID: skillID})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManagedMarker), append(data, '\n'), 0o644)
+}
+
+func hasManagedMarker(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ManagedMarker))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isManagedLink reports whether the entry at path is a symlink to
+// canonicalDir or a directory carrying the managed marker. A missing
+// entry is reported as absent.
+func isManagedLink(path, canonicalDir string) (exists, managed bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, false
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return true, pointsTo(path, canonicalDir)
+	}
+	return true, info.IsDir() && hasManagedMarker(path)
+}
+
+// pointsTo reports whether the symlink at path targets dir, comparing the
+// link text lexically so a dangling link to a removed canonical dir still
+// counts.
+func pointsTo(path, dir string) bool {
+	dest, err := os.Readlink(path)
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(path), dest)
+	}
+	if filepath.Clean(dest) == filepath.Clean(dir) {
+		return true
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	want, err := filepath.EvalSymlinks(dir)
+	return err == nil && resolved == want
+}
+
+// isManagedCanonical reports whether the canonical skill directory was
+// created by aios: it carries the marker or is recorded in the lockfile.
+// The lockfile check covers installs made before markers existed.
+func isManagedCanonical(canonicalDir string, lf *Lockfile) (exists, managed bool) {
+	info, err := os.Lstat(canonicalDir)
+	if err != nil {
+		return false, false
+	}
+	if !info.IsDir() {
+		return true, false
+	}
+	if hasManagedMarker(canonicalDir) {
+		return true, true
+	}
+	base := filepath.Base(canonicalDir)
+	for _, s := range lf.Skills {
+		if SanitizeName(s.ID) == base {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// unmanagedEntries returns the existing entries for a skill in layout that
+// aios does not own, sorted. linkPaths are the agent entries to check.
+func unmanagedEntries(layout installLayout, skillID string, linkPaths []string) ([]string, error) {
+	lf, err := ReadLockfile(lockfilePath(layout))
+	if err != nil {
+		return nil, err
+	}
+	canonicalDir := filepath.Join(layout.canonicalRoot, SanitizeName(skillID))
+	var out []string
+	if exists, managed := isManagedCanonical(canonicalDir, lf); exists && !managed {
+		out = append(out, canonicalDir)
+	}
+	for _, p := range linkPaths {
+		if exists, managed := isManagedLink(p, canonicalDir); exists && !managed {
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// linkPaths returns the entry path for a skill directory named name in
+// every agent of targets that needs its own link.
+func linkPaths(layout installLayout, targets []agentregistry.AgentDefinition, name string) []string {
+	var out []string
+	for _, agent := range targets {
+		dir, linked := layout.agentRoot(agent)
+		if dir == "" || !linked {
+			continue
+		}
+		out = append(out, filepath.Join(dir, name))
+	}
+	return out
+}
+
+// backupSet moves replaced user content into a timestamped directory under
+// .agents/backups, keeping each entry's path relative to the layout root.
+type backupSet struct {
+	root string
+	base string
+	dir  string
+}
+
+func newBackupSet(layout installLayout) *backupSet {
+	agentsDir := filepath.Dir(layout.canonicalRoot)
+	return &backupSet{
+		root: filepath.Dir(agentsDir),
+		base: filepath.Join(agentsDir, "backups"),
+	}
+}
+
+// pathFor returns where target is preserved, creating the backup session
+// directory on first use.
+func (b *backupSet) pathFor(tx *installTxn, target string) (string, error) {
+	if b.dir == "" {
+		if err := tx.mkdirAll(b.base); err != nil {
+			return "", fmt.Errorf("creating backup dir: %w", err)
+		}
+		dir, err := os.MkdirTemp(b.base, time.Now().UTC().Format("20060102T150405Z")+"-")
+		if err != nil {
+			return "", fmt.Errorf("creating backup dir: %w", err)
+		}
+		tx.createdDirs = append(tx.createdDirs, dir)
+		b.dir = dir
+	}
+	rel, err := filepath.Rel(b.root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = strings.TrimLeft(filepath.ToSlash(target), "/")
+	}
+	dest := filepath.Join(b.dir, rel)
+	if err := tx.mkdirAll(filepath.Dir(dest)); err != nil {
+		return "", fmt.Errorf("creating backup dir: %w", err)
+	}
+	return dest, nil
+}
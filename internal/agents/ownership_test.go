@@ -0,0 +1,174 @@
+package agents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+func writeHandWrittenSkill(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("hand-written"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallSkill_RefusesUnmanagedAgentDir(t *testing.T) {
+	tmp := t.TempDir()
+	handWritten := filepath.Join(tmp, ".claude", "skills", "review")
+	writeHandWrittenSkill(t, handWritten)
+
+	_, err := NewSkillInstaller(testAgentDefs()).InstallSkill("review", InstallOptions{ProjectDir: tmp})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if len(conflict.Paths) != 1 || conflict.Paths[0] != handWritten {
+		t.Errorf("unexpected conflict paths: %v", conflict.Paths)
+	}
+	if data, _ := os.ReadFile(filepath.Join(handWritten, "SKILL.md")); string(data) != "hand-written" {
+		t.Error("hand-written skill was modified")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".agents")); !os.IsNotExist(err) {
+		t.Error("conflicting install should not write anything")
+	}
+}
+
+func TestInstallSkill_RefusesUnmanagedCanonicalDir(t *testing.T) {
+	tmp := t.TempDir()
+	writeHandWrittenSkill(t, filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review"))
+
+	_, err := NewSkillInstaller(testAgentDefs()).InstallSkill("review", InstallOptions{ProjectDir: tmp})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+}
+
+func TestInstallSkill_RefusesForeignSymlink(t *testing.T) {
+	tmp := t.TempDir()
+	elsewhere := filepath.Join(tmp, "elsewhere")
+	writeHandWrittenSkill(t, elsewhere)
+	if err := os.MkdirAll(filepath.Join(tmp, ".cursor", "skills"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(elsewhere, filepath.Join(tmp, ".cursor", "skills", "review")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewSkillInstaller(testAgentDefs()).InstallSkill("review", InstallOptions{ProjectDir: tmp})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+}
+
+func TestInstallSkill_ForceBacksUpUnmanagedEntries(t *testing.T) {
+	tmp := t.TempDir()
+	handWritten := filepath.Join(tmp, ".claude", "skills", "review")
+	writeHandWrittenSkill(t, handWritten)
+
+	result, err := NewSkillInstaller(testAgentDefs()).InstallSkill("review", InstallOptions{ProjectDir: tmp, Force: true})
+	if err != nil {
+		t.Fatalf("forced install: %v", err)
+	}
+	var backup string
+	for _, c := range result.Committed {
+		if c.Path == handWritten {
+			backup = c.BackupPath
+		}
+	}
+	if backup == "" {
+		t.Fatalf("expected backup path for %s in %#v", handWritten, result.Committed)
+	}
+	if data, err := os.ReadFile(filepath.Join(backup, "SKILL.md")); err != nil || string(data) != "hand-written" {
+		t.Fatalf("expected backed up content, got %q (%v)", data, err)
+	}
+	if rel, _ := filepath.Rel(filepath.Join(tmp, ".agents", "backups"), backup); filepath.Base(rel) != "review" {
+		t.Errorf("unexpected backup location %s", backup)
+	}
+	if info, err := os.Lstat(handWritten); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected hand-written dir replaced by a symlink")
+	}
+}
+
+func TestInstallSkill_ReplacesManagedEntriesWithoutForce(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	canonical := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review")
+	if _, err := os.Stat(filepath.Join(canonical, ManagedMarker)); err != nil {
+		t.Fatalf("expected managed marker: %v", err)
+	}
+
+	// A marked copy, as left by the symlink fallback, is managed too.
+	copyPath := filepath.Join(tmp, ".cursor", "skills", "review")
+	if err := os.Remove(copyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := copyDirectory(canonical, copyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManagedMarker(copyPath, "review"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatalf("reinstall over managed entries: %v", err)
+	}
+}
+
+func TestInstallSkill_LockfileEntryMarksLegacyCanonicalManaged(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review", ManagedMarker)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatalf("reinstall over locked canonical dir: %v", err)
+	}
+}
+
+func TestUninstall_RefusesUnmanagedUnlessForced(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	handWritten := filepath.Join(tmp, ".claude", "skills", "review")
+	if err := os.Remove(handWritten); err != nil {
+		t.Fatal(err)
+	}
+	writeHandWrittenSkill(t, handWritten)
+
+	err := si.UninstallSkill("review", tmp)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "review")); err != nil {
+		t.Fatal("refused uninstall should not remove anything")
+	}
+
+	result, err := si.Uninstall("review", UninstallOptions{ProjectDir: tmp, Force: true})
+	if err != nil {
+		t.Fatalf("forced uninstall: %v", err)
+	}
+	backup := result.Backups[handWritten]
+	if data, err := os.ReadFile(filepath.Join(backup, "SKILL.md")); err != nil || string(data) != "hand-written" {
+		t.Fatalf("expected backed up content at %q, got %q (%v)", backup, data, err)
+	}
+	if _, err := os.Lstat(handWritten); !os.IsNotExist(err) {
+		t.Error("expected hand-written dir removed")
+	}
+}
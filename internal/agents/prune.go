@@ -0,0 +1,189 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PruneKind classifies a leftover skill entry found by Prune.
+type PruneKind string
+
+const (
+	// PruneDanglingLink is an agent symlink into the canonical skills
+	// directory whose target no longer exists.
+	PruneDanglingLink PruneKind = "dangling-link"
+	// PruneStaleCopy is an aios-managed copy in an agent directory whose
+	// canonical skill directory was removed.
+	PruneStaleCopy PruneKind = "stale-copy"
+	// PruneUnreferenced is an aios-managed canonical skill directory that
+	// is not in the lockfile and that no agent entry links to.
+	PruneUnreferenced PruneKind = "unreferenced-canonical"
+)
+
+// PruneAction is one entry Prune removes, or would remove in a dry run.
+type PruneAction struct {
+	Kind PruneKind `json:"kind"`
+	// Skill is the entry's directory name.
+	Skill string `json:"skill"`
+	Path  string `json:"path"`
+	// Agent is the agent whose skills directory holds the entry; empty for
+	// canonical entries.
+	Agent string `json:"agent,omitempty"`
+	// Target is where a dangling link points.
+	Target  string `json:"target,omitempty"`
+	Removed bool   `json:"removed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PruneOptions configures a prune.
+type PruneOptions struct {
+	// ProjectDir is the project to prune.
+	ProjectDir string
+
+	// Global prunes the user-level skill directories instead.
+	Global bool
+
+	// Apply removes the entries found; otherwise Prune only reports them.
+	Apply bool
+}
+
+// PruneResult lists the leftover entries found, sorted by path.
+type PruneResult struct {
+	Applied bool          `json:"applied"`
+	Actions []PruneAction `json:"actions"`
+}
+
+// Failed returns how many actions could not be applied.
+func (r *PruneResult) Failed() int {
+	n := 0
+	for _, a := range r.Actions {
+		if a.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Prune finds skill entries left behind by removed or partial installs:
+// dangling links, stale copies and unreferenced canonical directories. Only
+// entries aios created are considered; hand-written skills are never
+// touched. With opts.Apply they are removed, and a failure to remove one
+// entry is recorded on its action without stopping the others.
+func (si *SkillInstaller) Prune(opts PruneOptions) (*PruneResult, error) {
+	var layout installLayout
+	if opts.Global {
+		l, err := globalLayout()
+		if err != nil {
+			return nil, err
+		}
+		layout = l
+	} else {
+		if opts.ProjectDir == "" {
+			return nil, fmt.Errorf("project directory is required")
+		}
+		layout = projectLayout(opts.ProjectDir)
+	}
+	lf, err := ReadLockfile(lockfilePath(layout))
+	if err != nil {
+		return nil, err
+	}
+
+	actions, referenced := si.pruneAgentEntries(layout)
+
+	tracked := make(map[string]bool, len(lf.Skills))
+	for _, s := range lf.Skills {
+		tracked[SanitizeName(s.ID)] = true
+	}
+	if entries, err := os.ReadDir(layout.canonicalRoot); err == nil {
+		for _, e := range entries {
+			name := e.Name()
+			dir := filepath.Join(layout.canonicalRoot, name)
+			if strings.HasPrefix(name, ".") || !e.IsDir() || tracked[name] || referenced[name] || !hasManagedMarker(dir) {
+				continue
+			}
+			actions = append(actions, PruneAction{Kind: PruneUnreferenced, Skill: name, Path: dir})
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Path < actions[j].Path })
+
+	result := &PruneResult{Applied: opts.Apply, Actions: actions}
+	if !opts.Apply {
+		return result, nil
+	}
+	for i := range result.Actions {
+		a := &result.Actions[i]
+		if err := os.RemoveAll(a.Path); err != nil {
+			a.Error = err.Error()
+			continue
+		}
+		a.Removed = true
+	}
+	return result, nil
+}
+
+// pruneAgentEntries scans every linked agent skills directory once,
+// returning the dangling links and stale copies found and the canonical
+// entries that are still linked to.
+func (si *SkillInstaller) pruneAgentEntries(layout installLayout) ([]PruneAction, map[string]bool) {
+	var actions []PruneAction
+	referenced := make(map[string]bool)
+	scanned := make(map[string]bool)
+	for _, agent := range si.agents {
+		dir, linked := layout.agentRoot(agent)
+		if dir == "" || !linked || scanned[filepath.Clean(dir)] {
+			continue
+		}
+		scanned[filepath.Clean(dir)] = true
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			p := filepath.Join(dir, name)
+			info, err := os.Lstat(p)
+			if err != nil {
+				continue
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				dest, ok := canonicalLinkTarget(p, layout.canonicalRoot)
+				if !ok {
+					continue
+				}
+				if _, err := os.Stat(dest); err == nil {
+					referenced[filepath.Base(dest)] = true
+					continue
+				}
+				actions = append(actions, PruneAction{Kind: PruneDanglingLink, Skill: name, Path: p, Agent: agent.Name, Target: dest})
+				continue
+			}
+			if !info.IsDir() || !hasManagedMarker(p) {
+				continue
+			}
+			if dirExists(filepath.Join(layout.canonicalRoot, name)) {
+				referenced[name] = true
+				continue
+			}
+			actions = append(actions, PruneAction{Kind: PruneStaleCopy, Skill: name, Path: p, Agent: agent.Name})
+		}
+	}
+	return actions, referenced
+}
+
+// canonicalLinkTarget returns the absolute target of the symlink at p and
+// whether it is an entry directly inside canonicalRoot, comparing paths
+// lexically so dangling links still resolve.
+func canonicalLinkTarget(p, canonicalRoot string) (string, bool) {
+	dest, err := os.Readlink(p)
+	if err != nil {
+		return "", false
+	}
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(p), dest)
+	}
+	dest = filepath.Clean(dest)
+	return dest, filepath.Dir(dest) == filepath.Clean(canonicalRoot)
+}
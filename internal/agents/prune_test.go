@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+func TestPrune_ClassifiesAndRemovesLeftovers(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	canonicalRoot := filepath.Join(tmp, agentregistry.CanonicalSkillsDir)
+
+	// "gone" was installed, then its canonical directory was deleted.
+	if _, err := si.InstallSkill("gone", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(canonicalRoot, "gone")); err != nil {
+		t.Fatal(err)
+	}
+	// "kept" is tracked and installed for a universal agent only.
+	if _, err := si.InstallSkill("kept", InstallOptions{ProjectDir: tmp, TargetAgents: testAgentDefs()[:1]}); err != nil {
+		t.Fatal(err)
+	}
+	// "copied" is a managed copy whose canonical source was removed.
+	copied := filepath.Join(tmp, ".claude", "skills", "copied")
+	if err := os.MkdirAll(copied, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManagedMarker(copied, "copied"); err != nil {
+		t.Fatal(err)
+	}
+	// "orphan" is a managed canonical entry nothing refers to.
+	orphan := filepath.Join(canonicalRoot, "orphan")
+	if err := os.MkdirAll(orphan, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManagedMarker(orphan, "orphan"); err != nil {
+		t.Fatal(err)
+	}
+	// Hand-written entries are left alone.
+	mine := filepath.Join(tmp, ".cursor", "skills", "mine")
+	if err := os.MkdirAll(mine, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	elsewhere := filepath.Join(tmp, ".cursor", "skills", "elsewhere")
+	if err := os.Symlink(filepath.Join(tmp, "missing"), elsewhere); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(canonicalRoot, "handmade"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := si.Prune(PruneOptions{ProjectDir: tmp})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	want := map[string]PruneKind{
+		filepath.Join(tmp, ".claude", "skills", "gone"): PruneDanglingLink,
+		filepath.Join(tmp, ".cursor", "skills", "gone"): PruneDanglingLink,
+		copied: PruneStaleCopy,
+		orphan: PruneUnreferenced,
+	}
+	if len(dry.Actions) != len(want) {
+		t.Fatalf("expected %d actions, got %#v", len(want), dry.Actions)
+	}
+	for _, a := range dry.Actions {
+		if want[a.Path] != a.Kind || a.Removed {
+			t.Errorf("unexpected action %#v", a)
+		}
+		if _, err := os.Lstat(a.Path); err != nil {
+			t.Errorf("dry run removed %s", a.Path)
+		}
+	}
+
+	applied, err := si.Prune(PruneOptions{ProjectDir: tmp, Apply: true})
+	if err != nil {
+		t.Fatalf("prune --apply: %v", err)
+	}
+	if !applied.Applied || applied.Failed() != 0 {
+		t.Fatalf("unexpected result %#v", applied)
+	}
+	for path := range want {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed", path)
+		}
+	}
+	for _, path := range []string{mine, elsewhere, filepath.Join(canonicalRoot, "handmade"), filepath.Join(canonicalRoot, "kept")} {
+		if _, err := os.Lstat(path); err != nil {
+			t.Errorf("expected %s kept: %v", path, err)
+		}
+	}
+
+	again, err := si.Prune(PruneOptions{ProjectDir: tmp})
+	if err != nil || len(again.Actions) != 0 {
+		t.Fatalf("expected nothing left to prune, got %#v (%v)", again, err)
+	}
+}
+
+func TestPrune_KeepsLinkedCanonicalEntries(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+	if _, err := si.InstallSkill("review", InstallOptions{ProjectDir: tmp}); err != nil {
+		t.Fatal(err)
+	}
+	// Without a lockfile entry the skill is still referenced by its links.
+	if err := os.Remove(ProjectLockfilePath(tmp)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := si.Prune(PruneOptions{ProjectDir: tmp})
+	if err != nil || len(result.Actions) != 0 {
+		t.Fatalf("expected nothing to prune, got %#v (%v)", result, err)
+	}
+}
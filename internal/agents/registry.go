@@ -1,6 +1,7 @@
 // Package agents provides the infrastructure implementation for the agent
-// registry. It loads agent definitions from an embedded JSON file and provides
-// system-level operations such as agent detection and path resolution.
+// registry. It loads agent definitions from an embedded JSON file, layers
+// user-level and workspace-level overrides on top, and provides system-level
+// operations such as agent detection and path resolution.
 package agents
 
 import (
@@ -26,13 +27,153 @@ type agentJSON struct {
 	GlobalSkillsDir string   `json:"globalSkillsDir"`
 	DetectPaths     []string `json:"detectPaths"`
 	Universal       bool     `json:"universal"`
+	Renderer        string   `json:"renderer,omitempty"`
 }
 
-// LoadAll parses the embedded agent definitions and returns validated domain
-// objects. Returns an error if the JSON is malformed or any agent fails
-// validation.
+// Agent definition sources, in increasing order of precedence.
+const (
+	SourceEmbedded  = "embedded"
+	SourceUser      = "user"
+	SourceWorkspace = "workspace"
+)
+
+// SourcedAgent pairs an agent definition with the layer it was loaded from.
+type SourcedAgent struct {
+	agentregistry.AgentDefinition
+
+	// Source is one of SourceEmbedded, SourceUser or SourceWorkspace and names
+	// the highest-precedence layer that defined or overrode the agent.
+	Source string
+
+	// Path is the override file the definition came from. Empty for
+	// embedded definitions.
+	Path string
+}
+
+// definitionLayer is an optional override file merged over the embedded
+// definitions.
+type definitionLayer struct {
+	source string
+	path   string
+}
+
+// LoadAll returns the validated agent definitions with the user-level
+// ($XDG_CONFIG/aios/agents.json) and workspace-level (<workspace>/agents.json)
+// overrides merged over the embedded list. Returns an error if any JSON is
+// malformed or any agent fails validation.
 func LoadAll() ([]agentregistry.AgentDefinition, error) {
-	return loadFrom(embeddedAgentsJSON)
+	sourced, err := LoadAllWithSources()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]agentregistry.AgentDefinition, 0, len(sourced))
+	for _, a := range sourced {
+		out = append(out, a.AgentDefinition)
+	}
+	return out, nil
+}
+
+// LoadAllWithSources is like LoadAll but also reports which layer each
+// definition came from.
+func LoadAllWithSources() ([]SourcedAgent, error) {
+	return loadLayered(embeddedAgentsJSON, definitionLayers())
+}
+
+// UserAgentsPath returns the user-level agent override file path.
+func UserAgentsPath() string {
+	return expandPath("$XDG_CONFIG/aios/agents.json")
+}
+
+// WorkspaceAgentsPath returns the workspace-level agent override file path.
+// The workspace root follows AIOS_WORKSPACE_DIR, defaulting to ./.aios.
+func WorkspaceAgentsPath() string {
+	root := strings.TrimSpace(os.Getenv("AIOS_WORKSPACE_DIR"))
+	if root == "" {
+		root = filepath.Join(".", ".aios")
+	}
+	return filepath.Join(root, "agents.json")
+}
+
+func definitionLayers() []definitionLayer {
+	return []definitionLayer{
+		{source: SourceUser, path: UserAgentsPath()},
+		{source: SourceWorkspace, path: WorkspaceAgentsPath()},
+	}
+}
+
+// loadLayered parses the base definitions and merges each layer over them by
+// agent name. Fields present in an override entry replace the inherited
+// values; absent fields are kept. Entries with a new name are appended.
+// Missing layer files are skipped.
+func loadLayered(base []byte, layers []definitionLayer) ([]SourcedAgent, error) {
+	var raw []agentJSON
+	if err := json.Unmarshal(base, &raw); err != nil {
+		return nil, fmt.Errorf("parsing agent definitions: %w", err)
+	}
+	sources := make([]definitionLayer, len(raw))
+	for i := range sources {
+		sources[i] = definitionLayer{source: SourceEmbedded}
+	}
+	index := make(map[string]int, len(raw))
+	for i, r := range raw {
+		index[r.Name] = i
+	}
+
+	for _, layer := range layers {
+		// #nosec G304 -- override paths are fixed config locations.
+		data, err := os.ReadFile(filepath.Clean(layer.path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s agent definitions: %w", layer.source, err)
+		}
+		var overrides []json.RawMessage
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, fmt.Errorf("parsing %s agent definitions %s: %w", layer.source, layer.path, err)
+		}
+		for _, entry := range overrides {
+			var probe struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(entry, &probe); err != nil {
+				return nil, fmt.Errorf("parsing %s agent definitions %s: %w", layer.source, layer.path, err)
+			}
+			if i, ok := index[probe.Name]; ok {
+				merged := raw[i]
+				if err := json.Unmarshal(entry, &merged); err != nil {
+					return nil, fmt.Errorf("parsing %s agent definitions %s: %w", layer.source, layer.path, err)
+				}
+				raw[i] = merged
+				sources[i] = layer
+				continue
+			}
+			var added agentJSON
+			if err := json.Unmarshal(entry, &added); err != nil {
+				return nil, fmt.Errorf("parsing %s agent definitions %s: %w", layer.source, layer.path, err)
+			}
+			index[added.Name] = len(raw)
+			raw = append(raw, added)
+			sources = append(sources, layer)
+		}
+	}
+
+	out := make([]SourcedAgent, 0, len(raw))
+	for i, r := range raw {
+		a := r.toDomain()
+		if err := a.Validate(); err != nil {
+			if sources[i].path != "" {
+				return nil, fmt.Errorf("invalid agent definition in %s: %w", sources[i].path, err)
+			}
+			return nil, fmt.Errorf("invalid agent definition: %w", err)
+		}
+		out = append(out, SourcedAgent{
+			AgentDefinition: a,
+			Source:          sources[i].source,
+			Path:            sources[i].path,
+		})
+	}
+	return out, nil
 }
 
 // loadFrom parses agent definitions from the given JSON bytes.
@@ -43,15 +184,7 @@ func loadFrom(data []byte) ([]agentregistry.AgentDefinition, error) {
 	}
 	agents := make([]agentregistry.AgentDefinition, 0, len(raw))
 	for _, r := range raw {
-		a := agentregistry.AgentDefinition{
-			Name:            r.Name,
-			DisplayName:     r.DisplayName,
-			SkillsDir:       r.SkillsDir,
-			AltSkillsDirs:   r.AltSkillsDirs,
-			GlobalSkillsDir: r.GlobalSkillsDir,
-			DetectPaths:     r.DetectPaths,
-			Universal:       r.Universal,
-		}
+		a := r.toDomain()
 		if err := a.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid agent definition: %w", err)
 		}
@@ -60,6 +193,47 @@ func loadFrom(data []byte) ([]agentregistry.AgentDefinition, error) {
 	return agents, nil
 }
 
+func (r agentJSON) toDomain() agentregistry.AgentDefinition {
+	return agentregistry.AgentDefinition{
+		Name:            r.Name,
+		DisplayName:     r.DisplayName,
+		SkillsDir:       r.SkillsDir,
+		AltSkillsDirs:   r.AltSkillsDirs,
+		GlobalSkillsDir: r.GlobalSkillsDir,
+		DetectPaths:     r.DetectPaths,
+		Universal:       r.Universal,
+		Renderer:        r.Renderer,
+	}
+}
+
+// ResolveTargets returns the agents a skill is installed for: the agents
+// named in names when given, otherwise those named in clients (the
+// skill.yaml clients list), otherwise all of them. Names resolve with
+// agentregistry.ResolveByNames, so unknown agents are an error. Global
+// installs keep only the agents detected on this machine, and fail when
+// none of the named agents are.
+func ResolveTargets(all []agentregistry.AgentDefinition, names, clients []string, global bool) ([]agentregistry.AgentDefinition, error) {
+	if len(names) == 0 {
+		names = clients
+	}
+	targets := all
+	if len(names) > 0 {
+		resolved, err := agentregistry.ResolveByNames(all, names)
+		if err != nil {
+			return nil, err
+		}
+		targets = resolved
+	}
+	if !global {
+		return targets, nil
+	}
+	detected := DetectInstalled(targets)
+	if len(names) > 0 && len(detected) == 0 {
+		return nil, fmt.Errorf("none of the selected agents (%s) are installed on this machine", strings.Join(names, ", "))
+	}
+	return detected, nil
+}
+
 // DetectInstalled returns agent definitions for agents detected on the system
 // by checking whether any of their detection paths exist as directories.
 func DetectInstalled(agents []agentregistry.AgentDefinition) []agentregistry.AgentDefinition {
@@ -96,11 +270,25 @@ func DetectInFolder(agents []agentregistry.AgentDefinition, folderPath string) [
 }
 
 // ResolveGlobalSkillsDir expands environment variables and ~ in the agent's
-// global skills directory path.
+// global skills directory path. Returns an empty string when the agent has no
+// global directory or the path references an unset environment variable
+// (e.g. $CODEX_HOME), since expanding it would point at the filesystem root.
 func ResolveGlobalSkillsDir(agent agentregistry.AgentDefinition) string {
+	if strings.TrimSpace(agent.GlobalSkillsDir) == "" || hasUnsetEnv(agent.GlobalSkillsDir) {
+		return ""
+	}
 	return expandPath(agent.GlobalSkillsDir)
 }
 
+// ResolveGlobalCanonicalSkillsDir returns the absolute user-level canonical
+// skills directory, or an empty string if the home directory is unknown.
+func ResolveGlobalCanonicalSkillsDir() string {
+	if home, err := os.UserHomeDir(); err != nil || home == "" {
+		return ""
+	}
+	return expandPath(agentregistry.GlobalCanonicalSkillsDir)
+}
+
 // ResolveProjectSkillsDir returns the absolute path to an agent's skill
 // directory within a project folder.
 func ResolveProjectSkillsDir(agent agentregistry.AgentDefinition, projectDir string) string {
@@ -148,6 +336,19 @@ func expandPath(p string) string {
 	return p
 }
 
+// hasUnsetEnv reports whether p references an environment variable other
+// than $XDG_CONFIG (which has a default) that is not set.
+func hasUnsetEnv(p string) bool {
+	unset := false
+	os.Expand(strings.ReplaceAll(p, "$XDG_CONFIG", ""), func(key string) string {
+		if _, ok := os.LookupEnv(key); !ok {
+			unset = true
+		}
+		return ""
+	})
+	return unset
+}
+
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
 	return err == nil && info.IsDir()
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
@@ -366,3 +367,205 @@ func TestDirExists_FileNotDir(t *testing.T) {
 		t.Error("expected false for regular file")
 	}
 }
+
+func writeAgentsFile(t *testing.T, path string, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findSourced(agents []SourcedAgent, name string) (SourcedAgent, bool) {
+	for _, a := range agents {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return SourcedAgent{}, false
+}
+
+func TestLoadLayered_OverridesFieldsByName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	writeAgentsFile(t, path, `[{"name":"cline","skillsDir":".cline/custom-skills"}]`)
+
+	agents, err := loadLayered(embeddedAgentsJSON, []definitionLayer{{source: SourceUser, path: path}})
+	if err != nil {
+		t.Fatalf("loadLayered() error: %v", err)
+	}
+	cline, ok := findSourced(agents, "cline")
+	if !ok {
+		t.Fatal("cline missing after merge")
+	}
+	if cline.SkillsDir != ".cline/custom-skills" {
+		t.Errorf("expected overridden skills dir, got %q", cline.SkillsDir)
+	}
+	if cline.DisplayName != "Cline" {
+		t.Errorf("expected inherited display name, got %q", cline.DisplayName)
+	}
+	if cline.Source != SourceUser || cline.Path != path {
+		t.Errorf("unexpected source %q (%q)", cline.Source, cline.Path)
+	}
+	claude, _ := findSourced(agents, "claude-code")
+	if claude.Source != SourceEmbedded {
+		t.Errorf("expected untouched agent to stay embedded, got %q", claude.Source)
+	}
+}
+
+func TestLoadLayered_OverridesRenderer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	writeAgentsFile(t, path, `[{"name":"cursor","renderer":"skill-md"},{"name":"goose","renderer":"clinerules"}]`)
+
+	agents, err := loadLayered(embeddedAgentsJSON, []definitionLayer{{source: SourceUser, path: path}})
+	if err != nil {
+		t.Fatalf("loadLayered() error: %v", err)
+	}
+	cursor, _ := findSourced(agents, "cursor")
+	goose, _ := findSourced(agents, "goose")
+	if cursor.Renderer != "skill-md" || goose.Renderer != "clinerules" {
+		t.Errorf("expected overridden renderers, got cursor=%q goose=%q", cursor.Renderer, goose.Renderer)
+	}
+	windsurf, _ := findSourced(agents, "windsurf")
+	if windsurf.Renderer != "windsurf-rules" {
+		t.Errorf("expected embedded windsurf renderer, got %q", windsurf.Renderer)
+	}
+}
+
+func TestLoadLayered_AppendsNewAgents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	writeAgentsFile(t, path, `[{"name":"inhouse","displayName":"In-House","skillsDir":".inhouse/skills","detectPaths":["~/.inhouse"]}]`)
+
+	agents, err := loadLayered(embeddedAgentsJSON, []definitionLayer{{source: SourceWorkspace, path: path}})
+	if err != nil {
+		t.Fatalf("loadLayered() error: %v", err)
+	}
+	if len(agents) != 10 {
+		t.Fatalf("expected 10 agents, got %d", len(agents))
+	}
+	last := agents[len(agents)-1]
+	if last.Name != "inhouse" || last.Source != SourceWorkspace {
+		t.Errorf("expected appended workspace agent, got %q from %q", last.Name, last.Source)
+	}
+}
+
+func TestLoadLayered_WorkspaceTakesPrecedenceOverUser(t *testing.T) {
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.json")
+	workspacePath := filepath.Join(dir, "workspace.json")
+	writeAgentsFile(t, userPath, `[{"name":"goose","skillsDir":".goose/user"}]`)
+	writeAgentsFile(t, workspacePath, `[{"name":"goose","skillsDir":".goose/workspace"}]`)
+
+	agents, err := loadLayered(embeddedAgentsJSON, []definitionLayer{
+		{source: SourceUser, path: userPath},
+		{source: SourceWorkspace, path: workspacePath},
+	})
+	if err != nil {
+		t.Fatalf("loadLayered() error: %v", err)
+	}
+	goose, _ := findSourced(agents, "goose")
+	if goose.SkillsDir != ".goose/workspace" || goose.Source != SourceWorkspace {
+		t.Errorf("expected workspace override, got %q from %q", goose.SkillsDir, goose.Source)
+	}
+}
+
+func TestLoadLayered_MissingFilesAreSkipped(t *testing.T) {
+	agents, err := loadLayered(embeddedAgentsJSON, []definitionLayer{
+		{source: SourceUser, path: filepath.Join(t.TempDir(), "missing.json")},
+	})
+	if err != nil {
+		t.Fatalf("loadLayered() error: %v", err)
+	}
+	if len(agents) != 9 {
+		t.Errorf("expected 9 agents, got %d", len(agents))
+	}
+}
+
+func TestLoadLayered_InvalidOverrideFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	writeAgentsFile(t, path, `[{"name":"cursor","universal":true}]`)
+
+	_, err := loadLayered(embeddedAgentsJSON, []definitionLayer{{source: SourceUser, path: path}})
+	if err == nil {
+		t.Fatal("expected validation error for universal agent with non-canonical dir")
+	}
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("expected error to name the override file, got %v", err)
+	}
+}
+
+func TestLoadLayered_MalformedOverrideFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	writeAgentsFile(t, path, `{"name":"cursor"}`)
+
+	if _, err := loadLayered(embeddedAgentsJSON, []definitionLayer{{source: SourceUser, path: path}}); err == nil {
+		t.Fatal("expected parse error for non-array override file")
+	}
+}
+
+func TestLoadAll_MergesUserAndWorkspaceFiles(t *testing.T) {
+	configDir := t.TempDir()
+	workspaceDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("AIOS_WORKSPACE_DIR", workspaceDir)
+	writeAgentsFile(t, filepath.Join(configDir, "aios", "agents.json"),
+		`[{"name":"personal","displayName":"Personal","skillsDir":".personal/skills"}]`)
+	writeAgentsFile(t, filepath.Join(workspaceDir, "agents.json"),
+		`[{"name":"cursor","skillsDir":".cursor/rules-skills"}]`)
+
+	sourced, err := LoadAllWithSources()
+	if err != nil {
+		t.Fatalf("LoadAllWithSources() error: %v", err)
+	}
+	if a, ok := findSourced(sourced, "personal"); !ok || a.Source != SourceUser {
+		t.Errorf("expected user-defined agent, got %+v", a)
+	}
+	all, err := LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error: %v", err)
+	}
+	for _, a := range all {
+		if a.Name == "cursor" && a.SkillsDir != ".cursor/rules-skills" {
+			t.Errorf("LoadAll did not apply workspace override: %q", a.SkillsDir)
+		}
+	}
+}
+
+func TestResolveTargets(t *testing.T) {
+	all := testAgentDefs()
+
+	targets, err := ResolveTargets(all, nil, []string{"cursor"}, false)
+	if err != nil || len(targets) != 1 || targets[0].Name != "cursor" {
+		t.Fatalf("expected clients to select cursor, got %v (%v)", targets, err)
+	}
+	targets, err = ResolveTargets(all, []string{"claude-code"}, []string{"cursor"}, false)
+	if err != nil || len(targets) != 1 || targets[0].Name != "claude-code" {
+		t.Fatalf("expected names to override clients, got %v (%v)", targets, err)
+	}
+	if targets, _ := ResolveTargets(all, nil, nil, false); len(targets) != len(all) {
+		t.Errorf("expected all agents without names or clients, got %d", len(targets))
+	}
+	if _, err := ResolveTargets(all, []string{"vim"}, nil, false); err == nil {
+		t.Error("expected error for unknown agent")
+	}
+}
+
+func TestResolveTargets_GlobalRequiresInstalledAgent(t *testing.T) {
+	home := t.TempDir()
+	all := []agentregistry.AgentDefinition{
+		{Name: "cursor", DisplayName: "Cursor", SkillsDir: ".cursor/skills", DetectPaths: []string{filepath.Join(home, ".cursor")}},
+		{Name: "claude-code", DisplayName: "Claude Code", SkillsDir: ".claude/skills", DetectPaths: []string{filepath.Join(home, ".claude")}},
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := ResolveTargets(all, nil, nil, true)
+	if err != nil || len(targets) != 1 || targets[0].Name != "claude-code" {
+		t.Fatalf("expected detected agents only, got %v (%v)", targets, err)
+	}
+	if _, err := ResolveTargets(all, []string{"cursor"}, nil, true); err == nil || !strings.Contains(err.Error(), "cursor") {
+		t.Errorf("expected error for undetected agent, got %v", err)
+	}
+}
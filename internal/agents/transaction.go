@@ -0,0 +1,174 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CommittedEntry is one path an install swapped into place.
+type CommittedEntry struct {
+	// Agent is the agent name the entry serves, or empty for the canonical
+	// skill directory.
+	Agent string
+
+	// Path is the committed location.
+	Path string
+
+	// LinkType is LinkCanonical for the canonical directory, LinkRendered
+	// for a rendered artifact, otherwise LinkSymlink or LinkCopy.
+	LinkType string
+
+	// Replaced reports whether a previous entry existed at Path.
+	Replaced bool
+
+	// BackupPath is where a replaced entry not managed by aios was
+	// preserved, set only for forced installs.
+	BackupPath string
+}
+
+// InstallError reports a failed install. Every entry swapped in before the
+// failure has been rolled back; RollbackErrors lists any entry that could
+// not be restored.
+type InstallError struct {
+	SkillID        string
+	Err            error
+	RollbackErrors []error
+}
+
+func (e *InstallError) Error() string {
+	if len(e.RollbackErrors) == 0 {
+		return fmt.Sprintf("installing %s: %v (rolled back)", e.SkillID, e.Err)
+	}
+	return fmt.Sprintf("installing %s: %v (rollback incomplete: %v)", e.SkillID, e.Err, errors.Join(e.RollbackErrors...))
+}
+
+func (e *InstallError) Unwrap() error { return e.Err }
+
+// installTxn swaps staged entries into place with renames and remembers
+// how to undo each step.
+type installTxn struct {
+	swaps       []swap
+	createdDirs []string
+	committed   []CommittedEntry
+}
+
+type swap struct {
+	target string
+	// backup holds the previous entry, or is empty if target did not exist.
+	backup string
+	// keep preserves backup on commit instead of deleting it.
+	keep bool
+}
+
+// mkdirAll creates dir and records every directory it had to create so
+// rollback can remove them again.
+func (tx *installTxn) mkdirAll(dir string) error {
+	var missing []string
+	for p := filepath.Clean(dir); ; p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err == nil {
+			break
+		}
+		missing = append(missing, p)
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		tx.createdDirs = append(tx.createdDirs, missing[i])
+	}
+	return nil
+}
+
+// replace moves staged into target, keeping any previous entry at target
+// as a backup until commit. A non-empty keepAt moves the previous entry
+// there instead and preserves it after commit.
+func (tx *installTxn) replace(staged, target string, entry CommittedEntry, keepAt string) error {
+	s := swap{target: target}
+	if _, err := os.Lstat(target); err == nil {
+		s.backup = backupPath(target, len(tx.swaps))
+		if keepAt != "" {
+			s.backup, s.keep = keepAt, true
+			entry.BackupPath = keepAt
+		}
+		_ = os.RemoveAll(s.backup)
+		if err := os.Rename(target, s.backup); err != nil {
+			return fmt.Errorf("moving aside %s: %w", target, err)
+		}
+		entry.Replaced = true
+	}
+	if err := os.Rename(staged, target); err != nil {
+		if s.backup != "" {
+			_ = os.Rename(s.backup, target)
+		}
+		return fmt.Errorf("swapping in %s: %w", target, err)
+	}
+	tx.swaps = append(tx.swaps, s)
+	entry.Path = target
+	tx.committed = append(tx.committed, entry)
+	return nil
+}
+
+// remove moves target aside so rollback can restore it; commit deletes it.
+func (tx *installTxn) remove(target string) error {
+	backup := backupPath(target, len(tx.swaps))
+	_ = os.RemoveAll(backup)
+	if err := os.Rename(target, backup); err != nil {
+		return fmt.Errorf("removing %s: %w", target, err)
+	}
+	tx.swaps = append(tx.swaps, swap{target: target, backup: backup})
+	return nil
+}
+
+// rollback restores every replaced entry in reverse order and removes
+// directories the transaction created.
+func (tx *installTxn) rollback() []error {
+	var errs []error
+	for i := len(tx.swaps) - 1; i >= 0; i-- {
+		s := tx.swaps[i]
+		if err := os.RemoveAll(s.target); err != nil {
+			errs = append(errs, fmt.Errorf("removing %s: %w", s.target, err))
+			continue
+		}
+		if s.backup == "" {
+			continue
+		}
+		if err := os.Rename(s.backup, s.target); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", s.target, err))
+		}
+	}
+	for i := len(tx.createdDirs) - 1; i >= 0; i-- {
+		// Only empty directories are removed; anything else was not ours.
+		_ = os.Remove(tx.createdDirs[i])
+	}
+	tx.swaps = nil
+	tx.committed = nil
+	return errs
+}
+
+// commit drops the backups kept for rollback.
+func (tx *installTxn) commit() []CommittedEntry {
+	for _, s := range tx.swaps {
+		if s.backup != "" && !s.keep {
+			_ = os.RemoveAll(s.backup)
+		}
+	}
+	return tx.committed
+}
+
+// backupPath returns the hidden sibling used to keep a replaced entry. The
+// step index keeps backups distinct when one target is replaced twice, as
+// happens when two agents share a skills directory.
+func backupPath(target string, step int) string {
+	return filepath.Join(filepath.Dir(target), fmt.Sprintf(".%s.aios-backup-%d", filepath.Base(target), step))
+}
+
+// stagingDir creates a hidden temp dir beside target so the final rename
+// stays on one filesystem.
+func stagingDir(target string) (string, error) {
+	return os.MkdirTemp(filepath.Dir(target), "."+filepath.Base(target)+".aios-staging-")
+}
@@ -0,0 +1,124 @@
+package agents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+func TestInstallSkill_ReportsCommittedEntries(t *testing.T) {
+	tmp := t.TempDir()
+	si := NewSkillInstaller(testAgentDefs())
+
+	result, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp})
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if len(result.Committed) != 3 {
+		t.Fatalf("expected canonical + 2 links committed, got %#v", result.Committed)
+	}
+	first := result.Committed[0]
+	if first.Agent != "" || first.LinkType != LinkCanonical || first.Path != result.CanonicalPath || first.Replaced {
+		t.Errorf("unexpected canonical entry: %#v", first)
+	}
+	if c := result.Committed[1]; c.Agent != "cursor" || c.LinkType != LinkSymlink {
+		t.Errorf("unexpected cursor entry: %#v", c)
+	}
+
+	again, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp})
+	if err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	for _, c := range again.Committed {
+		if !c.Replaced {
+			t.Errorf("expected %s to be reported as replaced", c.Path)
+		}
+	}
+}
+
+func TestInstallSkill_RollsBackOnAgentFailure(t *testing.T) {
+	tmp := t.TempDir()
+	defs := testAgentDefs()
+	si := NewSkillInstaller(defs)
+	if _, err := si.InstallSkill("test-skill", InstallOptions{ProjectDir: tmp, SkillContent: "v1"}); err != nil {
+		t.Fatalf("install v1: %v", err)
+	}
+	lockBefore, err := os.ReadFile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A file where the agent's skills directory should be makes the last
+	// agent fail after the canonical dir and cursor link were swapped in.
+	if err := os.WriteFile(filepath.Join(tmp, "blocked"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := append(defs, agentregistry.AgentDefinition{Name: "broken", DisplayName: "Broken", SkillsDir: "blocked/skills"})
+	_, err = NewSkillInstaller(broken).InstallSkill("test-skill", InstallOptions{ProjectDir: tmp, SkillContent: "v2"})
+	var installErr *InstallError
+	if !errors.As(err, &installErr) {
+		t.Fatalf("expected InstallError, got %v", err)
+	}
+	if len(installErr.RollbackErrors) != 0 {
+		t.Fatalf("unexpected rollback errors: %v", installErr.RollbackErrors)
+	}
+
+	canonical := filepath.Join(tmp, agentregistry.CanonicalSkillsDir, "test-skill")
+	data, err := os.ReadFile(filepath.Join(canonical, "SKILL.md"))
+	if err != nil || string(data) != "v1" {
+		t.Fatalf("expected canonical SKILL.md restored to v1, got %q (%v)", data, err)
+	}
+	for _, dir := range []string{".cursor/skills", ".claude/skills"} {
+		link := filepath.Join(tmp, dir, "test-skill")
+		target, err := filepath.EvalSymlinks(link)
+		if err != nil {
+			t.Fatalf("expected %s restored: %v", link, err)
+		}
+		want, _ := filepath.EvalSymlinks(canonical)
+		if target != want {
+			t.Errorf("%s points to %s, want %s", link, target, want)
+		}
+	}
+	lockAfter, err := os.ReadFile(ProjectLockfilePath(tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lockBefore) != string(lockAfter) {
+		t.Error("lockfile changed by failed install")
+	}
+	assertNoTransactionLeftovers(t, tmp)
+}
+
+func TestInstallSkill_RollbackRemovesCreatedEntries(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "blocked"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defs := append(testAgentDefs(), agentregistry.AgentDefinition{Name: "broken", DisplayName: "Broken", SkillsDir: "blocked/skills"})
+
+	if _, err := NewSkillInstaller(defs).InstallSkill("test-skill", InstallOptions{ProjectDir: tmp}); err == nil {
+		t.Fatal("expected install to fail")
+	}
+	for _, p := range []string{".agents", ".cursor", ".claude"} {
+		if _, err := os.Lstat(filepath.Join(tmp, p)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed by rollback", p)
+		}
+	}
+}
+
+func assertNoTransactionLeftovers(t *testing.T, root string) {
+	t.Helper()
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if name := d.Name(); strings.Contains(name, ".aios-staging-") || strings.Contains(name, ".aios-backup") {
+			t.Errorf("leftover transaction entry: %s", path)
+		}
+		return nil
+	})
+}
@@ -19,5 +19,5 @@ func (s Service) LintSkill(ctx context.Context, command domain.LintSkillCommand)
 	if err := cmd.Validate(); err != nil {
 		return domain.LintSkillResult{}, err
 	}
-	return s.linter.Lint(ctx, cmd.SkillDir)
+	return s.linter.Lint(ctx, cmd.SkillDir, cmd.Fix)
 }
@@ -11,9 +11,13 @@ import (
 type fakeLinter struct {
 	result domain.LintSkillResult
 	err    error
+	fix    *bool
 }
 
-func (f fakeLinter) Lint(context.Context, string) (domain.LintSkillResult, error) {
+func (f fakeLinter) Lint(_ context.Context, _ string, fix bool) (domain.LintSkillResult, error) {
+	if f.fix != nil {
+		*f.fix = fix
+	}
 	return f.result, f.err
 }
 
@@ -28,6 +32,17 @@ func TestServiceLintSkill(t *testing.T) {
 	}
 }
 
+func TestServiceLintSkillPassesFix(t *testing.T) {
+	var fix bool
+	svc := NewService(fakeLinter{fix: &fix})
+	if _, err := svc.LintSkill(context.Background(), domain.LintSkillCommand{SkillDir: " /tmp/skill ", Fix: true}); err != nil {
+		t.Fatalf("lint failed: %v", err)
+	}
+	if !fix {
+		t.Fatal("expected fix to reach the linter")
+	}
+}
+
 func TestServiceLintSkillRequiresSkillDir(t *testing.T) {
 	svc := NewService(fakeLinter{})
 	_, err := svc.LintSkill(context.Background(), domain.LintSkillCommand{})
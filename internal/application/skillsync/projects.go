@@ -0,0 +1,138 @@
+package skillsync
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	domain "github.com/felixgeelhaar/aios/internal/domain/skillsync"
+)
+
+// ProjectSyncService installs a skill into many tracked projects through a
+// bounded pool of workers.
+type ProjectSyncService struct {
+	resolver  domain.SkillSpecResolver
+	installer domain.ClientInstaller
+	projects  domain.ProjectSource
+	now       func() time.Time
+}
+
+func NewProjectSyncService(resolver domain.SkillSpecResolver, installer domain.ClientInstaller, projects domain.ProjectSource) ProjectSyncService {
+	return ProjectSyncService{
+		resolver:  resolver,
+		installer: installer,
+		projects:  projects,
+		now:       time.Now,
+	}
+}
+
+// SyncProjects installs the skill into every selected project. A failing
+// project does not stop the others; failures are reported per project in
+// the result. progress may be nil.
+func (s ProjectSyncService) SyncProjects(ctx context.Context, command domain.SyncProjectsCommand, progress domain.ProgressFunc) (domain.SyncProjectsResult, error) {
+	cmd := command.Normalized()
+	if err := cmd.Validate(); err != nil {
+		return domain.SyncProjectsResult{}, err
+	}
+	skillID, err := s.resolver.ResolveSkillID(cmd.SkillDir)
+	if err != nil {
+		return domain.SyncProjectsResult{}, err
+	}
+	projects, err := s.selectProjects(ctx, cmd)
+	if err != nil {
+		return domain.SyncProjectsResult{}, err
+	}
+	if len(projects) == 0 {
+		return domain.SyncProjectsResult{}, domain.ErrNoProjects
+	}
+
+	results := make([]domain.ProjectSyncResult, len(projects))
+	jobs := make(chan int)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	for range min(cmd.Workers, len(projects)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.syncProject(ctx, skillID, cmd, projects[i])
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(results[i], done, len(projects))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range projects {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	out := domain.SyncProjectsResult{SkillID: skillID, Results: results}
+	for _, r := range results {
+		if r.OK {
+			out.Succeeded++
+		} else {
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+func (s ProjectSyncService) syncProject(ctx context.Context, skillID string, cmd domain.SyncProjectsCommand, project domain.ProjectRef) domain.ProjectSyncResult {
+	result := domain.ProjectSyncResult{Project: project}
+	if err := ctx.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	start := s.now()
+	err := s.installer.InstallSkillAcrossClients(ctx, domain.InstallRequest{
+		SkillID:    skillID,
+		SkillDir:   cmd.SkillDir,
+		ProjectDir: project.Path,
+		Force:      cmd.Force,
+		Agents:     cmd.Agents,
+	})
+	result.DurationMS = s.now().Sub(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.OK = true
+	return result
+}
+
+// selectProjects returns every tracked project sorted by path, or the
+// projects named by the command's selectors in the order given, without
+// duplicates.
+func (s ProjectSyncService) selectProjects(ctx context.Context, cmd domain.SyncProjectsCommand) ([]domain.ProjectRef, error) {
+	if cmd.AllProjects {
+		projects, err := s.projects.ListProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+		return projects, nil
+	}
+	seen := make(map[string]bool, len(cmd.Projects))
+	var out []domain.ProjectRef
+	for _, selector := range cmd.Projects {
+		p, err := s.projects.ResolveProject(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+		if seen[p.ID] {
+			continue
+		}
+		seen[p.ID] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
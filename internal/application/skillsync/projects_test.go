@@ -0,0 +1,128 @@
+package skillsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/felixgeelhaar/aios/internal/domain/skillsync"
+)
+
+type fakeProjectSource struct {
+	projects []domain.ProjectRef
+}
+
+func (f fakeProjectSource) ListProjects(context.Context) ([]domain.ProjectRef, error) {
+	return f.projects, nil
+}
+
+func (f fakeProjectSource) ResolveProject(_ context.Context, selector string) (domain.ProjectRef, error) {
+	for _, p := range f.projects {
+		if p.ID == selector || p.Path == selector {
+			return p, nil
+		}
+	}
+	return domain.ProjectRef{}, fmt.Errorf("project %q not found", selector)
+}
+
+// concurrentInstaller records the projects installed into and the peak
+// number of installs running at once.
+type concurrentInstaller struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+	dirs    []string
+	fail    map[string]bool
+}
+
+func (f *concurrentInstaller) InstallSkillAcrossClients(_ context.Context, request domain.InstallRequest) error {
+	f.mu.Lock()
+	f.running++
+	f.peak = max(f.peak, f.running)
+	f.dirs = append(f.dirs, request.ProjectDir)
+	f.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	if f.fail[request.ProjectDir] {
+		return errors.New("disk full")
+	}
+	return nil
+}
+
+func testProjects(n int) []domain.ProjectRef {
+	out := make([]domain.ProjectRef, n)
+	for i := range out {
+		out[n-1-i] = domain.ProjectRef{ID: fmt.Sprintf("id-%02d", i), Path: fmt.Sprintf("/src/repo-%02d", i)}
+	}
+	return out
+}
+
+func TestSyncProjectsBoundsWorkersAndReportsFailures(t *testing.T) {
+	installer := &concurrentInstaller{fail: map[string]bool{"/src/repo-03": true}}
+	svc := NewProjectSyncService(fakeSkillResolver{id: "review"}, installer, fakeProjectSource{projects: testProjects(10)})
+
+	var calls int
+	var mu sync.Mutex
+	progress := func(_ domain.ProjectSyncResult, done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if done != calls || total != 10 {
+			t.Errorf("unexpected progress %d/%d after %d calls", done, total, calls)
+		}
+	}
+	res, err := svc.SyncProjects(context.Background(), domain.SyncProjectsCommand{SkillDir: "/tmp/skill", AllProjects: true, Workers: 3}, progress)
+	if err != nil {
+		t.Fatalf("sync projects: %v", err)
+	}
+	if installer.peak > 3 {
+		t.Errorf("expected at most 3 concurrent installs, got %d", installer.peak)
+	}
+	if len(installer.dirs) != 10 || calls != 10 {
+		t.Fatalf("expected every project synced and reported, got %d installs and %d reports", len(installer.dirs), calls)
+	}
+	if res.SkillID != "review" || res.Succeeded != 9 || res.Failed != 1 {
+		t.Fatalf("unexpected summary: %#v", res)
+	}
+	for i, r := range res.Results {
+		if want := fmt.Sprintf("/src/repo-%02d", i); r.Project.Path != want {
+			t.Errorf("result %d: expected %s, got %s", i, want, r.Project.Path)
+		}
+	}
+	if r := res.Results[3]; r.OK || r.Error != "disk full" {
+		t.Errorf("expected repo-03 to fail, got %#v", r)
+	}
+}
+
+func TestSyncProjectsSelectsByIDOrPath(t *testing.T) {
+	installer := &concurrentInstaller{}
+	svc := NewProjectSyncService(fakeSkillResolver{id: "review"}, installer, fakeProjectSource{projects: testProjects(3)})
+	res, err := svc.SyncProjects(context.Background(), domain.SyncProjectsCommand{
+		SkillDir: "/tmp/skill",
+		Projects: []string{"/src/repo-02", "id-00", "id-02"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("sync projects: %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].Project.ID != "id-02" || res.Results[1].Project.ID != "id-00" {
+		t.Fatalf("expected selected projects in order without duplicates, got %#v", res.Results)
+	}
+
+	_, err = svc.SyncProjects(context.Background(), domain.SyncProjectsCommand{SkillDir: "/tmp/skill", Projects: []string{"missing"}}, nil)
+	if err == nil {
+		t.Fatal("expected unknown project to fail before installing")
+	}
+}
+
+func TestSyncProjectsRequiresTrackedProjects(t *testing.T) {
+	svc := NewProjectSyncService(fakeSkillResolver{id: "review"}, &concurrentInstaller{}, fakeProjectSource{})
+	_, err := svc.SyncProjects(context.Background(), domain.SyncProjectsCommand{SkillDir: "/tmp/skill", AllProjects: true}, nil)
+	if !errors.Is(err, domain.ErrNoProjects) {
+		t.Fatalf("expected ErrNoProjects, got %v", err)
+	}
+}
@@ -27,7 +27,13 @@ func (s Service) SyncSkill(ctx context.Context, command domain.SyncSkillCommand)
 	if err != nil {
 		return "", err
 	}
-	if err := s.installer.InstallSkillAcrossClients(ctx, skillID, cmd.SkillDir); err != nil {
+	if err := s.installer.InstallSkillAcrossClients(ctx, domain.InstallRequest{
+		SkillID:  skillID,
+		SkillDir: cmd.SkillDir,
+		Global:   cmd.Global,
+		Force:    cmd.Force,
+		Agents:   cmd.Agents,
+	}); err != nil {
 		return "", err
 	}
 	return skillID, nil
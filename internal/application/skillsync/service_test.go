@@ -20,13 +20,19 @@ func (f fakeSkillResolver) ResolveSkillID(string) (string, error) {
 
 type fakeInstaller struct {
 	skillID string
+	global  bool
+	force   bool
+	agents  []string
 	called  bool
 	err     error
 }
 
-func (f *fakeInstaller) InstallSkillAcrossClients(_ context.Context, skillID string, _ string) error {
+func (f *fakeInstaller) InstallSkillAcrossClients(_ context.Context, request domain.InstallRequest) error {
 	f.called = true
-	f.skillID = skillID
+	f.skillID = request.SkillID
+	f.global = request.Global
+	f.force = request.Force
+	f.agents = request.Agents
 	return f.err
 }
 
@@ -106,3 +112,37 @@ func TestSyncInstallsToAllClients(t *testing.T) {
 		t.Fatalf("installer called with wrong id: %q", installer.skillID)
 	}
 }
+
+func TestSyncPassesGlobalScope(t *testing.T) {
+	installer := &fakeInstaller{}
+	svc := NewService(fakeSkillResolver{id: "personal-skill"}, installer)
+	if _, err := svc.SyncSkill(context.Background(), domain.SyncSkillCommand{SkillDir: "/tmp/skill", Global: true}); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if !installer.global {
+		t.Fatal("expected global scope to reach the installer")
+	}
+}
+
+func TestSyncPassesForce(t *testing.T) {
+	installer := &fakeInstaller{}
+	svc := NewService(fakeSkillResolver{id: "review"}, installer)
+	if _, err := svc.SyncSkill(context.Background(), domain.SyncSkillCommand{SkillDir: "/tmp/skill", Force: true}); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if !installer.force {
+		t.Fatal("expected force to reach the installer")
+	}
+}
+
+func TestSyncPassesAgents(t *testing.T) {
+	installer := &fakeInstaller{}
+	svc := NewService(fakeSkillResolver{id: "review"}, installer)
+	cmd := domain.SyncSkillCommand{SkillDir: "/tmp/skill", Agents: []string{" cursor ", ""}}
+	if _, err := svc.SyncSkill(context.Background(), cmd); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(installer.agents) != 1 || installer.agents[0] != "cursor" {
+		t.Fatalf("expected normalized agents to reach the installer, got %q", installer.agents)
+	}
+}
@@ -2,16 +2,23 @@ package skilltest
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	domain "github.com/felixgeelhaar/aios/internal/domain/skilltest"
 )
 
 type Service struct {
-	runner domain.FixtureRunner
+	runner    domain.FixtureRunner
+	snapshots domain.SnapshotRunner
+	finder    domain.SkillFinder
+	now       func() time.Time
 }
 
-func NewService(runner domain.FixtureRunner) Service {
-	return Service{runner: runner}
+// NewService creates a Service. snapshots and finder may be nil, in which
+// case update and check mode, or recursive runs, are unavailable.
+func NewService(runner domain.FixtureRunner, snapshots domain.SnapshotRunner, finder domain.SkillFinder) Service {
+	return Service{runner: runner, snapshots: snapshots, finder: finder, now: time.Now}
 }
 
 func (s Service) TestSkill(ctx context.Context, command domain.TestSkillCommand) (domain.TestSkillResult, error) {
@@ -19,9 +26,55 @@ func (s Service) TestSkill(ctx context.Context, command domain.TestSkillCommand)
 	if err := cmd.Validate(); err != nil {
 		return domain.TestSkillResult{}, err
 	}
-	results, err := s.runner.Run(ctx, cmd.SkillDir)
+	if cmd.Update || cmd.Check {
+		if s.snapshots == nil {
+			return domain.TestSkillResult{}, fmt.Errorf("snapshot mode is not available")
+		}
+		snapshots, err := s.snapshots.Snapshot(ctx, cmd.SkillDir, cmd.Update)
+		if err != nil {
+			return domain.TestSkillResult{}, err
+		}
+		return domain.NewSnapshotResult(snapshots), nil
+	}
+	opts := domain.RunOptions{Parallel: cmd.Parallel, Timeout: cmd.Timeout}
+	if cmd.Recursive {
+		return s.testTree(ctx, cmd.SkillDir, opts)
+	}
+	start := s.now()
+	results, err := s.runner.Run(ctx, cmd.SkillDir, opts)
+	if err != nil {
+		return domain.TestSkillResult{}, err
+	}
+	out := domain.NewTestSkillResult(results)
+	out.DurationMS = s.now().Sub(start).Milliseconds()
+	return out, nil
+}
+
+// testTree tests every skill under root in path order. A skill whose
+// fixtures cannot run is reported as failed without stopping the others.
+func (s Service) testTree(ctx context.Context, root string, opts domain.RunOptions) (domain.TestSkillResult, error) {
+	if s.finder == nil {
+		return domain.TestSkillResult{}, fmt.Errorf("recursive testing is not available")
+	}
+	dirs, err := s.finder.FindSkills(ctx, root)
 	if err != nil {
 		return domain.TestSkillResult{}, err
 	}
-	return domain.NewTestSkillResult(results), nil
+	if len(dirs) == 0 {
+		return domain.TestSkillResult{}, fmt.Errorf("%w under %s", domain.ErrNoSkillsFound, root)
+	}
+	skills := make([]domain.SkillTestResult, 0, len(dirs))
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return domain.TestSkillResult{}, err
+		}
+		start := s.now()
+		results, err := s.runner.Run(ctx, dir, opts)
+		r := domain.SkillTestResult{SkillDir: dir, Results: results, DurationMS: s.now().Sub(start).Milliseconds()}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		skills = append(skills, r)
+	}
+	return domain.NewRecursiveTestResult(skills), nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	domain "github.com/felixgeelhaar/aios/internal/domain/skilltest"
 )
@@ -13,17 +14,27 @@ type fakeFixtureRunner struct {
 	err     error
 }
 
-func (f fakeFixtureRunner) Run(context.Context, string) ([]domain.FixtureResult, error) {
+func (f fakeFixtureRunner) Run(context.Context, string, domain.RunOptions) ([]domain.FixtureResult, error) {
 	return f.results, f.err
 }
 
+type fakeSnapshotRunner struct {
+	results []domain.SnapshotResult
+	write   *bool
+}
+
+func (f fakeSnapshotRunner) Snapshot(_ context.Context, _ string, write bool) ([]domain.SnapshotResult, error) {
+	*f.write = write
+	return f.results, nil
+}
+
 func TestServiceTestSkill(t *testing.T) {
 	svc := NewService(fakeFixtureRunner{
 		results: []domain.FixtureResult{
 			{Name: "fixture_01.json", Passed: true},
 			{Name: "fixture_02.json", Passed: false},
 		},
-	})
+	}, nil, nil)
 	res, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "/tmp/skill"})
 	if err != nil {
 		t.Fatalf("test-skill failed: %v", err)
@@ -34,9 +45,104 @@ func TestServiceTestSkill(t *testing.T) {
 }
 
 func TestServiceTestSkillRequiresSkillDir(t *testing.T) {
-	svc := NewService(fakeFixtureRunner{})
+	svc := NewService(fakeFixtureRunner{}, nil, nil)
 	_, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{})
 	if !errors.Is(err, domain.ErrSkillDirRequired) {
 		t.Fatalf("expected skill-dir required error, got %v", err)
 	}
 }
+
+func TestServiceTestSkillSnapshotModes(t *testing.T) {
+	var write bool
+	svc := NewService(fakeFixtureRunner{}, fakeSnapshotRunner{
+		write: &write,
+		results: []domain.SnapshotResult{
+			{Name: "fixture_01.json", Status: domain.SnapshotUnchanged},
+			{Name: "fixture_02.json", Status: domain.SnapshotStale},
+			{Name: "fixture_03.json", Status: domain.SnapshotFailed, Error: "boom"},
+			{Name: "fixture_04.json", Status: domain.SnapshotSkipped},
+		},
+	}, nil)
+
+	res, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "/tmp/skill", Check: true})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if write || res.Failed != 2 || len(res.Snapshots) != 4 || res.Results != nil {
+		t.Fatalf("unexpected check result: write=%v %#v", write, res)
+	}
+
+	if _, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "/tmp/skill", Update: true}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if !write {
+		t.Fatal("expected update to write snapshots")
+	}
+
+	_, err = svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "/tmp/skill", Update: true, Check: true})
+	if !errors.Is(err, domain.ErrUpdateAndCheck) {
+		t.Fatalf("expected update/check conflict, got %v", err)
+	}
+}
+
+func TestServiceTestSkillSnapshotModeUnavailable(t *testing.T) {
+	svc := NewService(fakeFixtureRunner{}, nil, nil)
+	if _, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "/tmp/skill", Update: true}); err == nil {
+		t.Fatal("expected error without a snapshot runner")
+	}
+}
+
+type fakeTreeRunner struct {
+	results map[string][]domain.FixtureResult
+	opts    *domain.RunOptions
+}
+
+func (f fakeTreeRunner) Run(_ context.Context, dir string, opts domain.RunOptions) ([]domain.FixtureResult, error) {
+	*f.opts = opts
+	results, ok := f.results[dir]
+	if !ok {
+		return nil, errors.New("invalid skill.yaml")
+	}
+	return results, nil
+}
+
+type fakeSkillFinder []string
+
+func (f fakeSkillFinder) FindSkills(context.Context, string) ([]string, error) {
+	return f, nil
+}
+
+func TestServiceTestSkillRecursive(t *testing.T) {
+	var opts domain.RunOptions
+	runner := fakeTreeRunner{opts: &opts, results: map[string][]domain.FixtureResult{
+		"skills/a": {{Name: "fixture_01.json", Passed: true}, {Name: "fixture_02.json", Passed: false}},
+		"skills/b": {{Name: "fixture_01.json", Passed: true}},
+	}}
+	svc := NewService(runner, nil, fakeSkillFinder{"skills/a", "skills/b", "skills/broken"})
+
+	res, err := svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "skills", Recursive: true, Parallel: 3, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("recursive test failed: %v", err)
+	}
+	if opts.Parallel != 3 || opts.Timeout != time.Second {
+		t.Fatalf("options not passed through: %#v", opts)
+	}
+	if len(res.Skills) != 3 || res.Results != nil {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if res.Skills[0].Failed != 1 || res.Skills[1].Failed != 0 || res.Skills[2].Failed != 1 || res.Skills[2].Error != "invalid skill.yaml" {
+		t.Fatalf("unexpected per-skill results: %#v", res.Skills)
+	}
+	if res.Failed != 2 {
+		t.Fatalf("expected 2 failures, got %d", res.Failed)
+	}
+
+	_, err = NewService(runner, nil, fakeSkillFinder{}).TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "skills", Recursive: true})
+	if !errors.Is(err, domain.ErrNoSkillsFound) {
+		t.Fatalf("expected no skills error, got %v", err)
+	}
+	_, err = svc.TestSkill(context.Background(), domain.TestSkillCommand{SkillDir: "skills", Recursive: true, Check: true})
+	if !errors.Is(err, domain.ErrRecursiveSnapshots) {
+		t.Fatalf("expected recursive/snapshot conflict, got %v", err)
+	}
+}
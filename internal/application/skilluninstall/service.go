@@ -27,7 +27,12 @@ func (s Service) UninstallSkill(ctx context.Context, command domain.UninstallSki
 	if err != nil {
 		return "", err
 	}
-	if err := s.uninstaller.UninstallAcrossClients(ctx, skillID); err != nil {
+	if err := s.uninstaller.UninstallAcrossClients(ctx, domain.UninstallRequest{
+		SkillID: skillID,
+		Global:  cmd.Global,
+		Force:   cmd.Force,
+		Agents:  cmd.Agents,
+	}); err != nil {
 		return "", err
 	}
 	return skillID, nil
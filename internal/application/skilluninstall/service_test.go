@@ -19,11 +19,17 @@ func (f fakeSkillIDResolver) ResolveSkillID(string) (string, error) {
 
 type fakeClientUninstaller struct {
 	skillID string
+	global  bool
+	force   bool
+	agents  []string
 	err     error
 }
 
-func (f *fakeClientUninstaller) UninstallAcrossClients(_ context.Context, skillID string) error {
-	f.skillID = skillID
+func (f *fakeClientUninstaller) UninstallAcrossClients(_ context.Context, request domain.UninstallRequest) error {
+	f.skillID = request.SkillID
+	f.global = request.Global
+	f.force = request.Force
+	f.agents = request.Agents
 	return f.err
 }
 
@@ -50,3 +56,37 @@ func TestServiceUninstallSkillRequiresSkillDir(t *testing.T) {
 		t.Fatalf("expected skill-dir required error, got %v", err)
 	}
 }
+
+func TestServiceUninstallSkillPassesGlobalScope(t *testing.T) {
+	uninstaller := &fakeClientUninstaller{}
+	svc := NewService(fakeSkillIDResolver{skillID: "roadmap-reader"}, uninstaller)
+	if _, err := svc.UninstallSkill(context.Background(), domain.UninstallSkillCommand{SkillDir: "/tmp/skill", Global: true}); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if !uninstaller.global {
+		t.Fatal("expected global scope to reach the uninstaller")
+	}
+}
+
+func TestServiceUninstallSkillPassesForce(t *testing.T) {
+	uninstaller := &fakeClientUninstaller{}
+	svc := NewService(fakeSkillIDResolver{skillID: "review"}, uninstaller)
+	if _, err := svc.UninstallSkill(context.Background(), domain.UninstallSkillCommand{SkillDir: "/tmp/skill", Force: true}); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if !uninstaller.force {
+		t.Fatal("expected force to reach the uninstaller")
+	}
+}
+
+func TestServiceUninstallSkillPassesAgents(t *testing.T) {
+	uninstaller := &fakeClientUninstaller{}
+	svc := NewService(fakeSkillIDResolver{skillID: "review"}, uninstaller)
+	cmd := domain.UninstallSkillCommand{SkillDir: "/tmp/skill", Agents: []string{"cursor", " "}}
+	if _, err := svc.UninstallSkill(context.Background(), cmd); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if len(uninstaller.agents) != 1 || uninstaller.agents[0] != "cursor" {
+		t.Fatalf("expected agents to reach the uninstaller, got %q", uninstaller.agents)
+	}
+}
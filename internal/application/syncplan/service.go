@@ -27,12 +27,20 @@ func (s Service) BuildSyncPlan(ctx context.Context, command domain.BuildSyncPlan
 	if err != nil {
 		return domain.BuildSyncPlanResult{}, err
 	}
-	writes, err := s.planner.PlanWriteTargets(ctx, skillID)
+	writes, err := s.planner.PlanWriteTargets(ctx, domain.PlanRequest{
+		SkillID:  skillID,
+		SkillDir: cmd.SkillDir,
+		Global:   cmd.Global,
+		Agents:   cmd.Agents,
+	})
 	if err != nil {
 		return domain.BuildSyncPlanResult{}, err
 	}
 	return domain.BuildSyncPlanResult{
-		SkillID: skillID,
-		Writes:  writes,
+		SkillID:  skillID,
+		Agents:   writes.Agents,
+		Writes:   writes.Targets,
+		Files:    writes.Files,
+		Sections: writes.Sections,
 	}, nil
 }
@@ -20,11 +20,12 @@ func (f fakeSkillResolver) ResolveSkillID(string) (string, error) {
 
 type fakeTargetPlanner struct {
 	writes []string
+	files  []string
 	err    error
 }
 
-func (f fakeTargetPlanner) PlanWriteTargets(context.Context, string) ([]string, error) {
-	return f.writes, f.err
+func (f fakeTargetPlanner) PlanWriteTargets(context.Context, domain.PlanRequest) (domain.PlannedWrites, error) {
+	return domain.PlannedWrites{Targets: f.writes, Files: f.files}, f.err
 }
 
 func TestBuildSyncPlan(t *testing.T) {
@@ -89,3 +90,34 @@ func TestSyncPlanValidatesBeforePlanning(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestBuildSyncPlanIncludesFiles(t *testing.T) {
+	svc := NewService(
+		fakeSkillResolver{id: "my-skill"},
+		fakeTargetPlanner{writes: []string{"a"}, files: []string{"a/SKILL.md", "a/scripts/run.sh"}},
+	)
+	res, err := svc.BuildSyncPlan(context.Background(), domain.BuildSyncPlanCommand{SkillDir: "/tmp/skill"})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(res.Files) != 2 || res.Files[1] != "a/scripts/run.sh" {
+		t.Fatalf("unexpected files: %#v", res.Files)
+	}
+}
+
+type agentsPlanner struct{}
+
+func (agentsPlanner) PlanWriteTargets(_ context.Context, request domain.PlanRequest) (domain.PlannedWrites, error) {
+	return domain.PlannedWrites{Agents: request.Agents, Targets: []string{"canonical", "link"}}, nil
+}
+
+func TestBuildSyncPlanPassesAgents(t *testing.T) {
+	svc := NewService(fakeSkillResolver{id: "review"}, agentsPlanner{})
+	res, err := svc.BuildSyncPlan(context.Background(), domain.BuildSyncPlanCommand{SkillDir: "/tmp/skill", Agents: []string{" cursor "}})
+	if err != nil {
+		t.Fatalf("build sync plan failed: %v", err)
+	}
+	if len(res.Agents) != 1 || res.Agents[0] != "cursor" {
+		t.Fatalf("expected planned agents in result, got %#v", res.Agents)
+	}
+}
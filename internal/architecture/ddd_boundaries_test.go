@@ -117,9 +117,15 @@ func TestObservabilityNoIO(t *testing.T) {
 	checkNoExactImports(t, filepath.Join(repoRoot, "internal", "observability"), forbiddenStdlib)
 }
 
+// sharedKernel lists the domain packages every bounded context may import:
+// agentregistry holds the agent model that installs, plans and uninstalls
+// all target.
+var sharedKernel = map[string]bool{"agentregistry": true}
+
 // TestDomainBoundedContextIsolation ensures each bounded context in the domain
-// layer owns its own model and does not import from other bounded contexts.
-// Cross-BC coordination must happen at the application layer.
+// layer owns its own model and does not import from other bounded contexts,
+// apart from the sharedKernel. Cross-BC coordination must happen at the
+// application layer.
 func TestDomainBoundedContextIsolation(t *testing.T) {
 	repoRoot := findRepoRoot(t)
 	domainDir := filepath.Join(repoRoot, "internal", "domain")
@@ -141,7 +147,7 @@ func TestDomainBoundedContextIsolation(t *testing.T) {
 		// Build forbidden list: all other bounded contexts.
 		var forbidden []string
 		for _, other := range contexts {
-			if other != bc {
+			if other != bc && !sharedKernel[other] {
 				forbidden = append(forbidden, modulePrefix+"internal/domain/"+other)
 			}
 		}
@@ -1,48 +1,283 @@
 package builder
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// Spec describes the skill BuildSkill writes.
 type Spec struct {
 	ID      string
 	Version string
 	Dir     string
+
+	// Type picks the template that fills in Description, Inputs and
+	// Outputs when they are empty, and drafts prompt.md. With no type and
+	// no fields, BuildSkill writes the minimal scaffold of aios skills init.
+	Type        SkillType
+	Description string
+	Inputs      []Field
+	Outputs     []Field
 }
 
+// genericInputs and genericOutputs are the placeholder fields of a skill
+// without a type.
+var (
+	genericInputs  = []Field{{Name: "query", Type: FieldString, Description: "Input query", Example: "example"}}
+	genericOutputs = []Field{{Name: "result", Type: FieldString, Description: "Output result", Required: true}}
+)
+
+// BuildSkill writes a skill directory named after s.ID under s.Dir:
+// skill.yaml, prompt.md, the input and output schemas, and a starter
+// fixture with its expected output.
 func BuildSkill(s Spec) error {
 	if s.ID == "" || s.Version == "" || s.Dir == "" {
 		return fmt.Errorf("id, version, and dir are required")
 	}
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
 	root := filepath.Join(s.Dir, s.ID)
 	if err := os.MkdirAll(filepath.Join(root, "tests"), 0o750); err != nil {
 		return err
 	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(root, rel), []byte(content), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	skillYAML := "id: " + s.ID + "\nversion: " + s.Version + "\n" +
-		"inputs:\n  schema: schema.input.json\n" +
-		"outputs:\n  schema: schema.output.json\n"
-
-	inputSchema := `{"type":"object","properties":{"query":{"type":"string","description":"Input query"}}}` + "\n"
-	outputSchema := `{"type":"object","properties":{"result":{"type":"string","description":"Output result"}}}` + "\n"
+// Resolve returns s with the defaults of its type filled in.
+func (s Spec) Resolve() (Spec, error) {
+	if s.Type == "" {
+		if len(s.Inputs) == 0 {
+			s.Inputs = genericInputs
+		}
+		if len(s.Outputs) == 0 {
+			s.Outputs = genericOutputs
+		}
+		return s, nil
+	}
+	tmpl, err := TemplateFor(s.Type)
+	if err != nil {
+		return Spec{}, err
+	}
+	if s.Description == "" {
+		s.Description = tmpl.Description
+	}
+	if len(s.Inputs) == 0 {
+		s.Inputs = tmpl.Inputs
+	}
+	if len(s.Outputs) == 0 {
+		s.Outputs = tmpl.Outputs
+	}
+	return s, nil
+}
 
-	fixture := `{"query":"example"}` + "\n"
-	expected := `{"status":"ok"}` + "\n"
+func (s Spec) files() (map[string]string, error) {
+	minimal := s.Type == "" && len(s.Inputs) == 0 && len(s.Outputs) == 0
+	s, err := s.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	for _, fields := range [][]Field{s.Inputs, s.Outputs} {
+		seen := map[string]bool{}
+		for _, f := range fields {
+			if err := f.validate(); err != nil {
+				return nil, err
+			}
+			if seen[f.Name] {
+				return nil, fmt.Errorf("field %s is declared twice", f.Name)
+			}
+			seen[f.Name] = true
+		}
+	}
 
-	files := map[string]string{
-		"skill.yaml":             skillYAML,
-		"prompt.md":              "# Prompt\n",
+	inputSchema, err := schemaJSON(s.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	outputSchema, err := schemaJSON(s.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	fixture, err := fixtureJSON(s.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := expectedJSON(s.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	prompt := "# Prompt\n"
+	if !minimal {
+		prompt = draftPrompt(s)
+	}
+	return map[string]string{
+		"skill.yaml":             skillYAML(s),
+		"prompt.md":              prompt,
 		"schema.input.json":      inputSchema,
 		"schema.output.json":     outputSchema,
 		"tests/fixture_01.json":  fixture,
 		"tests/expected_01.json": expected,
+	}, nil
+}
+
+func skillYAML(s Spec) string {
+	var b strings.Builder
+	b.WriteString("id: " + s.ID + "\nversion: " + s.Version + "\n")
+	if s.Description != "" {
+		desc, _ := json.Marshal(s.Description)
+		b.WriteString("description: " + string(desc) + "\n")
 	}
-	for rel, content := range files {
-		if err := os.WriteFile(filepath.Join(root, rel), []byte(content), 0o600); err != nil {
-			return err
+	if s.Type != "" {
+		b.WriteString("tags: [" + string(s.Type) + "]\n")
+	}
+	b.WriteString("inputs:\n  schema: schema.input.json\n")
+	b.WriteString("outputs:\n  schema: schema.output.json\n")
+	return b.String()
+}
+
+// schemaJSON renders fields as a JSON Schema object.
+func schemaJSON(fields []Field) (string, error) {
+	type property struct {
+		Type        string            `json:"type"`
+		Description string            `json:"description,omitempty"`
+		Items       map[string]string `json:"items,omitempty"`
+	}
+	props := make(map[string]property, len(fields))
+	var required []string
+	for _, f := range fields {
+		p := property{Type: f.fieldType(), Description: f.Description}
+		if p.Type == FieldArray {
+			p.Items = map[string]string{"type": FieldString}
+		}
+		props[f.Name] = p
+		if f.Required {
+			required = append(required, f.Name)
 		}
 	}
-	return nil
+	schema := struct {
+		Type       string              `json:"type"`
+		Properties map[string]property `json:"properties"`
+		Required   []string            `json:"required,omitempty"`
+	}{"object", props, required}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// fixtureJSON renders an input with an example value for every field.
+func fixtureJSON(fields []Field) (string, error) {
+	input := make(map[string]any, len(fields))
+	for _, f := range fields {
+		input[f.Name] = exampleValue(f)
+	}
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// expectedJSON renders an expected output with an example value for every
+// required output field, for the author to replace with the real output.
+func expectedJSON(fields []Field) (string, error) {
+	expected := map[string]any{}
+	for _, f := range fields {
+		if f.Required {
+			expected[f.Name] = exampleValue(f)
+		}
+	}
+	data, err := json.MarshalIndent(expected, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+func exampleValue(f Field) any {
+	if f.Example != nil {
+		return f.Example
+	}
+	switch f.fieldType() {
+	case FieldNumber, FieldInteger:
+		return 1
+	case FieldBoolean:
+		return true
+	case FieldArray:
+		return []any{"example " + f.Name}
+	}
+	return "example " + f.Name
+}
+
+// draftPrompt writes a first prompt.md for s: the template's role and
+// instructions, a section per input, and the output fields to return.
+func draftPrompt(s Spec) string {
+	var b strings.Builder
+	b.WriteString("# " + title(s.ID) + "\n\n")
+	tmpl, _ := TemplateFor(s.Type)
+	switch {
+	case tmpl.Role != "":
+		b.WriteString(tmpl.Role + "\n\n")
+	case s.Description != "":
+		b.WriteString(s.Description + "\n\n")
+	}
+
+	b.WriteString("## Input\n\n")
+	for _, f := range s.Inputs {
+		if !f.Required {
+			b.WriteString("{{#if input." + f.Name + "}}\n")
+		}
+		b.WriteString("### " + title(f.Name) + "\n\n")
+		if f.Description != "" {
+			b.WriteString(f.Description + "\n\n")
+		}
+		b.WriteString("{{ input." + f.Name + " }}\n")
+		if !f.Required {
+			b.WriteString("{{/if}}\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Instructions\n\n")
+	instructions := tmpl.Instructions
+	if len(instructions) == 0 {
+		instructions = []string{"Describe, step by step, how to turn the input into the output."}
+	}
+	for i, line := range instructions {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, line)
+	}
+
+	b.WriteString("\n## Output\n\nReply with a JSON object matching schema.output.json:\n\n")
+	for _, f := range s.Outputs {
+		kind := f.fieldType()
+		if kind == FieldArray {
+			kind = "list of strings"
+		}
+		line := fmt.Sprintf("- `%s` (%s)", f.Name, kind)
+		if f.Description != "" {
+			line += ": " + f.Description
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// title turns an identifier such as "roadmap-reader" or "key_points" into
+// "Roadmap Reader" or "Key Points".
+func title(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '-' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
 }
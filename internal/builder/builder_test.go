@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/felixgeelhaar/aios/internal/skill"
@@ -171,3 +173,192 @@ func TestBuildSkillGeneratesValidSkillYAML(t *testing.T) {
 		t.Fatalf("skill spec validation failed: %v", err)
 	}
 }
+
+func TestBuildSkillTypesPassLintAndTests(t *testing.T) {
+	for _, tmpl := range Templates() {
+		t.Run(string(tmpl.Type), func(t *testing.T) {
+			dir := t.TempDir()
+			id := string(tmpl.Type) + "-skill"
+			if err := BuildSkill(Spec{ID: id, Version: "0.1.0", Dir: dir, Type: tmpl.Type}); err != nil {
+				t.Fatalf("build failed: %v", err)
+			}
+			root := filepath.Join(dir, id)
+			res, err := skill.LintSkillDir(root)
+			if err != nil {
+				t.Fatalf("lint error: %v", err)
+			}
+			if !res.Valid || len(res.Findings) != 0 {
+				t.Fatalf("lint findings: %+v", res.Findings)
+			}
+			// The example outputs must satisfy the output schema, and the
+			// stub alone must not pass them.
+			results, err := skill.RunFixtureSuite(root)
+			if err != nil {
+				t.Fatalf("fixtures: %v", err)
+			}
+			if len(results) != 1 || results[0].Passed {
+				t.Fatalf("the stub should not pass the example outputs: %+v", results)
+			}
+			data, err := os.ReadFile(filepath.Join(root, "tests", "expected_01.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var example map[string]any
+			if err := json.Unmarshal(data, &example); err != nil {
+				t.Fatal(err)
+			}
+			exec := skill.NewExecutor()
+			exec.RegisterHandler(id, func(skill.Artifact, map[string]any) (map[string]any, error) {
+				return example, nil
+			})
+			results, err = skill.RunFixtureSuiteWithExecutor(root, exec)
+			if err != nil {
+				t.Fatalf("fixtures: %v", err)
+			}
+			if len(results) != 1 || !results[0].Passed {
+				t.Fatalf("fixture results: %+v", results)
+			}
+
+			spec, err := skill.LoadSkillSpec(filepath.Join(root, "skill.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spec.Description != tmpl.Description || len(spec.Tags) != 1 || spec.Tags[0] != string(tmpl.Type) {
+				t.Fatalf("unexpected spec %+v", spec)
+			}
+
+			prompt, err := skill.LoadProgressivePrompt(filepath.Join(root, "prompt.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs, err := prompt.InputReferences()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(refs) != len(tmpl.Inputs) {
+				t.Fatalf("prompt references %v, want one per input", refs)
+			}
+		})
+	}
+}
+
+func TestBuildSkillCustomFields(t *testing.T) {
+	dir := t.TempDir()
+	inputs := []Field{
+		{Name: "ticket", Description: "The support ticket.", Required: true},
+		{Name: "priority", Type: FieldInteger},
+		{Name: "tags", Type: FieldArray},
+	}
+	outputs := []Field{{Name: "reply", Required: true}, {Name: "escalate", Type: FieldBoolean, Required: true}}
+	spec := Spec{ID: "ticket-triage", Version: "0.1.0", Dir: dir, Type: TypeReviewer, Description: "Triages tickets.", Inputs: inputs, Outputs: outputs}
+	if err := BuildSkill(spec); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	root := filepath.Join(dir, "ticket-triage")
+
+	var schema struct {
+		Properties map[string]struct {
+			Type  string            `json:"type"`
+			Items map[string]string `json:"items"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	data, err := os.ReadFile(filepath.Join(root, "schema.input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties["priority"].Type != "integer" || schema.Properties["tags"].Items["type"] != "string" || len(schema.Required) != 1 || schema.Required[0] != "ticket" {
+		t.Fatalf("unexpected input schema %s", data)
+	}
+
+	var fixture map[string]any
+	data, err = os.ReadFile(filepath.Join(root, "tests", "fixture_01.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+	if fixture["ticket"] != "example ticket" || fixture["priority"] != float64(1) {
+		t.Fatalf("unexpected fixture %s", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(root, "tests", "expected_01.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected map[string]any
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"reply": "example reply", "escalate": true}
+	if !reflect.DeepEqual(expected, want) {
+		t.Fatalf("unexpected expected file %s", data)
+	}
+
+	prompt, err := os.ReadFile(filepath.Join(root, "prompt.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Ticket Triage", "{{#if input.priority}}", "{{ input.ticket }}", "- `escalate` (boolean)"} {
+		if !strings.Contains(string(prompt), want) {
+			t.Fatalf("prompt.md missing %q:\n%s", want, prompt)
+		}
+	}
+	res, err := skill.LintSkillDir(root)
+	if err != nil || !res.Valid {
+		t.Fatalf("lint: %+v %v", res, err)
+	}
+}
+
+func TestBuildSkillRejectsBadFields(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+	}{
+		{"unknown type", Spec{Type: "poet"}},
+		{"bad field name", Spec{Inputs: []Field{{Name: "first name"}}}},
+		{"bad field type", Spec{Inputs: []Field{{Name: "n", Type: "date"}}}},
+		{"duplicate field", Spec{Outputs: []Field{{Name: "a"}, {Name: "a"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.ID, tt.spec.Version, tt.spec.Dir = "x", "0.1.0", t.TempDir()
+			if err := BuildSkill(tt.spec); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Field
+	}{
+		{"document", Field{Name: "document", Required: true}},
+		{"count:integer", Field{Name: "count", Type: "integer", Required: true}},
+		{"audience?:string:Who it is for: leads", Field{Name: "audience", Type: "string", Description: "Who it is for: leads"}},
+		{" tags? : ARRAY ", Field{Name: "tags", Type: "array"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseField(tt.in)
+		if err != nil {
+			t.Fatalf("ParseField(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseField(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if again, err := ParseField(got.String()); err != nil || again.Name != got.Name || again.Required != got.Required || again.fieldType() != got.fieldType() {
+			t.Fatalf("round trip of %q: %+v %v", got.String(), again, err)
+		}
+	}
+	for _, bad := range []string{"", "1st", "a:date"} {
+		if _, err := ParseField(bad); err == nil {
+			t.Fatalf("ParseField(%q) should fail", bad)
+		}
+	}
+}
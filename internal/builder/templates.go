@@ -0,0 +1,206 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SkillType selects the template a new skill starts from.
+type SkillType string
+
+const (
+	TypeSummarizer SkillType = "summarizer"
+	TypeReviewer   SkillType = "reviewer"
+	TypeExtractor  SkillType = "extractor"
+	TypePlanner    SkillType = "planner"
+)
+
+// Field types a Field can have. FieldArray is a list of strings.
+const (
+	FieldString  = "string"
+	FieldNumber  = "number"
+	FieldInteger = "integer"
+	FieldBoolean = "boolean"
+	FieldArray   = "array"
+)
+
+var fieldNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Field is one property of a skill's input or output.
+type Field struct {
+	Name string
+	// Type is one of FieldString, FieldNumber, FieldInteger, FieldBoolean
+	// or FieldArray; empty means FieldString.
+	Type        string
+	Description string
+	Required    bool
+	// Example is the value the starter fixture uses; nil derives one from
+	// the type.
+	Example any
+}
+
+// ParseField parses a field written as "name:type:description". The type
+// and description may be left out, and a "?" after the name makes the
+// field optional: "audience?:string:Who the summary is for".
+func ParseField(s string) (Field, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), ":", 3)
+	f := Field{Name: strings.TrimSpace(parts[0]), Required: true}
+	if strings.HasSuffix(f.Name, "?") {
+		f.Name = strings.TrimSuffix(f.Name, "?")
+		f.Required = false
+	}
+	if len(parts) > 1 {
+		f.Type = strings.ToLower(strings.TrimSpace(parts[1]))
+	}
+	if len(parts) > 2 {
+		f.Description = strings.TrimSpace(parts[2])
+	}
+	if err := f.validate(); err != nil {
+		return Field{}, err
+	}
+	return f, nil
+}
+
+// String renders the field in the form ParseField reads.
+func (f Field) String() string {
+	name := f.Name
+	if !f.Required {
+		name += "?"
+	}
+	s := name + ":" + f.fieldType()
+	if f.Description != "" {
+		s += ":" + f.Description
+	}
+	return s
+}
+
+func (f Field) fieldType() string {
+	if f.Type == "" {
+		return FieldString
+	}
+	return f.Type
+}
+
+func (f Field) validate() error {
+	if !fieldNameRe.MatchString(f.Name) {
+		return fmt.Errorf("field name %q must start with a letter or underscore and contain only letters, digits and underscores", f.Name)
+	}
+	switch f.fieldType() {
+	case FieldString, FieldNumber, FieldInteger, FieldBoolean, FieldArray:
+		return nil
+	}
+	return fmt.Errorf("field %s has unknown type %q (want string, number, integer, boolean or array)", f.Name, f.Type)
+}
+
+// Template is the starting point for one skill type: a description, the
+// inputs and outputs the type usually has, and the instructions its prompt
+// opens with.
+type Template struct {
+	Type SkillType
+	// Summary is a one-line explanation for choosing a type.
+	Summary     string
+	Description string
+	// Role is the first sentence of the drafted prompt.
+	Role         string
+	Instructions []string
+	Inputs       []Field
+	Outputs      []Field
+}
+
+// Templates returns the template of every skill type.
+func Templates() []Template {
+	return []Template{
+		{
+			Type:        TypeSummarizer,
+			Summary:     "condense a document into a short summary and key points",
+			Description: "Summarizes a document for a given audience.",
+			Role:        "You summarize documents so readers can act on them without reading the original.",
+			Instructions: []string{
+				"Read the whole document before writing anything.",
+				"Keep what matters to the audience; leave out background they already know.",
+				"Write a summary of at most five sentences, then list the key points.",
+				"Do not add facts that are not in the document.",
+			},
+			Inputs: []Field{
+				{Name: "document", Type: FieldString, Description: "The text to summarize.", Required: true, Example: "Q3 roadmap: ship the billing revamp in July, then migrate search to the new index."},
+				{Name: "audience", Type: FieldString, Description: "Who the summary is for.", Example: "engineering leads"},
+			},
+			Outputs: []Field{
+				{Name: "summary", Type: FieldString, Description: "A concise summary of the document.", Required: true},
+				{Name: "key_points", Type: FieldArray, Description: "The most important points, one per item.", Required: true},
+			},
+		},
+		{
+			Type:        TypeReviewer,
+			Summary:     "check work against criteria and report findings",
+			Description: "Reviews work against a set of criteria.",
+			Role:        "You review work carefully and report problems the author can fix.",
+			Instructions: []string{
+				"Check the content against each criterion; use good judgement when none are given.",
+				"Report each problem once, most important first, with where it occurs and how to fix it.",
+				"Do not report matters of taste as problems.",
+				"Finish with a verdict: approve, approve with comments, or request changes.",
+			},
+			Inputs: []Field{
+				{Name: "content", Type: FieldString, Description: "The work to review.", Required: true, Example: "We will migrate all customers to the new billing system in one weekend."},
+				{Name: "criteria", Type: FieldArray, Description: "What to check the work against.", Example: []any{"risks are named", "rollback is planned"}},
+			},
+			Outputs: []Field{
+				{Name: "verdict", Type: FieldString, Description: "approve, approve with comments, or request changes.", Required: true},
+				{Name: "findings", Type: FieldArray, Description: "Problems found, most important first.", Required: true},
+			},
+		},
+		{
+			Type:        TypeExtractor,
+			Summary:     "pull structured facts out of unstructured text",
+			Description: "Extracts structured facts from text.",
+			Role:        "You extract facts from text exactly as the text states them.",
+			Instructions: []string{
+				"Look for each requested fact in the text.",
+				"Copy values as they appear; do not guess or infer missing ones.",
+				"List every requested fact the text does not contain under missing.",
+			},
+			Inputs: []Field{
+				{Name: "text", Type: FieldString, Description: "The text to extract from.", Required: true, Example: "Invoice 1042 from Acme Corp, due 30 June, total 1200 EUR."},
+				{Name: "facts", Type: FieldArray, Description: "The facts to extract.", Required: true, Example: []any{"invoice number", "due date", "total"}},
+			},
+			Outputs: []Field{
+				{Name: "values", Type: FieldArray, Description: "Each fact found, as \"fact: value\".", Required: true},
+				{Name: "missing", Type: FieldArray, Description: "The facts the text does not contain.", Required: true},
+			},
+		},
+		{
+			Type:        TypePlanner,
+			Summary:     "turn a goal into ordered steps with risks",
+			Description: "Plans the steps to reach a goal.",
+			Role:        "You turn goals into plans a team can start on today.",
+			Instructions: []string{
+				"Restate the goal in one sentence to check it is understood.",
+				"Break it into ordered steps small enough to finish in a few days each.",
+				"Respect every constraint; say so when the goal cannot be met within them.",
+				"Name the main risks and how to reduce them.",
+			},
+			Inputs: []Field{
+				{Name: "goal", Type: FieldString, Description: "What the plan should achieve.", Required: true, Example: "Launch the public API beta by the end of the quarter."},
+				{Name: "constraints", Type: FieldArray, Description: "Limits the plan must respect.", Example: []any{"two engineers", "no new infrastructure"}},
+			},
+			Outputs: []Field{
+				{Name: "steps", Type: FieldArray, Description: "Ordered steps to reach the goal.", Required: true},
+				{Name: "risks", Type: FieldArray, Description: "The main risks and how to reduce them.", Required: true},
+			},
+		},
+	}
+}
+
+// TemplateFor returns the template of a skill type.
+func TemplateFor(t SkillType) (Template, error) {
+	names := []string{}
+	for _, tmpl := range Templates() {
+		if tmpl.Type == t {
+			return tmpl, nil
+		}
+		names = append(names, string(tmpl.Type))
+	}
+	return Template{}, fmt.Errorf("unknown skill type %q (want %s)", t, strings.Join(names, ", "))
+}
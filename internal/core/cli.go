@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,6 +24,7 @@ import (
 	applicationsyncplan "github.com/felixgeelhaar/aios/internal/application/syncplan"
 	applicationworkspace "github.com/felixgeelhaar/aios/internal/application/workspaceorchestration"
 	"github.com/felixgeelhaar/aios/internal/builder"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 	domainonboarding "github.com/felixgeelhaar/aios/internal/domain/onboarding"
 	domainprojectinventory "github.com/felixgeelhaar/aios/internal/domain/projectinventory"
 	domainskilllint "github.com/felixgeelhaar/aios/internal/domain/skilllint"
@@ -39,6 +41,7 @@ import (
 	"github.com/felixgeelhaar/aios/internal/observability"
 	"github.com/felixgeelhaar/aios/internal/registry"
 	"github.com/felixgeelhaar/aios/internal/runtime"
+	"github.com/felixgeelhaar/aios/internal/secrets"
 	"github.com/felixgeelhaar/aios/internal/skill"
 	mcpg "github.com/felixgeelhaar/mcp-go"
 )
@@ -73,16 +76,91 @@ func (p *progressWriter) Stop(msg string) {
 	_, _ = fmt.Fprintln(p.out, msg)
 }
 
+// CommandFlags carries subcommand-specific flags from the command layer
+// into Run. The zero value selects the default behavior of every command.
+type CommandFlags struct {
+	// Global targets user-level agent skill directories instead of the
+	// project for skill sync, plan and uninstall.
+	Global bool
+	// Agents restricts skill sync, plan and uninstall to the named agents,
+	// overriding the skill's clients list.
+	Agents []string
+	// Force lets skill sync and uninstall replace entries that aios did
+	// not create, after backing them up.
+	Force bool
+	// AllProjects makes skill sync install into every tracked project
+	// instead of the current one.
+	AllProjects bool
+	// Projects makes skill sync install into these tracked projects,
+	// selected by id or path.
+	Projects []string
+	// Parallel bounds how many projects a multi-project sync installs
+	// into at once, or how many fixtures skill test runs at once. Zero
+	// uses the default.
+	Parallel int
+
+	// Frozen makes skill install fail instead of updating the lockfile when
+	// a source no longer matches its locked version or content.
+	Frozen bool
+
+	// Apply makes skill prune remove the entries it finds instead of
+	// only listing them.
+	Apply bool
+
+	// Update makes skill test write fixture outputs to their expected
+	// files.
+	Update bool
+	// Check makes skill test fail when expected files are stale.
+	Check bool
+	// Recursive makes skill test run every skill under the directory.
+	Recursive bool
+	// Timeout bounds each fixture skill test runs; zero means no limit.
+	Timeout time.Duration
+	// Reports selects the reports skill test writes, each "format" or
+	// "format=path" with format junit or tap. Without a path the report
+	// goes to standard output in place of the usual summary.
+	Reports []string
+
+	// Fix makes skill lint repair the findings its rules know how to fix.
+	Fix bool
+	// Format selects skill lint's output: text, json or sarif. Empty
+	// follows --output.
+	Format string
+
+	// UpdateBaseline makes skill scan accept the current findings by
+	// writing them to the skill's .aios-secrets-baseline.json.
+	UpdateBaseline bool
+
+	// Dest is the directory skill unpack extracts a package into; empty
+	// uses a directory named after the skill beside the package.
+	Dest string
+
+	// SkillType, Description, InputFields and OutputFields describe the
+	// skill skill new creates without the wizard. Fields are written as
+	// "name[?]:type:description".
+	SkillType    string
+	Description  string
+	InputFields  []string
+	OutputFields []string
+
+	// Write makes skill schema infer write the inferred schemas instead
+	// of only showing how they differ.
+	Write bool
+}
+
 type CLI struct {
 	In                 io.Reader
 	Out                io.Writer
+	Flags              CommandFlags
 	SyncState          func() string
 	ServeMCP           func(context.Context, *mcpg.Server, ...mcpg.ServeOption) error
 	Health             func() runtime.HealthReport
 	SyncSkill          func(ctx context.Context, command domainskillsync.SyncSkillCommand) (string, error)
+	SyncProjects       func(ctx context.Context, command domainskillsync.SyncProjectsCommand, progress domainskillsync.ProgressFunc) (domainskillsync.SyncProjectsResult, error)
 	TestSkill          func(ctx context.Context, command domainskilltest.TestSkillCommand) (domainskilltest.TestSkillResult, error)
 	SyncPlan           func(ctx context.Context, command domainsyncplan.BuildSyncPlanCommand) (domainsyncplan.BuildSyncPlanResult, error)
 	InitSkill          func(skillDir string) error
+	NewSkill           func(spec builder.Spec) error
 	LintSkill          func(ctx context.Context, command domainskilllint.LintSkillCommand) (domainskilllint.LintSkillResult, error)
 	BuildInfo          func() BuildInfo
 	Doctor             func() DoctorReport
@@ -97,6 +175,14 @@ type CLI struct {
 	ModelPolicyPacks   func() []model.PolicyPack
 	PackageSkill       func(ctx context.Context, command domainskillpackage.PackageSkillCommand) (domainskillpackage.PackageSkillResult, error)
 	UninstallSkill     func(ctx context.Context, command domainskilluninstall.UninstallSkillCommand) (string, error)
+	SkillsStatus       func() ([]agents.SkillStatus, error)
+	InstallLocked      func(frozen bool) ([]string, error)
+	PruneSkills        func(global, apply bool) (*agents.PruneResult, error)
+	VerifyPackage      func(packagePath string) (*skill.Manifest, error)
+	UnpackSkill        func(packagePath, destDir string) (string, *skill.Manifest, error)
+	ScanSecrets        func(skillDir string, updateBaseline bool) (secrets.Report, error)
+	ScanInstalled      func(global bool) ([]InstalledSecrets, error)
+	InferSchemas       func(skillDir string, write bool) ([]skill.InferredSchema, error)
 	BackupConfigs      func() (string, error)
 	RestoreConfigs     func(backupDir string) (string, error)
 	ExportReport       func(path string) (string, error)
@@ -123,12 +209,22 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 		skillMetadataResolverAdapter{},
 		skillPackagerAdapter{},
 	)
-	testService := applicationskilltest.NewService(fixtureRunnerAdapter{})
-	lintService := applicationskilllint.NewService(skillLinterAdapter{})
+	var provider model.Provider
+	if p, ok := model.OpenAIProviderFromEnv(); ok {
+		provider = p
+	}
+	fixtureRunner := fixtureRunnerAdapter{provider: provider}
+	testService := applicationskilltest.NewService(fixtureRunner, fixtureRunner, fixtureRunner)
+	lintService := applicationskilllint.NewService(skillLinterAdapter{provider: provider})
 	projectInventoryService := applicationprojectinventory.NewService(
 		fileProjectInventoryRepository{workspaceDir: cfg.WorkspaceDir},
 		absPathCanonicalizer{},
 	)
+	projectSyncService := applicationskillsync.NewProjectSyncService(
+		skillSpecResolverAdapter{},
+		clientInstallerAdapter{cfg: cfg},
+		projectSyncSourceAdapter{inventory: projectInventoryService},
+	)
 	syncPlanService := applicationsyncplan.NewService(
 		syncPlanSkillResolverAdapter{},
 		syncPlanWriteTargetPlannerAdapter{cfg: cfg},
@@ -159,9 +255,10 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 			rt := runtime.New(cfg.WorkspaceDir, runtime.NewMemoryTokenStore())
 			return rt.Health()
 		},
-		SyncSkill: syncService.SyncSkill,
-		TestSkill: testService.TestSkill,
-		SyncPlan:  syncPlanService.BuildSyncPlan,
+		SyncSkill:    syncService.SyncSkill,
+		SyncProjects: projectSyncService.SyncProjects,
+		TestSkill:    testService.TestSkill,
+		SyncPlan:     syncPlanService.BuildSyncPlan,
 		InitSkill: func(skillDir string) error {
 			if skillDir == "" {
 				return fmt.Errorf("skill directory is required\n\nUsage: aios skills init <skill-dir>\nExample: aios skills init my-skill")
@@ -172,6 +269,7 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 				Dir:     filepath.Dir(skillDir),
 			})
 		},
+		NewSkill:  builder.BuildSkill,
 		LintSkill: lintService.LintSkill,
 		BuildInfo: CurrentBuildInfo,
 		Doctor: func() DoctorReport {
@@ -193,10 +291,16 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 				}
 				return out, true
 			}
-			allAgents, err := agents.LoadAll()
+			sourced, err := agents.LoadAllWithSources()
 			if err != nil {
 				return map[string]any{"error": err.Error()}
 			}
+			sources := make(map[string]string, len(sourced))
+			allAgents := make([]agentregistry.AgentDefinition, 0, len(sourced))
+			for _, a := range sourced {
+				sources[a.Name] = a.Source
+				allAgents = append(allAgents, a.AgentDefinition)
+			}
 			installedAgents := agents.DetectInstalled(allAgents)
 			if len(installedAgents) == 0 {
 				return map[string]any{"status": "no_clients_detected", "message": "No AI clients found. Install Cursor, Claude Code, Windsurf, or other supported agents."}
@@ -204,18 +308,26 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 			result := make(map[string]any, len(installedAgents))
 			for _, agent := range installedAgents {
 				agentDir := filepath.Join(cfg.ProjectDir, agent.SkillsDir)
+				renderer := agent.Renderer
+				if renderer == "" {
+					renderer = skill.RendererSkillMd
+				}
 				files, ok := collect(agentDir)
 				if !ok {
 					result[agent.Name] = map[string]any{
 						"path":      agentDir,
 						"installed": false,
 						"skills":    []string{},
+						"source":    sources[agent.Name],
+						"renderer":  renderer,
 					}
 				} else {
 					result[agent.Name] = map[string]any{
 						"path":      agentDir,
 						"installed": true,
 						"skills":    files,
+						"source":    sources[agent.Name],
+						"renderer":  renderer,
 					}
 				}
 			}
@@ -239,6 +351,22 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 		ModelPolicyPacks: modelRouter.Packs,
 		PackageSkill:     packageService.PackageSkill,
 		UninstallSkill:   uninstallService.UninstallSkill,
+		SkillsStatus: func() ([]agents.SkillStatus, error) {
+			return SkillsStatus(cfg)
+		},
+		InstallLocked: func(frozen bool) ([]string, error) {
+			return InstallFromLockfile(cfg, frozen)
+		},
+		PruneSkills: func(global, apply bool) (*agents.PruneResult, error) {
+			return PruneSkills(cfg, global, apply)
+		},
+		VerifyPackage: skill.VerifyPackage,
+		UnpackSkill:   skill.UnpackSkill,
+		ScanSecrets:   ScanSecrets,
+		ScanInstalled: func(global bool) ([]InstalledSecrets, error) {
+			return ScanInstalledSecrets(cfg, global)
+		},
+		InferSchemas: skill.InferSchemas,
 		BackupConfigs: func() (string, error) {
 			return BackupClientConfigs(cfg)
 		},
@@ -345,13 +473,22 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 			if err != nil {
 				return nil, err
 			}
-			allAgents, loadErr := agents.LoadAll()
-			if loadErr != nil {
-				return nil, loadErr
+			report, err := secrets.Scan(skillDir, "")
+			if err != nil {
+				return nil, err
 			}
-			agentNames := make([]string, 0, len(allAgents))
-			for _, a := range allAgents {
-				agentNames = append(agentNames, a.Name)
+			if err := report.Err(); err != nil {
+				return nil, fmt.Errorf("publish %s: %w", spec.ID, err)
+			}
+			clients := spec.Clients
+			if len(clients) == 0 {
+				allAgents, loadErr := agents.LoadAll()
+				if loadErr != nil {
+					return nil, loadErr
+				}
+				for _, a := range allAgents {
+					clients = append(clients, a.Name)
+				}
 			}
 			reg, err := registry.NewCloudRegistryWithPath(filepath.Join(cfg.WorkspaceDir, "registry", "cloud.json"))
 			if err != nil {
@@ -360,11 +497,11 @@ func DefaultCLI(out io.Writer, cfg Config) CLI {
 			if err := reg.Publish(registry.SkillVersion{
 				ID:                spec.ID,
 				Version:           spec.Version,
-				CompatibleClients: agentNames,
+				CompatibleClients: clients,
 			}); err != nil {
 				return nil, err
 			}
-			return map[string]any{"published": true, "skill_id": spec.ID, "version": spec.Version}, nil
+			return map[string]any{"published": true, "skill_id": spec.ID, "version": spec.Version, "compatible_clients": clients}, nil
 		},
 		MarketplaceList: func(_ context.Context) (map[string]any, error) {
 			reg, err := registry.NewCloudRegistryWithPath(filepath.Join(cfg.WorkspaceDir, "registry", "cloud.json"))
@@ -513,21 +650,28 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 		_, _ = fmt.Fprintf(c.Out, "status: %s\nready: %t\nsync: %s\ntoken_store: %s\nworkspace: %s\n", h.Status, h.Ready, c.SyncState(), h.TokenStore, h.Workspace)
 		return nil
 	case "sync":
+		if c.Flags.AllProjects || len(c.Flags.Projects) > 0 {
+			return c.syncProjects(ctx, skillDir, output)
+		}
 		pg := newProgressWriter(c.Out)
 		if output != "json" {
 			pg.Start(fmt.Sprintf("Syncing skill from %s...", skillDir))
 		}
-		skillID, err := c.SyncSkill(ctx, domainskillsync.SyncSkillCommand{SkillDir: skillDir})
+		skillID, err := c.SyncSkill(ctx, domainskillsync.SyncSkillCommand{SkillDir: skillDir, Global: c.Flags.Global, Force: c.Flags.Force, Agents: c.Flags.Agents})
 		if err != nil {
 			return err
 		}
 		if output == "json" {
-			return writeJSON(map[string]any{"synced": true, "skill_id": skillID})
+			return writeJSON(map[string]any{"synced": true, "skill_id": skillID, "global": c.Flags.Global})
+		}
+		if c.Flags.Global {
+			pg.Stop(fmt.Sprintf("✓ global sync completed for skill %s", skillID))
+			return nil
 		}
 		pg.Stop(fmt.Sprintf("✓ sync completed for skill %s", skillID))
 		return nil
 	case "sync-plan":
-		plan, err := c.SyncPlan(ctx, domainsyncplan.BuildSyncPlanCommand{SkillDir: skillDir})
+		plan, err := c.SyncPlan(ctx, domainsyncplan.BuildSyncPlanCommand{SkillDir: skillDir, Global: c.Flags.Global, Agents: c.Flags.Agents})
 		if err != nil {
 			return err
 		}
@@ -535,12 +679,27 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 			return writeJSON(plan)
 		}
 		_, _ = fmt.Fprintf(c.Out, "sync plan for skill %s\n", plan.SkillID)
+		if len(plan.Agents) > 0 {
+			_, _ = fmt.Fprintf(c.Out, "agents: %s\n", strings.Join(plan.Agents, ", "))
+		}
 		for _, write := range plan.Writes {
 			_, _ = fmt.Fprintf(c.Out, "- %s\n", write)
 		}
+		if len(plan.Files) > 0 {
+			_, _ = fmt.Fprintln(c.Out, "files:")
+			for _, f := range plan.Files {
+				_, _ = fmt.Fprintf(c.Out, "- %s\n", f)
+			}
+		}
+		if len(plan.Sections) > 0 {
+			_, _ = fmt.Fprintln(c.Out, "prompt sections:")
+			for _, s := range plan.Sections {
+				_, _ = fmt.Fprintf(c.Out, "- %s: %s\n", s.Trigger, s.File)
+			}
+		}
 		return nil
 	case "serve-mcp":
-		srv := aosmcp.NewServer("0.1.0")
+		srv := aosmcp.NewServer(Version)
 		mw := mcpg.Recover()
 		switch mcpTransport {
 		case "stdio", "":
@@ -553,35 +712,32 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 			return fmt.Errorf("unsupported mcp transport %q", mcpTransport)
 		}
 	case "test-skill":
+		reports, err := parseTestReports(c.Flags.Reports)
+		if err != nil {
+			return err
+		}
+		if len(reports) > 0 && (c.Flags.Update || c.Flags.Check) {
+			return fmt.Errorf("--report cannot be combined with --update or --check")
+		}
 		pg := newProgressWriter(c.Out)
-		if output != "json" {
+		if output != "json" && !reportsToStdout(reports) {
 			pg.Start(fmt.Sprintf("Running fixtures for %s...", skillDir))
 		}
-		result, err := c.TestSkill(ctx, domainskilltest.TestSkillCommand{SkillDir: skillDir})
+		result, err := c.TestSkill(ctx, domainskilltest.TestSkillCommand{
+			SkillDir:  skillDir,
+			Update:    c.Flags.Update,
+			Check:     c.Flags.Check,
+			Recursive: c.Flags.Recursive,
+			Parallel:  c.Flags.Parallel,
+			Timeout:   c.Flags.Timeout,
+		})
 		if err != nil {
 			return err
 		}
-		if output == "json" {
-			return writeJSON(map[string]any{
-				"failed":  result.Failed,
-				"results": result.Results,
-			})
+		if c.Flags.Update || c.Flags.Check {
+			return c.printSnapshots(result, output)
 		}
-		for _, r := range result.Results {
-			state := "PASS"
-			if !r.Passed {
-				state = "FAIL"
-			}
-			if r.Error != "" {
-				_, _ = fmt.Fprintf(c.Out, "%s %s (%s)\n", state, r.Name, r.Error)
-			} else {
-				_, _ = fmt.Fprintf(c.Out, "%s %s\n", state, r.Name)
-			}
-		}
-		if result.Failed > 0 {
-			return fmt.Errorf("%d fixture(s) failed", result.Failed)
-		}
-		return nil
+		return c.printTestResult(result, skillDir, output, reports)
 	case "init-skill":
 		pg := newProgressWriter(c.Out)
 		if output != "json" {
@@ -596,30 +752,53 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 		pg.Stop(fmt.Sprintf("✓ skill scaffold created at %s", skillDir))
 		return nil
 	case "help":
-		_, _ = fmt.Fprintln(c.Out, "commands: status | tray-status | version | doctor | list-clients | model-policy-packs | analytics-summary | analytics-record | analytics-trend | marketplace-publish --skill-dir <dir> | marketplace-list | marketplace-install --skill-dir <skill-id> | marketplace-matrix | audit-export [--skill-dir <output-file>] | audit-verify [--skill-dir <input-file>] | runtime-execution-report [--skill-dir <output-file>] | project-list | project-add --skill-dir <path> | project-remove --skill-dir <path-or-id> | project-inspect --skill-dir <path-or-id> | workspace-validate | workspace-plan | workspace-repair | tui | backup-configs | restore-configs [--skill-dir <backup-dir>] | export-status-report [--skill-dir <output-file>] | connect-google-drive | sync --skill-dir <dir> | uninstall-skill --skill-dir <dir> | sync-plan --skill-dir <dir> | test-skill --skill-dir <dir> | lint-skill --skill-dir <dir> | init-skill --skill-dir <dir> | package-skill --skill-dir <dir> | serve-mcp [--mcp-transport stdio|http|ws --mcp-addr :8080]")
+		_, _ = fmt.Fprintln(c.Out, "commands: status | tray-status | version | doctor | list-clients | model-policy-packs | analytics-summary | analytics-record | analytics-trend | marketplace-publish --skill-dir <dir> | marketplace-list | marketplace-install --skill-dir <skill-id> | marketplace-matrix | audit-export [--skill-dir <output-file>] | audit-verify [--skill-dir <input-file>] | runtime-execution-report [--skill-dir <output-file>] | project-list | project-add --skill-dir <path> | project-remove --skill-dir <path-or-id> | project-inspect --skill-dir <path-or-id> | workspace-validate | workspace-plan | workspace-repair | tui | backup-configs | restore-configs [--skill-dir <backup-dir>] | export-status-report [--skill-dir <output-file>] | connect-google-drive | sync --skill-dir <dir> [--force] [--agents <names>] [--all-projects | --project <id-or-path>] | uninstall-skill --skill-dir <dir> [--force] | skills-status | skills-install [--frozen] | skills-prune [--global] [--apply] | sync-plan --skill-dir <dir> | test-skill --skill-dir <dir> [--update | --check] [--recursive] | lint-skill --skill-dir <dir> [--fix] [--format text|json|sarif] | init-skill --skill-dir <dir> | skills-new --skill-dir <dir> [--type <type>] | skills-schema-infer --skill-dir <dir> [--write] | skills-scan [--skill-dir <dir>] [--update-baseline] | package-skill --skill-dir <dir> | skills-verify-package --skill-dir <package> | skills-unpack --skill-dir <package> | serve-mcp [--mcp-transport stdio|http|ws --mcp-addr :8080]")
 		return nil
 	case "lint-skill":
+		format := strings.ToLower(strings.TrimSpace(c.Flags.Format))
+		switch {
+		case format == "" && output == "json":
+			format = "json"
+		case format == "":
+			format = "text"
+		case format != "text" && format != "json" && format != "sarif":
+			return fmt.Errorf("unknown lint format %q (want text, json or sarif)", c.Flags.Format)
+		}
 		pg := newProgressWriter(c.Out)
-		if output != "json" {
+		if format == "text" {
 			pg.Start(fmt.Sprintf("Linting skill %s...", skillDir))
 		}
-		res, err := c.LintSkill(ctx, domainskilllint.LintSkillCommand{SkillDir: skillDir})
+		res, err := c.LintSkill(ctx, domainskilllint.LintSkillCommand{SkillDir: skillDir, Fix: c.Flags.Fix})
 		if err != nil {
 			return err
 		}
-		if output == "json" {
+		switch format {
+		case "json":
 			return writeJSON(map[string]any{
-				"valid":  res.Valid,
-				"issues": res.Issues,
+				"valid":    res.Valid,
+				"issues":   res.Issues,
+				"findings": nonNilFindings(res.Findings),
+				"fixed":    nonNilFindings(res.Fixed),
 			})
+		case "sarif":
+			return writeJSON(lintSARIF(res, skillDir, c.BuildInfo().Version))
+		}
+		for _, f := range res.Fixed {
+			f.Fixable = false
+			_, _ = fmt.Fprintf(c.Out, "fixed %s\n", formatLintFinding(f))
+		}
+		for _, f := range res.Findings {
+			_, _ = fmt.Fprintf(c.Out, "- %s\n", formatLintFinding(f))
+		}
+		if len(res.Findings) == 0 {
+			for _, issue := range res.Issues {
+				_, _ = fmt.Fprintf(c.Out, "- %s\n", issue)
+			}
 		}
 		if res.Valid {
 			pg.Stop("✓ lint: ok")
 			return nil
 		}
-		for _, issue := range res.Issues {
-			_, _ = fmt.Fprintf(c.Out, "- %s\n", issue)
-		}
 		return fmt.Errorf("lint failed: %d issue(s)", len(res.Issues))
 	case "version":
 		b := c.BuildInfo()
@@ -886,7 +1065,7 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 		if output != "json" {
 			pg.Start(fmt.Sprintf("Uninstalling skill %s...", skillDir))
 		}
-		skillID, err := c.UninstallSkill(ctx, domainskilluninstall.UninstallSkillCommand{SkillDir: skillDir})
+		skillID, err := c.UninstallSkill(ctx, domainskilluninstall.UninstallSkillCommand{SkillDir: skillDir, Global: c.Flags.Global, Force: c.Flags.Force, Agents: c.Flags.Agents})
 		if err != nil {
 			return err
 		}
@@ -895,6 +1074,180 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 		}
 		pg.Stop(fmt.Sprintf("✓ uninstalled skill: %s", skillID))
 		return nil
+	case "skills-verify-package":
+		manifest, err := c.VerifyPackage(skillDir)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return writeJSON(manifest)
+		}
+		_, _ = fmt.Fprintf(c.Out, "✓ package %s holds skill %s %s (%d files)\n", skillDir, manifest.ID, manifest.Version, len(manifest.Files))
+		return nil
+	case "skills-unpack":
+		dir, manifest, err := c.UnpackSkill(skillDir, c.Flags.Dest)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return writeJSON(map[string]any{"skill_dir": dir, "manifest": manifest})
+		}
+		_, _ = fmt.Fprintf(c.Out, "✓ unpacked skill %s %s into %s\n", manifest.ID, manifest.Version, dir)
+		return nil
+	case "skills-new":
+		return c.newSkill(ctx, skillDir, output)
+	case "skills-schema-infer":
+		return c.inferSchemas(skillDir, output)
+	case "skills-scan":
+		if skillDir == "" {
+			if c.Flags.UpdateBaseline {
+				return fmt.Errorf("--update-baseline needs a skill directory\n\nRun 'aios skills scan <skill-dir> --update-baseline' on the skill's source")
+			}
+			results, err := c.ScanInstalled(c.Flags.Global)
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				if err := writeJSON(results); err != nil {
+					return err
+				}
+			}
+			found := 0
+			for _, r := range results {
+				found += len(r.Findings)
+				if output == "json" {
+					continue
+				}
+				for _, f := range r.Findings {
+					_, _ = fmt.Fprintf(c.Out, "- %s: %s\n", r.SkillID, f)
+				}
+			}
+			if found > 0 {
+				return fmt.Errorf("%d possible secret(s) found in installed skills\n\nRemove them from the skill sources, or accept reviewed findings with 'aios skills scan <skill-dir> --update-baseline' and sync again", found)
+			}
+			if output != "json" {
+				_, _ = fmt.Fprintf(c.Out, "✓ no secrets found in %d installed skill(s)\n", len(results))
+			}
+			return nil
+		}
+		report, err := c.ScanSecrets(skillDir, c.Flags.UpdateBaseline)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			if err := writeJSON(report); err != nil {
+				return err
+			}
+			return report.Err()
+		}
+		if err := report.Err(); err != nil {
+			return err
+		}
+		switch {
+		case c.Flags.UpdateBaseline:
+			_, _ = fmt.Fprintf(c.Out, "✓ baseline %s accepts %d finding(s)\n", report.Baseline, len(report.Accepted))
+		case len(report.Accepted) > 0:
+			_, _ = fmt.Fprintf(c.Out, "✓ no secrets found in %s (%d accepted by baseline)\n", skillDir, len(report.Accepted))
+		default:
+			_, _ = fmt.Fprintf(c.Out, "✓ no secrets found in %s\n", skillDir)
+		}
+		return nil
+	case "skills-prune":
+		result, err := c.PruneSkills(c.Flags.Global, c.Flags.Apply)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			if err := writeJSON(result); err != nil {
+				return err
+			}
+		} else {
+			switch {
+			case len(result.Actions) == 0:
+				_, _ = fmt.Fprintln(c.Out, "nothing to prune")
+			case result.Applied:
+				_, _ = fmt.Fprintf(c.Out, "pruned %d of %d entries\n", len(result.Actions)-result.Failed(), len(result.Actions))
+			default:
+				_, _ = fmt.Fprintf(c.Out, "%d entries would be pruned (dry run; rerun with --apply to remove them)\n", len(result.Actions))
+			}
+			for _, a := range result.Actions {
+				line := fmt.Sprintf("- %s: %s", a.Kind, a.Path)
+				if a.Target != "" {
+					line += " -> " + a.Target
+				}
+				if a.Error != "" {
+					line += " (failed: " + a.Error + ")"
+				}
+				_, _ = fmt.Fprintln(c.Out, line)
+			}
+		}
+		if n := result.Failed(); n > 0 {
+			return fmt.Errorf("failed to prune %d entries", n)
+		}
+		return nil
+	case "skills-status":
+		statuses, err := c.SkillsStatus()
+		if err != nil {
+			return err
+		}
+		clean := true
+		for _, st := range statuses {
+			if st.State != agents.StatusOK {
+				clean = false
+			}
+		}
+		if output == "json" {
+			if err := writeJSON(map[string]any{"clean": clean, "skills": statuses}); err != nil {
+				return err
+			}
+		} else {
+			if len(statuses) == 0 {
+				_, _ = fmt.Fprintln(c.Out, "no skills installed")
+			}
+			for _, st := range statuses {
+				label := st.ID
+				if st.Version != "" {
+					label += "@" + st.Version
+				}
+				_, _ = fmt.Fprintf(c.Out, "%s: %s\n", label, st.State)
+				for _, f := range st.ModifiedFiles {
+					_, _ = fmt.Fprintf(c.Out, "  modified: %s\n", f)
+				}
+				for _, f := range st.MissingFiles {
+					_, _ = fmt.Fprintf(c.Out, "  missing: %s\n", f)
+				}
+				for _, f := range st.ExtraFiles {
+					_, _ = fmt.Fprintf(c.Out, "  unexpected: %s\n", f)
+				}
+				for _, agent := range sortedIssueKeys(st.LinkIssues) {
+					_, _ = fmt.Fprintf(c.Out, "  %s: %s\n", agent, st.LinkIssues[agent])
+				}
+				for _, artifact := range sortedIssueKeys(st.ArtifactIssues) {
+					_, _ = fmt.Fprintf(c.Out, "  %s: %s\n", st.ArtifactIssues[artifact], artifact)
+				}
+			}
+		}
+		if !clean {
+			return fmt.Errorf("installed skills differ from lockfile\n\nRun 'aios skills install' to reinstall from the lockfile")
+		}
+		return nil
+	case "skills-install":
+		pg := newProgressWriter(c.Out)
+		if output != "json" {
+			pg.Start("Installing skills from lockfile...")
+		}
+		installed, err := c.InstallLocked(c.Flags.Frozen)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return writeJSON(map[string]any{"installed": installed, "frozen": c.Flags.Frozen})
+		}
+		pg.Stop(fmt.Sprintf("✓ installed %d skill(s) from lockfile", len(installed)))
+		for _, id := range installed {
+			_, _ = fmt.Fprintf(c.Out, "- %s\n", id)
+		}
+		return nil
 	case "backup-configs":
 		path, err := c.BackupConfigs()
 		if err != nil {
@@ -973,3 +1326,14 @@ func (c CLI) Run(ctx context.Context, cmd string, skillDir string, mcpTransport
 		return fmt.Errorf("unknown cli command %q", cmd)
 	}
 }
+
+// sortedIssueKeys returns the keys of a status issue map in order, so the
+// issues print the same way every run.
+func sortedIssueKeys(issues map[string]string) []string {
+	keys := make([]string, 0, len(issues))
+	for k := range issues {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
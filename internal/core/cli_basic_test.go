@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -79,6 +81,13 @@ func TestCLIHelp(t *testing.T) {
 		"workspace-plan",
 		"workspace-repair",
 		"tui",
+		"skills-status",
+		"skills-install",
+		"skills-prune",
+		"skills-new",
+		"skills-schema-infer",
+		"skills-verify-package",
+		"skills-unpack",
 	} {
 		if !strings.Contains(out, cmd) {
 			t.Fatalf("help output missing %q: %q", cmd, out)
@@ -341,3 +350,34 @@ func TestCLITrayStatusJSON(t *testing.T) {
 		t.Fatalf("missing updated_at: %#v", out)
 	}
 }
+
+func TestCLIListClientsReportsDefinitionSource(t *testing.T) {
+	configDir := t.TempDir()
+	detectDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("AIOS_WORKSPACE_DIR", t.TempDir())
+	if err := os.MkdirAll(filepath.Join(configDir, "aios"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body := `[{"name":"inhouse","displayName":"In-House","skillsDir":".inhouse/skills","detectPaths":["` + detectDir + `"]}]`
+	if err := os.WriteFile(filepath.Join(configDir, "aios", "agents.json"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	if err := cli.Run(context.Background(), "list-clients", "", "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("list-clients failed: %v", err)
+	}
+	var out map[string]map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	entry, ok := out["inhouse"]
+	if !ok {
+		t.Fatalf("missing user-defined agent: %s", buf.String())
+	}
+	if entry["source"] != "user" {
+		t.Fatalf("expected source=user, got %#v", entry["source"])
+	}
+}
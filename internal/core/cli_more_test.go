@@ -77,6 +77,48 @@ func TestDefaultCLISyncJSON(t *testing.T) {
 	}
 }
 
+func TestDefaultCLISyncPassesGlobalFlag(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.Flags = CommandFlags{Global: true}
+	var got domainskillsync.SyncSkillCommand
+	cli.SyncSkill = func(_ context.Context, cmd domainskillsync.SyncSkillCommand) (string, error) {
+		got = cmd
+		return "test-skill", nil
+	}
+
+	if err := cli.Run(context.Background(), "sync", "./test-skill", "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("sync json failed: %v", err)
+	}
+	if !got.Global {
+		t.Fatalf("expected global sync command, got %#v", got)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if out["global"] != true {
+		t.Fatalf("unexpected json: %#v", out)
+	}
+}
+
+func TestDefaultCLIUninstallPassesForceFlag(t *testing.T) {
+	cli := DefaultCLI(&bytes.Buffer{}, DefaultConfig())
+	cli.Flags = CommandFlags{Force: true}
+	var got domainskilluninstall.UninstallSkillCommand
+	cli.UninstallSkill = func(_ context.Context, cmd domainskilluninstall.UninstallSkillCommand) (string, error) {
+		got = cmd
+		return "test-skill", nil
+	}
+
+	if err := cli.Run(context.Background(), "uninstall-skill", "./test-skill", "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if !got.Force {
+		t.Fatalf("expected forced uninstall command, got %#v", got)
+	}
+}
+
 func TestDefaultCLITestSkill(t *testing.T) {
 	buf := &bytes.Buffer{}
 	cli := DefaultCLI(buf, DefaultConfig())
@@ -118,6 +160,36 @@ func TestDefaultCLITestSkillFails(t *testing.T) {
 	}
 }
 
+func TestDefaultCLITestSkillPrintsMismatches(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.TestSkill = func(context.Context, domainskilltest.TestSkillCommand) (domainskilltest.TestSkillResult, error) {
+		return domainskilltest.TestSkillResult{
+			Failed: 1,
+			Results: []domainskilltest.FixtureResult{
+				{Name: "fixture_01.json", Passed: false, Error: "output mismatch", Mismatches: []domainskilltest.Mismatch{
+					{Path: "/summary/title", Message: "not equal", Expected: `"Q4"`, Actual: `"Q3"`},
+					{Path: "/owner", Message: "missing", Expected: `"me"`},
+				}},
+			},
+		}, nil
+	}
+
+	if err := cli.Run(context.Background(), "test-skill", "./test-skill", "stdio", ":8080", "text"); err == nil {
+		t.Fatal("expected error for failed tests")
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"FAIL fixture_01.json 0ms (output mismatch)",
+		"  /summary/title: not equal\n    expected: \"Q4\"\n    actual:   \"Q3\"\n",
+		"  /owner: missing\n    expected: \"me\"\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
 func TestDefaultCLITestSkillJSON(t *testing.T) {
 	buf := &bytes.Buffer{}
 	cli := DefaultCLI(buf, DefaultConfig())
@@ -818,7 +890,7 @@ outputs:
 	}
 
 	adapter := skillLinterAdapter{}
-	result, err := adapter.Lint(context.Background(), skillDir)
+	result, err := adapter.Lint(context.Background(), skillDir, false)
 	if err != nil {
 		t.Fatalf("Lint failed: %v", err)
 	}
@@ -1045,7 +1117,7 @@ func TestClientUninstallerAdapter(t *testing.T) {
 	}
 	adapter := clientUninstallerAdapter{cfg: cfg}
 
-	err := adapter.UninstallAcrossClients(context.Background(), "test-skill")
+	err := adapter.UninstallAcrossClients(context.Background(), domainskilluninstall.UninstallRequest{SkillID: "test-skill"})
 	if err != nil {
 		t.Fatalf("UninstallAcrossClients failed: %v", err)
 	}
@@ -1170,7 +1242,7 @@ func TestSkillLintAdapterErrors(t *testing.T) {
 	}
 
 	adapter := skillLinterAdapter{}
-	_, err := adapter.Lint(context.Background(), skillDir)
+	_, err := adapter.Lint(context.Background(), skillDir, false)
 	if err == nil {
 		t.Fatal("expected error for invalid skill")
 	}
@@ -1237,13 +1309,17 @@ func TestSyncPlanWriteTargetPlannerAdapter(t *testing.T) {
 	cfg := Config{
 		ProjectDir: t.TempDir(),
 	}
+	skillDir := filepath.Join(cfg.ProjectDir, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
 	adapter := syncPlanWriteTargetPlannerAdapter{cfg: cfg}
 
-	writes, err := adapter.PlanWriteTargets(context.Background(), "test-skill")
+	writes, err := adapter.PlanWriteTargets(context.Background(), domainsyncplan.PlanRequest{SkillID: "lock-reader", SkillDir: skillDir})
 	if err != nil {
 		t.Fatalf("PlanWriteTargets failed: %v", err)
 	}
-	_ = writes
+	if len(writes.Targets) == 0 || len(writes.Files) == 0 {
+		t.Fatalf("expected targets and files, got %#v", writes)
+	}
 }
 
 func TestDefaultCLIAnalyticsRecordWithRealFiles(t *testing.T) {
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/skill"
 )
 
 func TestCLISyncRequiresSkillDir(t *testing.T) {
@@ -95,6 +100,132 @@ func TestCLITestSkillPasses(t *testing.T) {
 	}
 }
 
+// useFakeModel points the CLI's model provider at a server that answers
+// every completion with reply, and returns the prompts it received.
+func useFakeModel(t *testing.T, reply string) *[]string {
+	t.Helper()
+	var prompts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model == "" || len(req.Messages) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		prompts = append(prompts, req.Messages[0].Content)
+		body, _ := json.Marshal(map[string]any{
+			"model":   req.Model,
+			"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": reply}}},
+		})
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("AIOS_MODEL_BASE_URL", srv.URL)
+	t.Setenv("AIOS_MODEL_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	return &prompts
+}
+
+func TestCLITestSkillRunsOnModelProvider(t *testing.T) {
+	prompts := useFakeModel(t, `{"answer":"because"}`)
+	skillDir := filepath.Join(t.TempDir(), "skill")
+	if err := os.MkdirAll(filepath.Join(skillDir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"skill.yaml":             "id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":              "Explain the roadmap.\n",
+		"schema.input.json":      `{"type":"object","properties":{"query":{"type":"string"}}}`,
+		"schema.output.json":     `{"type":"object","required":["answer"],"properties":{"answer":{"type":"string"}}}`,
+		"tests/fixture_01.json":  `{"query":"why"}`,
+		"tests/expected_01.json": `{"answer":"because"}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	if err := cli.Run(context.Background(), "test-skill", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("test-skill failed: %v\n%s", err, buf.String())
+	}
+	if len(*prompts) != 1 || !strings.Contains((*prompts)[0], "Explain the roadmap.") {
+		t.Fatalf("expected the skill prompt to reach the model, got %q", *prompts)
+	}
+}
+
+func TestCLITestSkillCheckAndUpdateSnapshots(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "skill")
+	if err := os.MkdirAll(filepath.Join(skillDir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"skill.yaml":             "id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":              "Read the roadmap.\n",
+		"schema.input.json":      `{"type":"object","properties":{"query":{"type":"string"}}}`,
+		"schema.output.json":     `{"type":"object","properties":{"status":{"type":"string"}}}`,
+		"tests/fixture_01.json":  `{"query":"x"}`,
+		"tests/expected_01.json": `{"status":"ok"}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(flags CommandFlags) (string, error) {
+		buf := &bytes.Buffer{}
+		cli := DefaultCLI(buf, DefaultConfig())
+		cli.Flags = flags
+		err := cli.Run(context.Background(), "test-skill", skillDir, "stdio", ":8080", "text")
+		return buf.String(), err
+	}
+
+	// Without a provider only stub output is available, which is never
+	// recorded.
+	t.Setenv("AIOS_MODEL_BASE_URL", "")
+	t.Setenv("AIOS_MODEL_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	for _, flags := range []CommandFlags{{Update: true}, {Check: true}} {
+		if out, err := run(flags); err == nil || !strings.Contains(err.Error(), "no handler configured for skill roadmap-reader") {
+			t.Fatalf("expected a missing handler error for %+v, got %v\n%s", flags, err, out)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(skillDir, "tests", "expected_01.json")); string(data) != `{"status":"ok"}` {
+		t.Fatalf("expected file was rewritten without a provider:\n%s", data)
+	}
+
+	useFakeModel(t, `{"skill_id":"roadmap-reader","status":"ok"}`)
+	out, err := run(CommandFlags{Check: true})
+	if err == nil || !strings.Contains(err.Error(), "1 snapshot(s) are stale") {
+		t.Fatalf("expected stale snapshot error, got %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "stale     expected_01.json") || !strings.Contains(out, `+  "skill_id": "roadmap-reader",`) {
+		t.Fatalf("unexpected check output:\n%s", out)
+	}
+
+	out, err = run(CommandFlags{Update: true})
+	if err != nil {
+		t.Fatalf("update failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "updated   expected_01.json") || !strings.Contains(out, "snapshots: 1 updated") {
+		t.Fatalf("unexpected update output:\n%s", out)
+	}
+
+	if out, err := run(CommandFlags{Check: true}); err != nil || !strings.Contains(out, "snapshots: 1 unchanged") {
+		t.Fatalf("expected fresh snapshots, got %v\n%s", err, out)
+	}
+	if _, err := run(CommandFlags{Check: true, Update: true}); err == nil {
+		t.Fatal("expected --update and --check to conflict")
+	}
+}
+
 func TestCLILintSkill(t *testing.T) {
 	root := t.TempDir()
 	buf := &bytes.Buffer{}
@@ -144,6 +275,57 @@ func TestCLIPackageSkill(t *testing.T) {
 	}
 }
 
+func TestCLIVerifyAndUnpackPackage(t *testing.T) {
+	root := t.TempDir()
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+
+	skillDir := filepath.Join(root, "skill")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "skill.yaml"), []byte("id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "schema.input.json"), []byte(`{"type":"object","properties":{"q":{"type":"string"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "schema.output.json"), []byte(`{"type":"object","properties":{"a":{"type":"string"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pkg := filepath.Join(root, "roadmap-reader.zip")
+	if err := skill.PackageSkill(skillDir, pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cli.Run(context.Background(), "skills-verify-package", pkg, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("verify-package failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "holds skill roadmap-reader 0.1.0 (3 files)") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+
+	buf.Reset()
+	dest := filepath.Join(root, "unpacked")
+	cli.Flags = CommandFlags{Dest: dest}
+	if err := cli.Run(context.Background(), "skills-unpack", pkg, "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("unpack failed: %v", err)
+	}
+	var out struct {
+		SkillDir string         `json:"skill_dir"`
+		Manifest skill.Manifest `json:"manifest"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json %q: %v", buf.String(), err)
+	}
+	if out.SkillDir != dest || out.Manifest.ID != "roadmap-reader" {
+		t.Fatalf("unexpected result %+v", out)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "skill.yaml")); err != nil {
+		t.Fatalf("expected unpacked skill.yaml: %v", err)
+	}
+}
+
 func TestCLIUninstallSkill(t *testing.T) {
 	root := t.TempDir()
 	t.Setenv("AIOS_PROJECT_DIR", root)
@@ -301,3 +483,239 @@ func TestCLILintSkillJSON(t *testing.T) {
 		t.Fatalf("expected valid=true, got: %#v", out)
 	}
 }
+
+func TestCLISyncInstallsSupportingFiles(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+	for _, rel := range []string{"scripts/run.sh", "references/api.md", "tests/fixture_01.json"} {
+		p := filepath.Join(skillDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, cfg)
+	if err := cli.Run(context.Background(), "sync-plan", skillDir, "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("sync-plan failed: %v", err)
+	}
+	var plan struct {
+		Files []string `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &plan); err != nil {
+		t.Fatalf("decode plan: %v (%s)", err, buf.String())
+	}
+	canonicalDir := filepath.Join(root, ".agents", "skills", "lock-reader")
+	var inSkill int
+	for _, f := range plan.Files {
+		if strings.HasPrefix(f, canonicalDir+string(filepath.Separator)) {
+			inSkill++
+		}
+	}
+	if inSkill != 3 {
+		t.Fatalf("expected SKILL.md plus two supporting files in plan, got %v", plan.Files)
+	}
+
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	for _, rel := range []string{"SKILL.md", "scripts/run.sh", "references/api.md"} {
+		if _, err := os.Stat(filepath.Join(canonicalDir, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("expected %s to be installed: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"skill.yaml", "prompt.md", "schema.input.json", "tests"} {
+		if _, err := os.Stat(filepath.Join(canonicalDir, rel)); !os.IsNotExist(err) {
+			t.Errorf("expected authoring file %s to stay out of the install", rel)
+		}
+	}
+}
+
+func TestCLISyncRendersAgentRules(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	t.Setenv("AIOS_WORKSPACE_DIR", filepath.Join(root, ".aios"))
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+
+	cli := DefaultCLI(&bytes.Buffer{}, cfg)
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".cursor", "rules", "lock-reader.mdc"))
+	if err != nil {
+		t.Fatalf("expected cursor rule: %v", err)
+	}
+	if !strings.Contains(string(data), "Read the roadmap.") {
+		t.Errorf("expected prompt in cursor rule, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".clinerules", "lock-reader.md")); err != nil {
+		t.Errorf("expected cline rule: %v", err)
+	}
+
+	// A workspace override switches cursor back to SKILL.md only.
+	if err := os.MkdirAll(filepath.Join(root, ".aios"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".aios", "agents.json"), []byte(`[{"name":"cursor","renderer":"skill-md"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".cursor", "rules", "lock-reader.mdc")); !os.IsNotExist(err) {
+		t.Error("expected cursor rule to be removed after switching renderer")
+	}
+}
+
+func TestCLISyncHonorsClientsAndAgentsFlag(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+	spec := filepath.Join(skillDir, "skill.yaml")
+	data, err := os.ReadFile(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, append(data, []byte("clients: [cursor]\n")...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := DefaultCLI(&bytes.Buffer{}, cfg)
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, ".cursor", "skills", "lock-reader")); err != nil {
+		t.Errorf("expected cursor entry: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, ".claude", "skills", "lock-reader")); !os.IsNotExist(err) {
+		t.Error("expected no claude-code entry for a cursor-only skill")
+	}
+
+	buf := &bytes.Buffer{}
+	cli = DefaultCLI(buf, cfg)
+	cli.Flags.Agents = []string{"claude-code"}
+	if err := cli.Run(context.Background(), "sync-plan", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync-plan failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "agents: claude-code\n") || strings.Contains(buf.String(), ".cursor") {
+		t.Errorf("expected plan narrowed to claude-code, got:\n%s", buf.String())
+	}
+
+	cli.Flags.Agents = []string{"no-such-agent"}
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err == nil {
+		t.Fatal("expected unknown agent to fail")
+	}
+}
+
+func TestCLISyncAllProjects(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	t.Setenv("AIOS_WORKSPACE_DIR", filepath.Join(root, "workspace"))
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+
+	api := filepath.Join(root, "api")
+	web := filepath.Join(root, "web")
+	blocked := filepath.Join(root, "blocked")
+	if err := os.WriteFile(blocked, []byte("not a directory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cli := DefaultCLI(&bytes.Buffer{}, cfg)
+	for _, p := range []string{api, web, filepath.Join(blocked, "repo")} {
+		if _, err := cli.AddProject(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	cli = DefaultCLI(buf, cfg)
+	cli.Flags.AllProjects = true
+	cli.Flags.Parallel = 2
+	err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text")
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 projects") {
+		t.Fatalf("expected one failed project, got %v", err)
+	}
+	for _, p := range []string{api, web} {
+		if _, err := os.Stat(filepath.Join(p, ".agents", "skills", "lock-reader", "SKILL.md")); err != nil {
+			t.Errorf("expected skill installed into %s: %v", p, err)
+		}
+		if _, err := os.Stat(filepath.Join(p, ".agents", "skills.lock")); err != nil {
+			t.Errorf("expected lockfile in %s: %v", p, err)
+		}
+	}
+	out := buf.String()
+	for _, want := range []string{"✓ " + api, "✗ " + filepath.Join(blocked, "repo"), "PROJECT", "skill lock-reader: 2 succeeded, 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	cli.Flags.AllProjects = false
+	cli.Flags.Projects = []string{web}
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("sync --project failed: %v", err)
+	}
+	var result struct {
+		Succeeded int `json:"succeeded"`
+		Results   []struct {
+			Project struct {
+				Path string `json:"path"`
+			} `json:"project"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v (%s)", err, buf.String())
+	}
+	if result.Succeeded != 1 || len(result.Results) != 1 || result.Results[0].Project.Path != web {
+		t.Errorf("unexpected result: %s", buf.String())
+	}
+
+	cli.Flags.Global = true
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err == nil {
+		t.Error("expected --global with --project to fail")
+	}
+}
+
+func TestCLISkillsPrune(t *testing.T) {
+	cfg, _ := syncLockTestSkill(t)
+	if err := os.RemoveAll(filepath.Join(cfg.ProjectDir, ".agents", "skills", "lock-reader")); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(cfg.ProjectDir, ".claude", "skills", "lock-reader")
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, cfg)
+	if err := cli.Run(context.Background(), "skills-prune", "", "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "dry run") || !strings.Contains(buf.String(), "dangling-link: "+link) {
+		t.Fatalf("unexpected dry-run output:\n%s", buf.String())
+	}
+	if _, err := os.Lstat(link); err != nil {
+		t.Fatal("dry run must not remove entries")
+	}
+
+	buf.Reset()
+	cli.Flags.Apply = true
+	if err := cli.Run(context.Background(), "skills-prune", "", "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("prune --apply failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "pruned") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Error("expected dangling link removed")
+	}
+}
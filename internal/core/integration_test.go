@@ -13,6 +13,7 @@ import (
 	domainonboarding "github.com/felixgeelhaar/aios/internal/domain/onboarding"
 	domainskilllint "github.com/felixgeelhaar/aios/internal/domain/skilllint"
 	domainskillpackage "github.com/felixgeelhaar/aios/internal/domain/skillpackage"
+	domainskillsync "github.com/felixgeelhaar/aios/internal/domain/skillsync"
 	aosmcp "github.com/felixgeelhaar/aios/internal/mcp"
 	"github.com/felixgeelhaar/aios/internal/runtime"
 	"github.com/felixgeelhaar/aios/internal/sync"
@@ -224,7 +225,7 @@ func TestIntegrationSkillLifecycle(t *testing.T) {
 	})
 
 	t.Run("sync skill", func(t *testing.T) {
-		skillID, err := cli.SyncSkill(ctx, struct{ SkillDir string }{SkillDir: skillDir})
+		skillID, err := cli.SyncSkill(ctx, domainskillsync.SyncSkillCommand{SkillDir: skillDir})
 		if err != nil {
 			t.Fatalf("sync: %v", err)
 		}
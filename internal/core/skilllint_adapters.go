@@ -4,20 +4,54 @@ import (
 	"context"
 
 	domain "github.com/felixgeelhaar/aios/internal/domain/skilllint"
+	"github.com/felixgeelhaar/aios/internal/model"
 	"github.com/felixgeelhaar/aios/internal/skill"
 )
 
-type skillLinterAdapter struct{}
+// skillLinterAdapter lints skills; fixes that record a skill's output run it
+// on provider.
+type skillLinterAdapter struct {
+	provider model.Provider
+}
 
-func (skillLinterAdapter) Lint(_ context.Context, skillDir string) (domain.LintSkillResult, error) {
-	res, err := skill.LintSkillDir(skillDir)
+func (a skillLinterAdapter) Lint(_ context.Context, skillDir string, fix bool) (domain.LintSkillResult, error) {
+	linter := skill.NewLinter()
+	res, err := linter.Lint(skillDir, skill.LintOptions{
+		Fix:      fix,
+		Executor: skill.NewModelExecutor(a.provider, skill.ExecutorOptions{}),
+	})
 	if err != nil {
 		return domain.LintSkillResult{}, err
 	}
-	return domain.LintSkillResult{
-		Valid:  res.Valid,
-		Issues: res.Issues,
-	}, nil
+	out := domain.LintSkillResult{
+		Valid:    res.Valid,
+		Issues:   res.Issues,
+		Findings: lintFindings(res.Findings),
+		Fixed:    lintFindings(res.Fixed),
+	}
+	for _, rule := range linter.Rules() {
+		out.Rules = append(out.Rules, domain.Rule{
+			ID:          rule.ID,
+			Description: rule.Description,
+			Severity:    string(rule.Severity),
+		})
+	}
+	return out, nil
+}
+
+func lintFindings(findings []skill.LintFinding) []domain.Finding {
+	out := make([]domain.Finding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, domain.Finding{
+			RuleID:   f.RuleID,
+			Severity: string(f.Severity),
+			Message:  f.Message,
+			File:     f.File,
+			Line:     f.Line,
+			Fixable:  f.Fixable,
+		})
+	}
+	return out
 }
 
 var _ domain.SkillLinter = skillLinterAdapter{}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	domainskilllint "github.com/felixgeelhaar/aios/internal/domain/skilllint"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifSkillRoot is the base URI id finding locations are relative to.
+	sarifSkillRoot = "SKILLROOT"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// lintSARIF renders a lint result as a SARIF 2.1.0 log for code scanning
+// tools. Finding locations are relative to the skill directory, which the
+// log records as the SKILLROOT base URI.
+func lintSARIF(res domainskilllint.LintSkillResult, skillDir, version string) sarifLog {
+	driver := sarifDriver{
+		Name:           "aios",
+		Version:        version,
+		InformationURI: "https://github.com/felixgeelhaar/aios",
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := map[string]int{}
+	for _, rule := range res.Rules {
+		ruleIndex[rule.ID] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifRuleDefaults{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	if abs, err := filepath.Abs(skillDir); err == nil {
+		root := url.URL{Scheme: "file", Path: filepath.ToSlash(abs) + "/"}
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLoc{sarifSkillRoot: {URI: root.String()}}
+	}
+	for _, f := range res.Findings {
+		index, ok := ruleIndex[f.RuleID]
+		if !ok {
+			index = -1
+		}
+		result := sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: index,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+		}
+		if f.File != "" {
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLoc{URI: f.File, URIBaseID: sarifSkillRoot}}
+			if f.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: f.Line}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+		run.Results = append(run.Results, result)
+	}
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+// sarifLevel maps a lint severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case domainskilllint.SeverityError:
+		return "error"
+	case domainskilllint.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// formatLintFinding renders a finding as "file:line: severity: message
+// [rule]".
+func formatLintFinding(f domainskilllint.Finding) string {
+	var b strings.Builder
+	if f.File != "" {
+		b.WriteString(f.File)
+		if f.Line > 0 {
+			fmt.Fprintf(&b, ":%d", f.Line)
+		}
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "%s: %s [%s]", f.Severity, f.Message, f.RuleID)
+	if f.Fixable {
+		b.WriteString(" (fixable with --fix)")
+	}
+	return b.String()
+}
+
+func nonNilFindings(findings []domainskilllint.Finding) []domainskilllint.Finding {
+	if findings == nil {
+		return []domainskilllint.Finding{}
+	}
+	return findings
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	domainskilllint "github.com/felixgeelhaar/aios/internal/domain/skilllint"
+)
+
+func lintResultWithFindings() domainskilllint.LintSkillResult {
+	return domainskilllint.LintSkillResult{
+		Valid:  false,
+		Issues: []string{"missing expected_01.json"},
+		Findings: []domainskilllint.Finding{
+			{RuleID: "fixture-missing-expected", Severity: "error", Message: "missing expected_01.json", File: "tests/fixture_01.json", Fixable: true},
+			{RuleID: "description-required", Severity: "info", Message: "skill has no description", File: "skill.yaml", Line: 1},
+		},
+		Rules: []domainskilllint.Rule{
+			{ID: "tests-missing", Description: "A skill needs a tests directory.", Severity: "error"},
+			{ID: "fixture-missing-expected", Description: "Every fixture needs an expected file.", Severity: "error"},
+			{ID: "description-required", Description: "skill.yaml should describe the skill.", Severity: "warning"},
+		},
+	}
+}
+
+func TestCLILintSkillSARIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.LintSkill = func(context.Context, domainskilllint.LintSkillCommand) (domainskilllint.LintSkillResult, error) {
+		return lintResultWithFindings(), nil
+	}
+	cli.Flags = CommandFlags{Format: "sarif"}
+	if err := cli.Run(context.Background(), "lint-skill", "./my-skill", "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("lint-skill failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid sarif %q: %v", buf.String(), err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 3 || run.Tool.Driver.Rules[2].DefaultConfiguration.Level != "warning" {
+		t.Fatalf("unexpected rules %+v", run.Tool.Driver.Rules)
+	}
+	if !strings.HasPrefix(run.OriginalURIBaseIDs[sarifSkillRoot].URI, "file:///") || !strings.HasSuffix(run.OriginalURIBaseIDs[sarifSkillRoot].URI, "/my-skill/") {
+		t.Fatalf("unexpected base uri %+v", run.OriginalURIBaseIDs)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("unexpected results %+v", run.Results)
+	}
+	first, second := run.Results[0], run.Results[1]
+	if first.RuleID != "fixture-missing-expected" || first.RuleIndex != 1 || first.Level != "error" || first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "tests/fixture_01.json" || first.Locations[0].PhysicalLocation.Region != nil {
+		t.Fatalf("unexpected first result %+v", first)
+	}
+	if second.Level != "note" || second.Locations[0].PhysicalLocation.Region.StartLine != 1 {
+		t.Fatalf("unexpected second result %+v", second)
+	}
+}
+
+func TestCLILintSkillTextListsFindings(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	var command domainskilllint.LintSkillCommand
+	cli.LintSkill = func(_ context.Context, cmd domainskilllint.LintSkillCommand) (domainskilllint.LintSkillResult, error) {
+		command = cmd
+		return lintResultWithFindings(), nil
+	}
+	cli.Flags = CommandFlags{Fix: true}
+	err := cli.Run(context.Background(), "lint-skill", "./my-skill", "stdio", ":8080", "text")
+	if err == nil || err.Error() != "lint failed: 1 issue(s)" {
+		t.Fatalf("expected lint failure, got %v", err)
+	}
+	if !command.Fix {
+		t.Fatal("expected --fix to reach the linter")
+	}
+	for _, want := range []string{
+		"- tests/fixture_01.json: error: missing expected_01.json [fixture-missing-expected] (fixable with --fix)\n",
+		"- skill.yaml:1: info: skill has no description [description-required]\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+
+	cli.Flags = CommandFlags{Format: "xml"}
+	if err := cli.Run(context.Background(), "lint-skill", "./my-skill", "stdio", ":8080", "text"); err == nil || !strings.Contains(err.Error(), `unknown lint format "xml"`) {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestCLILintSkillFix(t *testing.T) {
+	useFakeModel(t, `{"a":"y"}`)
+	root := t.TempDir()
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+
+	skillDir := filepath.Join(root, "skill")
+	if err := os.MkdirAll(filepath.Join(skillDir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{
+		"skill.yaml":            "id: roadmap-reader\nversion: 0.1\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":             "# prompt",
+		"schema.input.json":     `{"type":"object","properties":{"q":{"type":"string"}}}`,
+		"schema.output.json":    `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		"tests/fixture_01.json": `{"q":"x"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cli.Flags = CommandFlags{Fix: true}
+	if err := cli.Run(context.Background(), "lint-skill", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("lint --fix failed: %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{
+		`fixed skill.yaml:2: error: version "0.1" is not valid semver; use "0.1.0" [version-semver]`,
+		"fixed tests/fixture_01.json: error: missing expected_01.json [fixture-missing-expected]",
+		"✓ lint: ok",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
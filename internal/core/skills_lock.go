@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+// SkillsStatus compares the skills installed in the project against
+// .agents/skills.lock.
+func SkillsStatus(cfg Config) ([]agents.SkillStatus, error) {
+	allAgents, err := agents.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("loading agents: %w", err)
+	}
+	return agents.NewSkillInstaller(allAgents).Status(cfg.ProjectDir)
+}
+
+// InstallFromLockfile reinstalls every skill recorded in the project
+// lockfile from its source directory, for the agents its entry records, and
+// returns the installed skill IDs. When frozen is set, each source must still match the locked version and
+// content hashes and the lockfile is not rewritten; any difference fails the
+// install before that skill is written.
+func InstallFromLockfile(cfg Config, frozen bool) ([]string, error) {
+	lockPath := agents.ProjectLockfilePath(cfg.ProjectDir)
+	lf, err := agents.ReadLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(lf.Skills) == 0 {
+		return nil, fmt.Errorf("no skills recorded in %s\n\nRun 'aios skills sync <skill-dir>' to install skills and create the lockfile", lockPath)
+	}
+	allAgents, err := agents.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("loading agents: %w", err)
+	}
+	si := agents.NewSkillInstaller(allAgents)
+
+	installed := make([]string, 0, len(lf.Skills))
+	for _, locked := range lf.Skills {
+		if locked.Source == "" {
+			return installed, fmt.Errorf("skill %s: lockfile entry has no source", locked.ID)
+		}
+		sourceDir := filepath.FromSlash(locked.Source)
+		if !filepath.IsAbs(sourceDir) {
+			sourceDir = filepath.Join(cfg.ProjectDir, sourceDir)
+		}
+		opts, spec, err := skillInstallOptions(cfg, sourceDir, allAgents, nil, false)
+		if err != nil {
+			return installed, fmt.Errorf("skill %s: %w", locked.ID, err)
+		}
+		if spec.ID != locked.ID {
+			return installed, fmt.Errorf("skill %s: source %s now declares id %s", locked.ID, locked.Source, spec.ID)
+		}
+		opts.Source = locked.Source
+		opts.TargetAgents = lockedAgents(allAgents, locked)
+		if frozen {
+			if spec.Version != locked.Version {
+				return installed, fmt.Errorf("skill %s: source version %s does not match locked version %s", locked.ID, spec.Version, locked.Version)
+			}
+			pinned := locked
+			opts.Locked = &pinned
+		}
+		if _, err := si.InstallSkill(locked.ID, opts); err != nil {
+			return installed, err
+		}
+		installed = append(installed, locked.ID)
+	}
+	return installed, nil
+}
+
+// lockedAgents returns the agent definitions named in a lockfile entry.
+func lockedAgents(all []agentregistry.AgentDefinition, locked agents.LockedSkill) []agentregistry.AgentDefinition {
+	out := make([]agentregistry.AgentDefinition, 0, len(locked.Agents))
+	for _, a := range all {
+		if _, ok := locked.Agents[a.Name]; ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
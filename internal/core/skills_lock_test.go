@@ -0,0 +1,214 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+)
+
+func writeLockTestSkill(t *testing.T, dir, version, prompt string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"skill.yaml":         "id: lock-reader\nversion: " + version + "\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"schema.input.json":  `{"type":"object","properties":{"q":{"type":"string"}}}`,
+		"schema.output.json": `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		"prompt.md":          prompt,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func syncLockTestSkill(t *testing.T) (Config, string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+	cli := DefaultCLI(&bytes.Buffer{}, cfg)
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	return cfg, skillDir
+}
+
+func TestSyncRecordsRelativeSourceInLockfile(t *testing.T) {
+	cfg, _ := syncLockTestSkill(t)
+
+	lf, err := agents.ReadLockfile(agents.ProjectLockfilePath(cfg.ProjectDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := lf.Find("lock-reader")
+	if !ok {
+		t.Fatalf("expected lockfile entry: %#v", lf)
+	}
+	if entry.Source != "skills/lock-reader" || entry.Version != "0.1.0" {
+		t.Fatalf("unexpected entry: %#v", entry)
+	}
+}
+
+func TestInstallFromLockfileFrozenReproducesInstall(t *testing.T) {
+	cfg, _ := syncLockTestSkill(t)
+	canonical := filepath.Join(cfg.ProjectDir, ".agents", "skills", "lock-reader")
+	if err := os.RemoveAll(canonical); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := InstallFromLockfile(cfg, true)
+	if err != nil {
+		t.Fatalf("frozen install: %v", err)
+	}
+	if len(installed) != 1 || installed[0] != "lock-reader" {
+		t.Fatalf("unexpected installed: %v", installed)
+	}
+	statuses, err := SkillsStatus(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].State != agents.StatusOK {
+		t.Fatalf("expected clean status, got %#v", statuses)
+	}
+}
+
+func TestInstallFromLockfileFrozenRejectsChangedSource(t *testing.T) {
+	cfg, skillDir := syncLockTestSkill(t)
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap carefully.\n")
+
+	if _, err := InstallFromLockfile(cfg, true); err == nil || !strings.Contains(err.Error(), "does not match lockfile") {
+		t.Fatalf("expected content mismatch, got %v", err)
+	}
+
+	writeLockTestSkill(t, skillDir, "0.2.0", "Read the roadmap.\n")
+	if _, err := InstallFromLockfile(cfg, true); err == nil || !strings.Contains(err.Error(), "locked version") {
+		t.Fatalf("expected version mismatch, got %v", err)
+	}
+
+	if _, err := InstallFromLockfile(cfg, false); err != nil {
+		t.Fatalf("unfrozen install: %v", err)
+	}
+	lf, _ := agents.ReadLockfile(agents.ProjectLockfilePath(cfg.ProjectDir))
+	if entry, _ := lf.Find("lock-reader"); entry.Version != "0.2.0" {
+		t.Fatalf("expected lockfile refreshed to 0.2.0, got %#v", entry)
+	}
+}
+
+func TestInstallFromLockfileKeepsLockedAgents(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AIOS_PROJECT_DIR", root)
+	cfg := DefaultConfig()
+	skillDir := filepath.Join(root, "skills", "lock-reader")
+	writeLockTestSkill(t, skillDir, "0.1.0", "Read the roadmap.\n")
+	cli := DefaultCLI(&bytes.Buffer{}, cfg)
+	cli.Flags.Agents = []string{"cursor"}
+	if err := cli.Run(context.Background(), "sync", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	for _, frozen := range []bool{false, true} {
+		if _, err := InstallFromLockfile(cfg, frozen); err != nil {
+			t.Fatalf("install (frozen=%v): %v", frozen, err)
+		}
+		if _, err := os.Lstat(filepath.Join(root, ".claude", "skills", "lock-reader")); !os.IsNotExist(err) {
+			t.Fatalf("install (frozen=%v) added claude-code, which the lockfile does not record", frozen)
+		}
+		lf, err := agents.ReadLockfile(agents.ProjectLockfilePath(cfg.ProjectDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry, _ := lf.Find("lock-reader"); len(entry.Agents) != 1 || entry.Agents["cursor"] == "" {
+			t.Fatalf("expected only cursor locked, got %#v", entry.Agents)
+		}
+	}
+}
+
+func TestInstallFromLockfileRequiresEntries(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectDir = t.TempDir()
+	if _, err := InstallFromLockfile(cfg, false); err == nil {
+		t.Fatal("expected error for empty lockfile")
+	}
+}
+
+func TestCLISkillsStatusReportsDrift(t *testing.T) {
+	cfg, _ := syncLockTestSkill(t)
+	skillMd := filepath.Join(cfg.ProjectDir, ".agents", "skills", "lock-reader", "SKILL.md")
+	if err := os.WriteFile(skillMd, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, cfg)
+	if err := cli.Run(context.Background(), "skills-status", "", "stdio", ":8080", "json"); err == nil {
+		t.Fatal("expected drift error")
+	}
+	var out struct {
+		Clean  bool                 `json:"clean"`
+		Skills []agents.SkillStatus `json:"skills"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if out.Clean || len(out.Skills) != 1 || out.Skills[0].State != agents.StatusModified {
+		t.Fatalf("unexpected status: %#v", out)
+	}
+}
+
+func TestCLISkillsStatusTextSortsIssues(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := newStubCLI()
+	cli.Out = buf
+	cli.SkillsStatus = func() ([]agents.SkillStatus, error) {
+		return []agents.SkillStatus{{
+			ID:             "reader",
+			State:          agents.StatusModified,
+			LinkIssues:     map[string]string{"windsurf": "missing link", "claude-code": "not a link", "cursor": "wrong target"},
+			ArtifactIssues: map[string]string{".windsurf/rules/reader.md": "missing", ".cursor/rules/reader.mdc": "modified"},
+		}}, nil
+	}
+	if err := cli.Run(context.Background(), "skills-status", "", "stdio", ":8080", "text"); err == nil {
+		t.Fatal("expected drift error")
+	}
+	want := "reader: modified\n" +
+		"  claude-code: not a link\n" +
+		"  cursor: wrong target\n" +
+		"  windsurf: missing link\n" +
+		"  modified: .cursor/rules/reader.mdc\n" +
+		"  missing: .windsurf/rules/reader.md\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestCLISkillsInstallPassesFrozenFlag(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := newStubCLI()
+	cli.Out = buf
+	cli.Flags = CommandFlags{Frozen: true}
+	var gotFrozen bool
+	cli.InstallLocked = func(frozen bool) ([]string, error) {
+		gotFrozen = frozen
+		return []string{"a"}, nil
+	}
+	if err := cli.Run(context.Background(), "skills-install", "", "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("skills-install failed: %v", err)
+	}
+	if !gotFrozen {
+		t.Fatal("expected frozen install")
+	}
+	if !strings.Contains(buf.String(), "installed 1 skill(s)") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/builder"
+	domainskilllint "github.com/felixgeelhaar/aios/internal/domain/skilllint"
+	domainskilltest "github.com/felixgeelhaar/aios/internal/domain/skilltest"
+)
+
+// newSkill runs "skills new": it takes the skill's type, description,
+// inputs and outputs from the wizard, or from flags when --type is given or
+// there is no terminal, writes the skill, and then lints and tests it.
+func (c CLI) newSkill(ctx context.Context, skillDir, output string) error {
+	spec, err := newSkillSpec(skillDir, c.Flags)
+	if err != nil {
+		return err
+	}
+	if spec.Type == "" {
+		if output == "json" || !isTerminalReader(c.In) || !isTerminalWriter(c.Out) {
+			return fmt.Errorf("--type is required without a terminal (want %s)\n\nExample: aios skills new my-skill --type summarizer", skillTypeNames())
+		}
+		if spec, err = c.RunSkillWizard(ctx, spec); err != nil {
+			return err
+		}
+	}
+	if spec.ID == "" {
+		return fmt.Errorf("skill directory is required\n\nUsage: aios skills new <skill-dir> --type <type>")
+	}
+	if spec, err = spec.Resolve(); err != nil {
+		return err
+	}
+	dir := filepath.Join(spec.Dir, spec.ID)
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s already exists and is not empty", dir)
+	}
+	if err := c.NewSkill(spec); err != nil {
+		return err
+	}
+
+	lint, err := c.LintSkill(ctx, domainskilllint.LintSkillCommand{SkillDir: dir})
+	if err != nil {
+		return err
+	}
+	test, err := c.TestSkill(ctx, domainskilltest.TestSkillCommand{SkillDir: dir})
+	if err != nil {
+		return err
+	}
+	passed := len(test.Results) - test.Failed
+
+	if output == "json" {
+		body, err := json.Marshal(map[string]any{
+			"skill_dir": dir,
+			"type":      spec.Type,
+			"lint":      map[string]any{"valid": lint.Valid, "findings": nonNilFindings(lint.Findings)},
+			"test":      map[string]any{"failed": test.Failed, "results": test.Results},
+		})
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+	} else {
+		_, _ = fmt.Fprintf(c.Out, "✓ created %s skill at %s\n", spec.Type, dir)
+		for _, f := range lint.Findings {
+			_, _ = fmt.Fprintf(c.Out, "- %s\n", formatLintFinding(f))
+		}
+		if lint.Valid {
+			_, _ = fmt.Fprintln(c.Out, "✓ lint: ok")
+		}
+		printFixtureResults(c.Out, test.Results, "")
+		if test.Failed == 0 {
+			_, _ = fmt.Fprintf(c.Out, "✓ tests: %d passed\n", passed)
+		} else {
+			_, _ = fmt.Fprintf(c.Out, "- tests: %d of %d failing until the skill returns the example outputs in %s\n", test.Failed, len(test.Results), filepath.Join(dir, "tests"))
+		}
+		_, _ = fmt.Fprintf(c.Out, "\nNext: edit %s and the expected outputs in %s, then run 'aios skills test %s'\n", filepath.Join(dir, "prompt.md"), filepath.Join(dir, "tests"), dir)
+	}
+	if !lint.Valid {
+		return fmt.Errorf("lint failed: %d issue(s)", len(lint.Issues))
+	}
+	return nil
+}
+
+// newSkillSpec builds the spec of "skills new" from its directory argument
+// and flags.
+func newSkillSpec(skillDir string, flags CommandFlags) (builder.Spec, error) {
+	spec := builder.Spec{
+		Version:     "0.1.0",
+		Type:        builder.SkillType(strings.ToLower(strings.TrimSpace(flags.SkillType))),
+		Description: strings.TrimSpace(flags.Description),
+	}
+	if skillDir != "" {
+		spec.ID = filepath.Base(skillDir)
+		spec.Dir = filepath.Dir(skillDir)
+	}
+	if spec.Type != "" {
+		if _, err := builder.TemplateFor(spec.Type); err != nil {
+			return builder.Spec{}, err
+		}
+	} else if len(flags.InputFields) > 0 || len(flags.OutputFields) > 0 || spec.Description != "" {
+		return builder.Spec{}, fmt.Errorf("--description, --input-field and --output-field need --type (want %s)", skillTypeNames())
+	}
+	for _, s := range flags.InputFields {
+		f, err := builder.ParseField(s)
+		if err != nil {
+			return builder.Spec{}, fmt.Errorf("--input-field %q: %w", s, err)
+		}
+		spec.Inputs = append(spec.Inputs, f)
+	}
+	for _, s := range flags.OutputFields {
+		f, err := builder.ParseField(s)
+		if err != nil {
+			return builder.Spec{}, fmt.Errorf("--output-field %q: %w", s, err)
+		}
+		spec.Outputs = append(spec.Outputs, f)
+	}
+	return spec, nil
+}
+
+func skillTypeNames() string {
+	names := []string{}
+	for _, tmpl := range builder.Templates() {
+		names = append(names, string(tmpl.Type))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,211 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/felixgeelhaar/aios/internal/builder"
+	"github.com/felixgeelhaar/aios/internal/skill"
+)
+
+func TestCLISkillsNewFromFlags(t *testing.T) {
+	root := t.TempDir()
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.In = strings.NewReader("")
+	cli.Flags = CommandFlags{
+		SkillType:    "extractor",
+		Description:  "Reads invoices.",
+		InputFields:  []string{"invoice:string:The invoice text", "currency?:string"},
+		OutputFields: []string{"total:number:The invoice total"},
+	}
+	skillDir := filepath.Join(root, "invoice-reader")
+	if err := cli.Run(context.Background(), "skills-new", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("skills new failed: %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"✓ created extractor skill", "✓ lint: ok", "- tests: 1 of 1 failing until the skill returns the example outputs"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+	spec, err := skill.LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.ID != "invoice-reader" || spec.Description != "Reads invoices." {
+		t.Fatalf("unexpected spec %+v", spec)
+	}
+	data, err := os.ReadFile(filepath.Join(skillDir, "schema.output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"total"`) || strings.Contains(string(data), `"values"`) {
+		t.Fatalf("output schema should use the given fields:\n%s", data)
+	}
+
+	err = cli.Run(context.Background(), "skills-new", skillDir, "stdio", ":8080", "text")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected existing directory error, got %v", err)
+	}
+}
+
+func TestCLISkillsNewJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.Flags = CommandFlags{SkillType: "planner"}
+	skillDir := filepath.Join(t.TempDir(), "launch-plan")
+	if err := cli.Run(context.Background(), "skills-new", skillDir, "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("skills new failed: %v", err)
+	}
+	var out struct {
+		SkillDir string `json:"skill_dir"`
+		Type     string `json:"type"`
+		Lint     struct {
+			Valid bool `json:"valid"`
+		} `json:"lint"`
+		Test struct {
+			Failed int `json:"failed"`
+		} `json:"test"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json %q: %v", buf.String(), err)
+	}
+	if out.SkillDir != skillDir || out.Type != "planner" || !out.Lint.Valid || out.Test.Failed != 1 {
+		t.Fatalf("unexpected result %+v", out)
+	}
+}
+
+func TestCLISkillsNewFlagErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "s")
+	tests := []struct {
+		name  string
+		flags CommandFlags
+		want  string
+	}{
+		{"no type without terminal", CommandFlags{}, "--type is required without a terminal"},
+		{"fields without type", CommandFlags{InputFields: []string{"a"}}, "need --type"},
+		{"unknown type", CommandFlags{SkillType: "poet"}, `unknown skill type "poet"`},
+		{"bad field", CommandFlags{SkillType: "planner", OutputFields: []string{"a:date"}}, `--output-field "a:date"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := DefaultCLI(&bytes.Buffer{}, DefaultConfig())
+			cli.In = strings.NewReader("")
+			cli.Flags = tt.flags
+			err := cli.Run(context.Background(), "skills-new", dir, "stdio", ":8080", "text")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("nothing should be written: %v", err)
+	}
+}
+
+// wizardKeys feeds keys to the wizard: named keys such as "enter", or text
+// typed as runes.
+func wizardKeys(m skillWizardModel, keys ...string) skillWizardModel {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(skillWizardModel)
+	}
+	return m
+}
+
+func TestSkillWizardFlow(t *testing.T) {
+	m := newSkillWizard(builder.Spec{Version: "0.1.0"})
+	if m.step != wizardStepDir || !strings.Contains(m.View(), "skill directory") {
+		t.Fatalf("wizard should start by asking for the directory:\n%s", m.View())
+	}
+	m = wizardKeys(m, "enter")
+	if m.message != "skill directory is required" {
+		t.Fatalf("message = %q", m.message)
+	}
+	m = wizardKeys(m, "skills/pr-review", "enter")
+	if m.spec.ID != "pr-review" || m.spec.Dir != "skills" || m.step != wizardStepType {
+		t.Fatalf("unexpected state %+v", m)
+	}
+	if view := m.View(); !strings.Contains(view, "reviewer") || !strings.Contains(view, "step 2 of 6") {
+		t.Fatalf("type step view:\n%s", view)
+	}
+
+	m = wizardKeys(m, "down", "enter")
+	if m.spec.Type != builder.TypeReviewer || m.spec.Description == "" || len(m.spec.Inputs) != 2 {
+		t.Fatalf("reviewer template not applied: %+v", m.spec)
+	}
+	m = wizardKeys(m, "Reviews pull requests", "enter")
+	if m.spec.Description != "Reviews pull requests" || m.step != wizardStepInputs {
+		t.Fatalf("unexpected state %+v", m)
+	}
+
+	// Replace a field, add one, reject a bad one, remove the last one.
+	m = wizardKeys(m, "content:string:The diff", "enter", "repo?:string", "enter", "bad field", "enter")
+	if !strings.Contains(m.message, "field name") {
+		t.Fatalf("message = %q", m.message)
+	}
+	for range len("bad field") + 1 {
+		m = wizardKeys(m, "backspace")
+	}
+	if m.input != "" || len(m.spec.Inputs) != 2 || m.spec.Inputs[0].Description != "The diff" || m.spec.Inputs[1].Name != "criteria" {
+		t.Fatalf("unexpected inputs %+v (input %q)", m.spec.Inputs, m.input)
+	}
+	m = wizardKeys(m, "enter")
+	if m.step != wizardStepOutputs {
+		t.Fatalf("step = %d", m.step)
+	}
+	m = wizardKeys(m, "esc")
+	if m.step != wizardStepInputs {
+		t.Fatalf("esc should go back, step = %d", m.step)
+	}
+	m = wizardKeys(m, "enter", "enter")
+	if m.step != wizardStepConfirm || !strings.Contains(m.View(), "criteria?:array") {
+		t.Fatalf("confirm view:\n%s", m.View())
+	}
+	m = wizardKeys(m, "y")
+	if !m.done || m.cancelled {
+		t.Fatalf("wizard should be done: %+v", m)
+	}
+}
+
+func TestSkillWizardWithDirectoryStartsAtType(t *testing.T) {
+	m := newSkillWizard(builder.Spec{ID: "notes", Dir: t.TempDir(), Version: "0.1.0"})
+	if m.step != wizardStepType {
+		t.Fatalf("step = %d", m.step)
+	}
+	m = wizardKeys(m, "4")
+	if m.spec.Type != builder.TypePlanner || m.step != wizardStepDescription {
+		t.Fatalf("unexpected state %+v", m)
+	}
+	m = wizardKeys(m, "esc", "esc")
+	if !m.cancelled {
+		t.Fatal("esc on the first step should cancel")
+	}
+
+	m = newSkillWizard(builder.Spec{ID: "notes", Dir: t.TempDir(), Version: "0.1.0"})
+	m = wizardKeys(m, "1", "enter")
+	m.spec.Inputs = nil
+	m = wizardKeys(m, "enter")
+	if m.message != "add at least one field" || m.step != wizardStepInputs {
+		t.Fatalf("unexpected state %+v", m)
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	domainskillsync "github.com/felixgeelhaar/aios/internal/domain/skillsync"
+)
+
+// syncProjects runs "sync" against the tracked projects selected by
+// --all-projects or --project, printing each project as it finishes and a
+// summary table at the end. It fails when any project failed.
+func (c CLI) syncProjects(ctx context.Context, skillDir, output string) error {
+	if c.Flags.Global {
+		return fmt.Errorf("--global cannot be combined with --all-projects or --project")
+	}
+	var progress domainskillsync.ProgressFunc
+	if output != "json" {
+		progress = func(r domainskillsync.ProjectSyncResult, done, total int) {
+			width := len(fmt.Sprint(total))
+			if r.OK {
+				_, _ = fmt.Fprintf(c.Out, "[%*d/%d] ✓ %s (%dms)\n", width, done, total, r.Project.Path, r.DurationMS)
+				return
+			}
+			_, _ = fmt.Fprintf(c.Out, "[%*d/%d] ✗ %s: %s\n", width, done, total, r.Project.Path, r.Error)
+		}
+	}
+	result, err := c.SyncProjects(ctx, domainskillsync.SyncProjectsCommand{
+		SkillDir:    skillDir,
+		AllProjects: c.Flags.AllProjects,
+		Projects:    c.Flags.Projects,
+		Force:       c.Flags.Force,
+		Agents:      c.Flags.Agents,
+		Workers:     c.Flags.Parallel,
+	}, progress)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		body, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+	} else {
+		_, _ = fmt.Fprintln(c.Out)
+		tw := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "PROJECT\tSTATUS\tDETAIL")
+		for _, r := range result.Results {
+			status, detail := "ok", fmt.Sprintf("%dms", r.DurationMS)
+			if !r.OK {
+				status, detail = "failed", r.Error
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Project.Path, status, detail)
+		}
+		_ = tw.Flush()
+		_, _ = fmt.Fprintf(c.Out, "skill %s: %d succeeded, %d failed\n", result.SkillID, result.Succeeded, result.Failed)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("sync failed for %d of %d projects", result.Failed, len(result.Results))
+	}
+	return nil
+}
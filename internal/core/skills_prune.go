@@ -0,0 +1,22 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+)
+
+// PruneSkills finds skill entries left behind in the project, or in the
+// user-level directories when global is set, and removes them when apply
+// is set. See agents.SkillInstaller.Prune.
+func PruneSkills(cfg Config, global, apply bool) (*agents.PruneResult, error) {
+	allAgents, err := agents.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("loading agents: %w", err)
+	}
+	return agents.NewSkillInstaller(allAgents).Prune(agents.PruneOptions{
+		ProjectDir: cfg.ProjectDir,
+		Global:     global,
+		Apply:      apply,
+	})
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/skill"
+)
+
+// inferSchemas runs "skills schema infer": one line per schema file with
+// the samples it was inferred from and the diff of any change.
+func (c CLI) inferSchemas(skillDir, output string) error {
+	if skillDir == "" {
+		return fmt.Errorf("skill directory is required\n\nUsage: aios skills schema infer <skill-dir> [--write]")
+	}
+	results, err := c.InferSchemas(skillDir, c.Flags.Write)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		body, err := json.Marshal(map[string]any{"schemas": results})
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+		return nil
+	}
+	stale := 0
+	for _, r := range results {
+		from := fmt.Sprintf("from %d fixture(s)", len(r.Samples))
+		if r.Kind == "output" {
+			from = fmt.Sprintf("from %d expected file(s)", len(r.Samples))
+		}
+		if len(r.Skipped) > 0 {
+			from += fmt.Sprintf(", %d using assertions skipped", len(r.Skipped))
+		}
+		_, _ = fmt.Fprintf(c.Out, "%-9s %s (%s)\n", r.Status, r.Path, from)
+		if r.Diff != "" {
+			for _, line := range strings.Split(strings.TrimSuffix(r.Diff, "\n"), "\n") {
+				_, _ = fmt.Fprintf(c.Out, "    %s\n", line)
+			}
+		}
+		if r.Status == skill.SchemaStale {
+			stale++
+		}
+	}
+	if stale > 0 {
+		_, _ = fmt.Fprintf(c.Out, "\nRun 'aios skills schema infer %s --write' to write %d schema file(s)\n", skillDir, stale)
+	}
+	return nil
+}
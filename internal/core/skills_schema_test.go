@@ -0,0 +1,67 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/builder"
+)
+
+func TestCLISkillsSchemaInfer(t *testing.T) {
+	root := t.TempDir()
+	if err := builder.BuildSkill(builder.Spec{ID: "infer-me", Version: "0.1.0", Dir: root}); err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(root, "infer-me")
+	for name, body := range map[string]string{
+		"fixture_02.json":  `{"query":"other","limit":5}`,
+		"expected_01.json": `{"result":"done","score":1}`,
+		"expected_02.json": `{"result":"other","score":2}`,
+	} {
+		if err := os.WriteFile(filepath.Join(skillDir, "tests", name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	if err := cli.Run(context.Background(), "skills-schema-infer", skillDir, "stdio", ":8080", "text"); err != nil {
+		t.Fatalf("infer failed: %v", err)
+	}
+	for _, want := range []string{"stale     schema.input.json (from 2 fixture(s))", `+    "limit": {`, "--write' to write 2 schema file(s)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	cli.Flags.Write = true
+	if err := cli.Run(context.Background(), "skills-schema-infer", skillDir, "stdio", ":8080", "json"); err != nil {
+		t.Fatalf("infer --write failed: %v", err)
+	}
+	var out struct {
+		Schemas []struct {
+			Path   string `json:"path"`
+			Status string `json:"status"`
+		} `json:"schemas"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid json %q: %v", buf.String(), err)
+	}
+	if len(out.Schemas) != 2 || out.Schemas[0].Status != "updated" || out.Schemas[1].Status != "updated" {
+		t.Fatalf("unexpected result %+v", out)
+	}
+	data, err := os.ReadFile(filepath.Join(skillDir, "schema.input.json"))
+	if err != nil || !strings.Contains(string(data), `"limit"`) {
+		t.Fatalf("input schema not written: %v\n%s", err, data)
+	}
+
+	if err := cli.Run(context.Background(), "skills-schema-infer", "", "stdio", ":8080", "text"); err == nil {
+		t.Fatal("expected an error without a skill directory")
+	}
+}
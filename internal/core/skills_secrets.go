@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+	"github.com/felixgeelhaar/aios/internal/secrets"
+)
+
+// InstalledSecrets is the secret scan of one installed skill.
+type InstalledSecrets struct {
+	SkillID string `json:"skill_id"`
+	Dir     string `json:"dir"`
+	secrets.Report
+}
+
+// ScanSecrets scans a skill directory against its
+// .aios-secrets-baseline.json. When updateBaseline is set, every current
+// finding is written to the baseline first, accepting it.
+func ScanSecrets(skillDir string, updateBaseline bool) (secrets.Report, error) {
+	if _, err := os.Stat(skillDir); err != nil {
+		return secrets.Report{}, fmt.Errorf("skill directory %s: %w", skillDir, err)
+	}
+	report, err := secrets.Scan(skillDir, "")
+	if err != nil || !updateBaseline {
+		return report, err
+	}
+	all := append(append([]secrets.Finding{}, report.Accepted...), report.Findings...)
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].File != all[j].File {
+			return all[i].File < all[j].File
+		}
+		return all[i].Line < all[j].Line
+	})
+	if err := secrets.WriteBaseline(filepath.Join(skillDir, secrets.BaselineFile), all); err != nil {
+		return secrets.Report{}, err
+	}
+	return secrets.Scan(skillDir, "")
+}
+
+// ScanInstalledSecrets scans every skill installed in the project, or in
+// the user-level skills directory when global is set. Installs leave out
+// dot files, so each skill is checked against the baseline of the source
+// directory its lockfile entry records.
+func ScanInstalledSecrets(cfg Config, global bool) ([]InstalledSecrets, error) {
+	root := filepath.Join(cfg.ProjectDir, agentregistry.CanonicalSkillsDir)
+	if global {
+		root = agents.ResolveGlobalCanonicalSkillsDir()
+		if root == "" {
+			return nil, fmt.Errorf("resolving home directory for global skills")
+		}
+	}
+	lf, err := agents.ReadLockfile(filepath.Join(filepath.Dir(root), agents.LockfileName))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return []InstalledSecrets{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+	results := []InstalledSecrets{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		baseline := ""
+		if locked, ok := lf.Find(e.Name()); ok && locked.Source != "" {
+			source := filepath.FromSlash(locked.Source)
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(cfg.ProjectDir, source)
+			}
+			baseline = filepath.Join(source, secrets.BaselineFile)
+		}
+		report, err := secrets.Scan(dir, baseline)
+		if err != nil {
+			return nil, fmt.Errorf("skill %s: %w", e.Name(), err)
+		}
+		results = append(results, InstalledSecrets{SkillID: e.Name(), Dir: dir, Report: report})
+	}
+	return results, nil
+}
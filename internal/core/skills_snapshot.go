@@ -0,0 +1,63 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domainskilltest "github.com/felixgeelhaar/aios/internal/domain/skilltest"
+)
+
+// printSnapshots reports "test --update" and "test --check" results: one
+// line per expected file with the diff of any change, then a summary. It
+// fails when a check found stale snapshots or a fixture could not run.
+func (c CLI) printSnapshots(result domainskilltest.TestSkillResult, output string) error {
+	counts := make(map[string]int)
+	for _, s := range result.Snapshots {
+		counts[s.Status]++
+	}
+	if output == "json" {
+		body, err := json.Marshal(map[string]any{
+			"failed":    result.Failed,
+			"snapshots": result.Snapshots,
+		})
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+	} else {
+		for _, s := range result.Snapshots {
+			if s.Error != "" {
+				_, _ = fmt.Fprintf(c.Out, "%-9s %s (%s)\n", s.Status, s.Name, s.Error)
+				continue
+			}
+			_, _ = fmt.Fprintf(c.Out, "%-9s %s\n", s.Status, s.Expected)
+			if s.Diff != "" {
+				for _, line := range strings.Split(strings.TrimSuffix(s.Diff, "\n"), "\n") {
+					_, _ = fmt.Fprintf(c.Out, "    %s\n", line)
+				}
+			}
+		}
+		var parts []string
+		for _, status := range []string{
+			domainskilltest.SnapshotCreated,
+			domainskilltest.SnapshotUpdated,
+			domainskilltest.SnapshotStale,
+			domainskilltest.SnapshotUnchanged,
+			domainskilltest.SnapshotSkipped,
+			domainskilltest.SnapshotFailed,
+		} {
+			if counts[status] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+			}
+		}
+		_, _ = fmt.Fprintf(c.Out, "snapshots: %s\n", strings.Join(parts, ", "))
+	}
+	if n := counts[domainskilltest.SnapshotStale]; n > 0 {
+		return fmt.Errorf("%d snapshot(s) are stale; run 'aios skills test --update' to refresh them", n)
+	}
+	if n := counts[domainskilltest.SnapshotFailed]; n > 0 {
+		return fmt.Errorf("%d fixture(s) failed", n)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package core
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domainskilltest "github.com/felixgeelhaar/aios/internal/domain/skilltest"
+	"gopkg.in/yaml.v3"
+)
+
+// testReport is one report requested with --report. An empty path writes
+// the report to standard output.
+type testReport struct {
+	format string
+	path   string
+}
+
+// parseTestReports parses --report values of the form "format" or
+// "format=path".
+func parseTestReports(specs []string) ([]testReport, error) {
+	var reports []testReport
+	toStdout := 0
+	for _, spec := range specs {
+		format, path, _ := strings.Cut(strings.TrimSpace(spec), "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format != "junit" && format != "tap" {
+			return nil, fmt.Errorf("unknown report format %q (want junit or tap)", format)
+		}
+		path = strings.TrimSpace(path)
+		if path == "-" {
+			path = ""
+		}
+		if path == "" {
+			toStdout++
+		}
+		reports = append(reports, testReport{format: format, path: path})
+	}
+	if toStdout > 1 {
+		return nil, fmt.Errorf("only one report can be written to standard output")
+	}
+	return reports, nil
+}
+
+func reportsToStdout(reports []testReport) bool {
+	for _, r := range reports {
+		if r.path == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// testSuites returns the per-skill results of a run, wrapping a single
+// skill's results as one suite.
+func testSuites(result domainskilltest.TestSkillResult, skillDir string) []domainskilltest.SkillTestResult {
+	if result.Skills != nil {
+		return result.Skills
+	}
+	return []domainskilltest.SkillTestResult{{
+		SkillDir:   skillDir,
+		Results:    result.Results,
+		Failed:     result.Failed,
+		DurationMS: result.DurationMS,
+	}}
+}
+
+// printTestResult writes the requested reports and prints the run's
+// results, unless a report took over standard output. It fails when any
+// fixture failed.
+func (c CLI) printTestResult(result domainskilltest.TestSkillResult, skillDir, output string, reports []testReport) error {
+	suites := testSuites(result, skillDir)
+	for _, r := range reports {
+		if err := c.writeTestReport(r, suites); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case reportsToStdout(reports):
+	case output == "json" && result.Skills != nil:
+		body, err := json.Marshal(map[string]any{
+			"failed":      result.Failed,
+			"duration_ms": result.DurationMS,
+			"skills":      result.Skills,
+		})
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+	case output == "json":
+		body, err := json.Marshal(map[string]any{
+			"failed":      result.Failed,
+			"duration_ms": result.DurationMS,
+			"results":     result.Results,
+		})
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.Out, string(body))
+	case result.Skills != nil:
+		passed := 0
+		for _, s := range suites {
+			_, _ = fmt.Fprintf(c.Out, "%s (%dms)\n", s.SkillDir, s.DurationMS)
+			if s.Error != "" {
+				_, _ = fmt.Fprintf(c.Out, "  ERROR %s\n", s.Error)
+			}
+			printFixtureResults(c.Out, s.Results, "  ")
+			for _, r := range s.Results {
+				if r.Passed {
+					passed++
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(c.Out, "%d skills: %d passed, %d failed (%dms)\n", len(suites), passed, result.Failed, result.DurationMS)
+	default:
+		printFixtureResults(c.Out, result.Results, "")
+	}
+
+	if result.Failed == 0 {
+		return nil
+	}
+	if result.Skills != nil {
+		failedSkills := 0
+		for _, s := range result.Skills {
+			if s.Failed > 0 {
+				failedSkills++
+			}
+		}
+		return fmt.Errorf("%d failure(s) in %d of %d skills", result.Failed, failedSkills, len(result.Skills))
+	}
+	return fmt.Errorf("%d fixture(s) failed", result.Failed)
+}
+
+// printFixtureResults prints one line per fixture with its timing, followed
+// by any output mismatches.
+func printFixtureResults(w io.Writer, results []domainskilltest.FixtureResult, indent string) {
+	for _, r := range results {
+		state := "PASS"
+		if !r.Passed {
+			state = "FAIL"
+		}
+		if r.Error != "" {
+			_, _ = fmt.Fprintf(w, "%s%s %s %dms (%s)\n", indent, state, r.Name, r.DurationMS, r.Error)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s%s %s %dms\n", indent, state, r.Name, r.DurationMS)
+		}
+		for _, m := range r.Mismatches {
+			_, _ = fmt.Fprintf(w, "%s  %s: %s\n", indent, m.Path, m.Message)
+			if m.Expected != "" {
+				_, _ = fmt.Fprintf(w, "%s    expected: %s\n", indent, m.Expected)
+			}
+			if m.Actual != "" {
+				_, _ = fmt.Fprintf(w, "%s    actual:   %s\n", indent, m.Actual)
+			}
+		}
+	}
+}
+
+func (c CLI) writeTestReport(r testReport, suites []domainskilltest.SkillTestResult) error {
+	write := writeJUnitReport
+	if r.format == "tap" {
+		write = writeTAPReport
+	}
+	if r.path == "" {
+		return write(c.Out, suites)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return err
+	}
+	// #nosec G304 -- report path is chosen by the user on the command line.
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, suites); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// writeJUnitReport writes a JUnit XML report with one testsuite per skill.
+// Output mismatches are failures; fixtures that could not run, and skills
+// whose fixtures could not be loaded, are errors.
+func writeJUnitReport(w io.Writer, suites []domainskilltest.SkillTestResult) error {
+	doc := junitTestSuites{Name: "aios skills test"}
+	var total int64
+	for _, s := range suites {
+		suite := junitTestSuite{Name: s.SkillDir, Time: junitSeconds(s.DurationMS)}
+		classname := filepath.ToSlash(s.SkillDir)
+		if s.Error != "" {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "load",
+				Classname: classname,
+				Time:      junitSeconds(0),
+				Error:     &junitProblem{Message: s.Error},
+			})
+			suite.Errors++
+		}
+		for _, r := range s.Results {
+			tc := junitTestCase{Name: r.Name, Classname: classname, Time: junitSeconds(r.DurationMS)}
+			switch {
+			case r.Passed:
+			case len(r.Mismatches) > 0:
+				tc.Failure = &junitProblem{Message: r.Error, Body: mismatchText(r.Mismatches)}
+				suite.Failures++
+			default:
+				tc.Error = &junitProblem{Message: r.Error}
+				suite.Errors++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Tests = len(suite.Cases)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Errors += suite.Errors
+		total += s.DurationMS
+		doc.Suites = append(doc.Suites, suite)
+	}
+	doc.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func mismatchText(mismatches []domainskilltest.Mismatch) string {
+	var b strings.Builder
+	for _, m := range mismatches {
+		fmt.Fprintf(&b, "%s: %s", m.Path, m.Message)
+		if m.Expected != "" {
+			fmt.Fprintf(&b, "; expected %s", m.Expected)
+		}
+		if m.Actual != "" {
+			fmt.Fprintf(&b, "; actual %s", m.Actual)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// tapDiagnostic is the YAML block TAP version 13 attaches to a failed test.
+type tapDiagnostic struct {
+	Message    string            `yaml:"message"`
+	DurationMS int64             `yaml:"duration_ms"`
+	Mismatches []tapMismatchYAML `yaml:"mismatches,omitempty"`
+}
+
+type tapMismatchYAML struct {
+	Path     string `yaml:"path"`
+	Message  string `yaml:"message"`
+	Expected string `yaml:"expected,omitempty"`
+	Actual   string `yaml:"actual,omitempty"`
+}
+
+// writeTAPReport writes a TAP version 13 report with one test point per
+// fixture, plus one for each skill whose fixtures could not be loaded.
+func writeTAPReport(w io.Writer, suites []domainskilltest.SkillTestResult) error {
+	type point struct {
+		ok   bool
+		name string
+		diag *tapDiagnostic
+		ms   int64
+	}
+	var points []point
+	for _, s := range suites {
+		prefix := filepath.ToSlash(filepath.Join(s.SkillDir, "tests"))
+		if s.Error != "" {
+			points = append(points, point{name: filepath.ToSlash(s.SkillDir), diag: &tapDiagnostic{Message: s.Error}})
+		}
+		for _, r := range s.Results {
+			p := point{ok: r.Passed, name: prefix + "/" + r.Name, ms: r.DurationMS}
+			if !r.Passed {
+				p.diag = &tapDiagnostic{Message: r.Error, DurationMS: r.DurationMS}
+				for _, m := range r.Mismatches {
+					p.diag.Mismatches = append(p.diag.Mismatches, tapMismatchYAML(m))
+				}
+			}
+			points = append(points, p)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", len(points))
+	for i, p := range points {
+		if p.ok {
+			fmt.Fprintf(&b, "ok %d - %s # time=%dms\n", i+1, p.name, p.ms)
+			continue
+		}
+		fmt.Fprintf(&b, "not ok %d - %s\n", i+1, p.name)
+		body, err := yaml.Marshal(p.diag)
+		if err != nil {
+			return err
+		}
+		b.WriteString("  ---\n")
+		for _, line := range strings.Split(strings.TrimSuffix(string(body), "\n"), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
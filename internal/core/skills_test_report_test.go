@@ -0,0 +1,141 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	domainskilltest "github.com/felixgeelhaar/aios/internal/domain/skilltest"
+)
+
+func recursiveTestResult() domainskilltest.TestSkillResult {
+	return domainskilltest.NewRecursiveTestResult([]domainskilltest.SkillTestResult{
+		{SkillDir: "skills/a", DurationMS: 30, Results: []domainskilltest.FixtureResult{
+			{Name: "fixture_01.json", Passed: true, DurationMS: 12},
+			{Name: "fixture_02.json", Passed: false, Error: "output mismatch", DurationMS: 8, Mismatches: []domainskilltest.Mismatch{
+				{Path: "/status", Message: "not equal", Expected: `"ok"`, Actual: `"draft"`},
+			}},
+		}},
+		{SkillDir: "skills/b", DurationMS: 5, Results: []domainskilltest.FixtureResult{
+			{Name: "fixture_01.json", Passed: false, Error: "fixture timed out after 1s", DurationMS: 1000},
+		}},
+		{SkillDir: "skills/broken", Error: "parse skill spec: bad yaml"},
+	})
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := writeJUnitReport(buf, testSuites(recursiveTestResult(), "skills")); err != nil {
+		t.Fatal(err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid junit xml: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 2 || doc.Time != "0.035" || len(doc.Suites) != 3 {
+		t.Fatalf("unexpected totals: %+v", doc)
+	}
+	failing := doc.Suites[0].Cases[1]
+	if failing.Failure == nil || failing.Failure.Message != "output mismatch" || !strings.Contains(failing.Failure.Body, `/status: not equal; expected "ok"; actual "draft"`) {
+		t.Fatalf("unexpected failure: %+v", failing)
+	}
+	if doc.Suites[1].Cases[0].Error == nil || doc.Suites[2].Cases[0].Name != "load" {
+		t.Fatalf("expected errors for timeout and load failure: %+v", doc.Suites)
+	}
+	if doc.Suites[0].Cases[0].Time != "0.012" {
+		t.Fatalf("unexpected testcase time: %+v", doc.Suites[0].Cases[0])
+	}
+}
+
+func TestWriteTAPReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := writeTAPReport(buf, testSuites(recursiveTestResult(), "skills")); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"TAP version 13\n1..4\n",
+		"ok 1 - skills/a/tests/fixture_01.json # time=12ms\n",
+		"not ok 2 - skills/a/tests/fixture_02.json\n  ---\n  message: output mismatch\n  duration_ms: 8\n  mismatches:\n",
+		"        expected: '\"ok\"'\n",
+		"not ok 3 - skills/b/tests/fixture_01.json\n",
+		"not ok 4 - skills/broken\n  ---\n  message: 'parse skill spec: bad yaml'\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in TAP output:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseTestReports(t *testing.T) {
+	reports, err := parseTestReports([]string{"junit=out/report.xml", "TAP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0] != (testReport{format: "junit", path: "out/report.xml"}) || reports[1] != (testReport{format: "tap"}) {
+		t.Fatalf("unexpected reports: %#v", reports)
+	}
+	if _, err := parseTestReports([]string{"html"}); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+	if _, err := parseTestReports([]string{"tap", "junit=-"}); err == nil {
+		t.Fatal("expected error for two stdout reports")
+	}
+}
+
+func TestCLITestSkillRecursiveWithReports(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"alpha", "beta"} {
+		dir := filepath.Join(root, "skills", name)
+		if err := os.MkdirAll(filepath.Join(dir, "tests"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		status := "ok"
+		if name == "beta" {
+			status = "wrong"
+		}
+		files := map[string]string{
+			"skill.yaml":             "id: " + name + "\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+			"schema.input.json":      `{"type":"object","properties":{"query":{"type":"string"}}}`,
+			"schema.output.json":     `{"type":"object","properties":{"status":{"type":"string"}}}`,
+			"tests/fixture_01.json":  `{"query":"x"}`,
+			"tests/expected_01.json": `{"status":"` + status + `"}`,
+		}
+		for file, body := range files {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(body), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	junitPath := filepath.Join(root, "reports", "skills.xml")
+	buf := &bytes.Buffer{}
+	cli := DefaultCLI(buf, DefaultConfig())
+	cli.Flags = CommandFlags{Recursive: true, Parallel: 2, Reports: []string{"junit=" + junitPath, "tap"}}
+
+	err := cli.Run(context.Background(), "test-skill", filepath.Join(root, "skills"), "stdio", ":8080", "text")
+	if err == nil || err.Error() != "1 failure(s) in 1 of 2 skills" {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "TAP version 13\n1..2\n") || !strings.Contains(out, "not ok 2 - "+filepath.ToSlash(filepath.Join(root, "skills", "beta", "tests", "fixture_01.json"))) {
+		t.Fatalf("unexpected TAP output:\n%s", out)
+	}
+	data, err := os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatalf("junit report not written: %v", err)
+	}
+	if !strings.Contains(string(data), `<testsuites name="aios skills test" tests="2" failures="1" errors="0"`) {
+		t.Fatalf("unexpected junit report:\n%s", data)
+	}
+
+	buf.Reset()
+	cli.Flags = CommandFlags{Recursive: true}
+	_ = cli.Run(context.Background(), "test-skill", filepath.Join(root, "skills"), "stdio", ":8080", "text")
+	if !strings.Contains(buf.String(), "  PASS fixture_01.json") || !strings.Contains(buf.String(), "2 skills: 1 passed, 1 failed") {
+		t.Fatalf("unexpected text output:\n%s", buf.String())
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"path/filepath"
 
 	"github.com/felixgeelhaar/aios/internal/agents"
+	applicationprojectinventory "github.com/felixgeelhaar/aios/internal/application/projectinventory"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 	domain "github.com/felixgeelhaar/aios/internal/domain/skillsync"
 	"github.com/felixgeelhaar/aios/internal/skill"
+	"github.com/felixgeelhaar/aios/internal/skillinstall"
 )
 
 type skillSpecResolverAdapter struct{}
@@ -28,20 +31,67 @@ type clientInstallerAdapter struct {
 	cfg Config
 }
 
-func (a clientInstallerAdapter) InstallSkillAcrossClients(_ context.Context, skillID string, skillDir string) error {
+func (a clientInstallerAdapter) InstallSkillAcrossClients(_ context.Context, request domain.InstallRequest) error {
 	allAgents, err := agents.LoadAll()
 	if err != nil {
 		return fmt.Errorf("loading agents: %w", err)
 	}
-	// Compose rich SKILL.md from skill.yaml + prompt.md.
-	skillContent, _ := skill.LoadAndBuildSkillMd(skillDir)
-	si := agents.NewSkillInstaller(allAgents)
-	_, installErr := si.InstallSkill(skillID, agents.InstallOptions{
-		ProjectDir:   a.cfg.ProjectDir,
-		SkillContent: skillContent,
-	})
+	cfg := a.cfg
+	if request.ProjectDir != "" {
+		cfg.ProjectDir = request.ProjectDir
+	}
+	opts, _, err := skillInstallOptions(cfg, request.SkillDir, allAgents, request.Agents, request.Global)
+	if err != nil {
+		return err
+	}
+	opts.Force = request.Force
+	si := agents.NewSkillInstaller(installerAgents(allAgents, request.Global))
+	_, installErr := si.InstallSkill(request.SkillID, opts)
 	return installErr
 }
 
+// projectSyncSourceAdapter exposes the tracked project inventory to
+// fan-out skill sync.
+type projectSyncSourceAdapter struct {
+	inventory applicationprojectinventory.Service
+}
+
+func (a projectSyncSourceAdapter) ListProjects(ctx context.Context) ([]domain.ProjectRef, error) {
+	projects, err := a.inventory.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.ProjectRef, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, domain.ProjectRef{ID: p.ID, Path: p.Path})
+	}
+	return out, nil
+}
+
+func (a projectSyncSourceAdapter) ResolveProject(ctx context.Context, selector string) (domain.ProjectRef, error) {
+	p, err := a.inventory.Inspect(ctx, selector)
+	if err != nil {
+		return domain.ProjectRef{}, fmt.Errorf("project %q: %w", selector, err)
+	}
+	return domain.ProjectRef{ID: p.ID, Path: p.Path}, nil
+}
+
+// skillInstallOptions returns the install options of the skill at
+// skillDir for cfg's project; see skillinstall.Options.
+func skillInstallOptions(cfg Config, skillDir string, defs []agentregistry.AgentDefinition, names []string, global bool) (agents.InstallOptions, skill.SkillSpec, error) {
+	return skillinstall.Options(cfg.ProjectDir, skillDir, defs, names, global, Version)
+}
+
+// installerAgents returns the agents an installer should know about: all
+// of them for project installs, and only those present on this machine for
+// global installs, which never link into missing agents.
+func installerAgents(all []agentregistry.AgentDefinition, global bool) []agentregistry.AgentDefinition {
+	if global {
+		return agents.DetectInstalled(all)
+	}
+	return all
+}
+
 var _ domain.SkillSpecResolver = skillSpecResolverAdapter{}
 var _ domain.ClientInstaller = clientInstallerAdapter{}
+var _ domain.ProjectSource = projectSyncSourceAdapter{}
@@ -2,27 +2,78 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	domain "github.com/felixgeelhaar/aios/internal/domain/skilltest"
+	"github.com/felixgeelhaar/aios/internal/model"
 	"github.com/felixgeelhaar/aios/internal/skill"
 )
 
-type fixtureRunnerAdapter struct{}
+// fixtureRunnerAdapter runs fixtures the way serve-mcp executes skills: on
+// provider when one is configured, on the stub otherwise.
+type fixtureRunnerAdapter struct {
+	provider model.Provider
+}
+
+func (a fixtureRunnerAdapter) executor() *skill.Executor {
+	return skill.NewModelExecutor(a.provider, skill.ExecutorOptions{})
+}
 
-func (fixtureRunnerAdapter) Run(_ context.Context, skillDir string) ([]domain.FixtureResult, error) {
-	results, err := skill.RunFixtureSuite(skillDir)
+func (a fixtureRunnerAdapter) Run(_ context.Context, skillDir string, opts domain.RunOptions) ([]domain.FixtureResult, error) {
+	results, err := skill.RunFixtureSuiteWithOptions(skillDir, a.executor(), skill.FixtureOptions{
+		Parallel: opts.Parallel,
+		Timeout:  opts.Timeout,
+	})
 	if err != nil {
 		return nil, err
 	}
 	out := make([]domain.FixtureResult, 0, len(results))
 	for _, r := range results {
-		out = append(out, domain.FixtureResult{
-			Name:   r.Name,
-			Passed: r.Passed,
-			Error:  r.Error,
+		fr := domain.FixtureResult{
+			Name:       r.Name,
+			Passed:     r.Passed,
+			Error:      r.Error,
+			DurationMS: r.DurationMS,
+		}
+		for _, m := range r.Mismatches {
+			fr.Mismatches = append(fr.Mismatches, domain.Mismatch{
+				Path:     m.Path,
+				Message:  m.Message,
+				Expected: m.Expected,
+				Actual:   m.Actual,
+			})
+		}
+		out = append(out, fr)
+	}
+	return out, nil
+}
+
+func (a fixtureRunnerAdapter) Snapshot(_ context.Context, skillDir string, write bool) ([]domain.SnapshotResult, error) {
+	results, err := skill.SnapshotFixtures(skillDir, a.executor(), write)
+	if errors.Is(err, skill.ErrNoHandler) {
+		return nil, fmt.Errorf("%w; snapshots record the model's output, so set OPENAI_API_KEY or AIOS_MODEL_BASE_URL", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.SnapshotResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, domain.SnapshotResult{
+			Name:     r.Name,
+			Expected: r.Expected,
+			Status:   string(r.Status),
+			Diff:     r.Diff,
+			Error:    r.Error,
 		})
 	}
 	return out, nil
 }
 
+func (fixtureRunnerAdapter) FindSkills(_ context.Context, root string) ([]string, error) {
+	return skill.FindSkillDirs(root)
+}
+
 var _ domain.FixtureRunner = fixtureRunnerAdapter{}
+var _ domain.SnapshotRunner = fixtureRunnerAdapter{}
+var _ domain.SkillFinder = fixtureRunnerAdapter{}
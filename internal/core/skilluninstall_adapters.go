@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/felixgeelhaar/aios/internal/agents"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 	domain "github.com/felixgeelhaar/aios/internal/domain/skilluninstall"
 	"github.com/felixgeelhaar/aios/internal/skill"
 )
@@ -24,13 +25,26 @@ type clientUninstallerAdapter struct {
 	cfg Config
 }
 
-func (a clientUninstallerAdapter) UninstallAcrossClients(_ context.Context, skillID string) error {
+func (a clientUninstallerAdapter) UninstallAcrossClients(_ context.Context, request domain.UninstallRequest) error {
 	allAgents, err := agents.LoadAll()
 	if err != nil {
 		return fmt.Errorf("loading agents: %w", err)
 	}
-	si := agents.NewSkillInstaller(allAgents)
-	return si.UninstallSkill(skillID, a.cfg.ProjectDir)
+	var targets []agentregistry.AgentDefinition
+	if len(request.Agents) > 0 {
+		if targets, err = agentregistry.ResolveByNames(allAgents, request.Agents); err != nil {
+			return err
+		}
+	}
+	// Global uninstall sweeps every agent, not only detected ones, so links
+	// left behind by a since-removed agent are cleaned up too.
+	_, err = agents.NewSkillInstaller(allAgents).Uninstall(request.SkillID, agents.UninstallOptions{
+		ProjectDir:   a.cfg.ProjectDir,
+		Global:       request.Global,
+		Force:        request.Force,
+		TargetAgents: targets,
+	})
+	return err
 }
 
 var _ domain.SkillIDResolver = uninstallSkillIDResolverAdapter{}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/felixgeelhaar/aios/internal/agents"
 	agentregistry "github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+	domainskillsync "github.com/felixgeelhaar/aios/internal/domain/skillsync"
+	domainsyncplan "github.com/felixgeelhaar/aios/internal/domain/syncplan"
 )
 
 func TestBuildSyncPlan(t *testing.T) {
@@ -149,3 +152,50 @@ func TestSyncPlanRejectsInvalidSchema(t *testing.T) {
 		t.Fatalf("error should mention schema type issue: %v", err)
 	}
 }
+
+func TestSyncInstallsOptionalSectionsAsReferences(t *testing.T) {
+	root := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.ProjectDir = root
+
+	skillDir := filepath.Join(root, "skill")
+	files := map[string]string{
+		"skill.yaml":         "id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"schema.input.json":  `{"type":"object","properties":{"q":{"type":"string"}}}`,
+		"schema.output.json": `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		"prompt.md":          "Read the roadmap.\n# @section *dates\nUse ISO dates.\n# @section risks: when asked about risks\nList the top risks.\n",
+	}
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err := syncPlanWriteTargetPlannerAdapter{cfg: cfg}.PlanWriteTargets(context.Background(), domainsyncplan.PlanRequest{SkillID: "roadmap-reader", SkillDir: skillDir})
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	if len(plan.Sections) != 2 || plan.Sections[0].File != "SKILL.md" || plan.Sections[1].File != "references/risks.md" {
+		t.Fatalf("expected the split in the plan, got %#v", plan.Sections)
+	}
+
+	if _, err := DefaultCLI(&strings.Builder{}, cfg).SyncSkill(context.Background(), domainskillsync.SyncSkillCommand{SkillDir: skillDir}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	canonical := filepath.Join(root, agentregistry.CanonicalSkillsDir, "roadmap-reader")
+	skillMd, err := os.ReadFile(filepath.Join(canonical, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(skillMd), "Use ISO dates.") || strings.Contains(string(skillMd), "List the top risks.") ||
+		!strings.Contains(string(skillMd), "[references/risks.md](references/risks.md): when asked about risks") {
+		t.Fatalf("unexpected SKILL.md:\n%s", skillMd)
+	}
+	ref, err := os.ReadFile(filepath.Join(canonical, "references", "risks.md"))
+	if err != nil || string(ref) != "List the top risks.\n" {
+		t.Fatalf("expected risks reference, got %q (%v)", ref, err)
+	}
+}
@@ -27,13 +27,37 @@ type syncPlanWriteTargetPlannerAdapter struct {
 	cfg Config
 }
 
-func (a syncPlanWriteTargetPlannerAdapter) PlanWriteTargets(_ context.Context, skillID string) ([]string, error) {
+func (a syncPlanWriteTargetPlannerAdapter) PlanWriteTargets(_ context.Context, request domain.PlanRequest) (domain.PlannedWrites, error) {
 	allAgents, err := agents.LoadAll()
 	if err != nil {
-		return nil, fmt.Errorf("loading agents: %w", err)
+		return domain.PlannedWrites{}, fmt.Errorf("loading agents: %w", err)
 	}
-	si := agents.NewSkillInstaller(allAgents)
-	return si.PlanWriteTargets(skillID, a.cfg.ProjectDir), nil
+	opts, _, err := skillInstallOptions(a.cfg, request.SkillDir, allAgents, request.Agents, request.Global)
+	if err != nil {
+		return domain.PlannedWrites{}, err
+	}
+	plan, err := agents.NewSkillInstaller(installerAgents(allAgents, request.Global)).PlanInstall(request.SkillID, opts)
+	if err != nil {
+		return domain.PlannedWrites{}, err
+	}
+	return domain.PlannedWrites{
+		Agents:   plan.Agents,
+		Targets:  plan.Targets,
+		Files:    plan.Files,
+		Sections: promptSections(skill.DisclosePrompt(skill.LoadPrompt(request.SkillDir))),
+	}, nil
+}
+
+// promptSections lists where sync puts each section of a disclosed prompt.
+func promptSections(disclosed skill.DisclosedPrompt) []domain.PromptSection {
+	var out []domain.PromptSection
+	for _, trigger := range disclosed.Required {
+		out = append(out, domain.PromptSection{Trigger: trigger, File: "SKILL.md"})
+	}
+	for _, ref := range disclosed.References {
+		out = append(out, domain.PromptSection{Trigger: ref.Trigger, File: ref.Path})
+	}
+	return out
 }
 
 var _ domain.SkillIDResolver = syncPlanSkillResolverAdapter{}
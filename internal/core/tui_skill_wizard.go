@@ -0,0 +1,316 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/felixgeelhaar/aios/internal/builder"
+)
+
+var errWizardCancelled = errors.New("skill wizard cancelled")
+
+type wizardStep int
+
+const (
+	wizardStepDir wizardStep = iota
+	wizardStepType
+	wizardStepDescription
+	wizardStepInputs
+	wizardStepOutputs
+	wizardStepConfirm
+)
+
+var wizardStepTitles = map[wizardStep]string{
+	wizardStepDir:         "Where should the skill live?",
+	wizardStepType:        "What kind of skill is it?",
+	wizardStepDescription: "Describe what it does",
+	wizardStepInputs:      "What does it take as input?",
+	wizardStepOutputs:     "What does it return?",
+	wizardStepConfirm:     "Create this skill?",
+}
+
+// skillWizardModel is the Bubble Tea form of "aios skills new". It walks
+// through the skill's directory, type, description, inputs and outputs,
+// starting from the type's template, and ends with a confirmation.
+type skillWizardModel struct {
+	spec      builder.Spec
+	templates []builder.Template
+	step      wizardStep
+	first     wizardStep
+	cursor    int
+	input     string
+	message   string
+	done      bool
+	cancelled bool
+}
+
+// newSkillWizard starts the wizard from spec; it skips the directory step
+// when spec already names one.
+func newSkillWizard(spec builder.Spec) skillWizardModel {
+	m := skillWizardModel{spec: spec, templates: builder.Templates()}
+	if spec.ID != "" {
+		m.first = wizardStepType
+	}
+	m.step = m.first
+	for i, tmpl := range m.templates {
+		if tmpl.Type == spec.Type {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+func (m skillWizardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m skillWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.Type {
+	case tea.KeyCtrlC:
+		m.cancelled = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		if m.step == m.first {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+		m.step--
+		m.input = ""
+		m.message = ""
+		return m, nil
+	}
+	switch m.step {
+	case wizardStepType:
+		return m.updateType(key)
+	case wizardStepConfirm:
+		return m.updateConfirm(key)
+	}
+	switch key.Type {
+	case tea.KeyEnter:
+		return m.submit()
+	case tea.KeyBackspace:
+		if m.input != "" {
+			runes := []rune(m.input)
+			m.input = string(runes[:len(runes)-1])
+		} else if fields := m.fields(); fields != nil && len(*fields) > 0 {
+			*fields = (*fields)[:len(*fields)-1]
+		}
+	case tea.KeySpace:
+		m.input += " "
+	case tea.KeyRunes:
+		m.input += string(key.Runes)
+	}
+	return m, nil
+}
+
+func (m skillWizardModel) updateType(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.templates)-1 {
+			m.cursor++
+		}
+	case "enter":
+		m.selectType(m.cursor)
+	default:
+		if idx := keyToIndex(key.String()); idx >= 0 && idx < len(m.templates) {
+			m.cursor = idx
+			m.selectType(idx)
+		}
+	}
+	return m, nil
+}
+
+// selectType picks the template at idx. Choosing a different type than
+// before replaces the description and fields with the new template's.
+func (m *skillWizardModel) selectType(idx int) {
+	tmpl := m.templates[idx]
+	if m.spec.Type != tmpl.Type {
+		m.spec.Type = tmpl.Type
+		m.spec.Description = tmpl.Description
+		m.spec.Inputs = append([]builder.Field(nil), tmpl.Inputs...)
+		m.spec.Outputs = append([]builder.Field(nil), tmpl.Outputs...)
+	}
+	m.step = wizardStepDescription
+	m.message = ""
+}
+
+func (m skillWizardModel) updateConfirm(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "enter", "y":
+		m.done = true
+		return m, tea.Quit
+	case "n":
+		m.cancelled = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// submit handles enter on the text steps. On the field steps a non-empty
+// line adds or replaces a field and an empty line moves on.
+func (m skillWizardModel) submit() (tea.Model, tea.Cmd) {
+	input := strings.TrimSpace(m.input)
+	m.message = ""
+	switch m.step {
+	case wizardStepDir:
+		if input == "" {
+			m.message = "skill directory is required"
+			return m, nil
+		}
+		m.spec.ID = filepath.Base(input)
+		m.spec.Dir = filepath.Dir(input)
+	case wizardStepDescription:
+		if input != "" {
+			m.spec.Description = input
+		}
+	case wizardStepInputs, wizardStepOutputs:
+		fields := m.fields()
+		if input != "" {
+			f, err := builder.ParseField(input)
+			if err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
+			*fields = setField(*fields, f)
+			m.input = ""
+			return m, nil
+		}
+		if len(*fields) == 0 {
+			m.message = "add at least one field"
+			return m, nil
+		}
+	}
+	m.input = ""
+	m.step++
+	return m, nil
+}
+
+// fields returns the field list the current step edits, or nil.
+func (m *skillWizardModel) fields() *[]builder.Field {
+	switch m.step {
+	case wizardStepInputs:
+		return &m.spec.Inputs
+	case wizardStepOutputs:
+		return &m.spec.Outputs
+	}
+	return nil
+}
+
+// setField replaces the field named like f, or appends f.
+func setField(fields []builder.Field, f builder.Field) []builder.Field {
+	out := append([]builder.Field(nil), fields...)
+	for i := range out {
+		if out[i].Name == f.Name {
+			out[i] = f
+			return out
+		}
+	}
+	return append(out, f)
+}
+
+func (m skillWizardModel) View() string {
+	var b strings.Builder
+	styleHeader := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	styleSelected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	styleMuted := lipgloss.NewStyle().Faint(true)
+	styleError := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("204"))
+	styleInput := lipgloss.NewStyle().Foreground(lipgloss.Color("86"))
+	styleSubtle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	b.WriteString(styleHeader.Render("AIOS · New Skill"))
+	b.WriteString("\n")
+	b.WriteString(styleSubtle.Render(fmt.Sprintf("step %d of %d", int(m.step)+1, int(wizardStepConfirm)+1)))
+	b.WriteString("\n\n")
+	b.WriteString(styleHeader.Render(wizardStepTitles[m.step]))
+	b.WriteString("\n\n")
+	esc := "esc to go back"
+	if m.step == m.first {
+		esc = "esc to cancel"
+	}
+
+	switch m.step {
+	case wizardStepDir:
+		b.WriteString(styleSubtle.Render("skill directory: "))
+		b.WriteString(styleInput.Render(m.input))
+		b.WriteString("\n\n")
+		b.WriteString(styleMuted.Render("enter to continue, " + esc))
+	case wizardStepType:
+		for i, tmpl := range m.templates {
+			cursor := " "
+			line := fmt.Sprintf("%d) %-11s %s", i+1, tmpl.Type, tmpl.Summary)
+			if i == m.cursor {
+				cursor = ">"
+				line = styleSelected.Render(line)
+			}
+			b.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+		}
+		b.WriteString("\n")
+		b.WriteString(styleMuted.Render("enter to choose, " + esc))
+	case wizardStepDescription:
+		b.WriteString(styleSubtle.Render("current: " + m.spec.Description))
+		b.WriteString("\n")
+		b.WriteString(styleSubtle.Render("description: "))
+		b.WriteString(styleInput.Render(m.input))
+		b.WriteString("\n\n")
+		b.WriteString(styleMuted.Render("enter to keep the current description or use the one typed, " + esc))
+	case wizardStepInputs, wizardStepOutputs:
+		for _, f := range *m.fields() {
+			b.WriteString("  " + f.String() + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(styleSubtle.Render("add field: "))
+		b.WriteString(styleInput.Render(m.input))
+		b.WriteString("\n\n")
+		b.WriteString(styleMuted.Render("name[?]:type:description — type is string, number, integer, boolean or array; ? makes it optional"))
+		b.WriteString("\n")
+		b.WriteString(styleMuted.Render("enter adds a field (same name replaces it), empty enter continues, backspace on an empty line removes the last field, " + esc))
+	case wizardStepConfirm:
+		b.WriteString(fmt.Sprintf("directory:   %s\n", filepath.Join(m.spec.Dir, m.spec.ID)))
+		b.WriteString(fmt.Sprintf("type:        %s\n", m.spec.Type))
+		b.WriteString(fmt.Sprintf("description: %s\n", m.spec.Description))
+		b.WriteString("inputs:\n")
+		for _, f := range m.spec.Inputs {
+			b.WriteString("  " + f.String() + "\n")
+		}
+		b.WriteString("outputs:\n")
+		for _, f := range m.spec.Outputs {
+			b.WriteString("  " + f.String() + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(styleMuted.Render("enter or y to create, n to cancel, " + esc))
+	}
+
+	if m.message != "" {
+		b.WriteString("\n\n")
+		b.WriteString(styleError.Render(m.message))
+	}
+	return b.String()
+}
+
+// RunSkillWizard asks for the parts of a new skill in the terminal,
+// starting from spec, and returns the completed spec.
+func (c CLI) RunSkillWizard(ctx context.Context, spec builder.Spec) (builder.Spec, error) {
+	program := tea.NewProgram(newSkillWizard(spec), tea.WithInput(c.In), tea.WithOutput(c.Out), tea.WithContext(ctx))
+	final, err := program.Run()
+	if err != nil {
+		return builder.Spec{}, err
+	}
+	m, _ := final.(skillWizardModel)
+	if !m.done {
+		return builder.Spec{}, errWizardCancelled
+	}
+	return m.spec, nil
+}
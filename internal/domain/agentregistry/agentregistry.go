@@ -42,11 +42,21 @@ type AgentDefinition struct {
 	// Universal indicates whether this agent reads from the shared
 	// .agents/skills/ directory. Universal agents do not need symlinks.
 	Universal bool
+
+	// Renderer names the format the agent additionally receives each skill
+	// in, such as Cursor .mdc rules. Empty means the agent only reads the
+	// shared SKILL.md directory.
+	Renderer string
 }
 
 // CanonicalSkillsDir is the shared directory used by all universal agents.
 const CanonicalSkillsDir = ".agents/skills"
 
+// GlobalCanonicalSkillsDir is the user-level counterpart of CanonicalSkillsDir
+// (contains ~ for infrastructure to expand). Global installs store the skill
+// here and link it into each agent's GlobalSkillsDir.
+const GlobalCanonicalSkillsDir = "~/.agents/skills"
+
 // Validate checks that the agent definition has all required fields.
 func (a AgentDefinition) Validate() error {
 	if strings.TrimSpace(a.Name) == "" {
@@ -91,6 +101,18 @@ func FilterNonUniversal(agents []AgentDefinition) []AgentDefinition {
 	return result
 }
 
+// NormalizeNames trims agent names, such as those given with --agents, and
+// drops empty entries.
+func NormalizeNames(names []string) []string {
+	var out []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 // ResolveByNames returns agent definitions matching the given names.
 // Returns an error if any name does not match a known agent.
 func ResolveByNames(agents []AgentDefinition, names []string) ([]AgentDefinition, error) {
@@ -246,3 +246,13 @@ func TestCanonicalSkillsDir_Value(t *testing.T) {
 		t.Errorf("expected .agents/skills, got %q", agentregistry.CanonicalSkillsDir)
 	}
 }
+
+func TestNormalizeNames(t *testing.T) {
+	got := agentregistry.NormalizeNames([]string{" cursor ", "", "  ", "claude-code"})
+	if len(got) != 2 || got[0] != "cursor" || got[1] != "claude-code" {
+		t.Fatalf("unexpected names: %q", got)
+	}
+	if got := agentregistry.NormalizeNames(nil); got != nil {
+		t.Fatalf("expected nil, got %q", got)
+	}
+}
@@ -8,21 +8,56 @@ import (
 
 var ErrSkillDirRequired = fmt.Errorf("skill-dir is required")
 
+// Severity values of a Finding. Only errors make a skill fail lint.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
 type LintSkillCommand struct {
 	SkillDir string
+	// Fix repairs the findings the rules know how to fix before reporting.
+	Fix bool
+}
+
+// Finding is one problem a lint rule found. File is relative to the skill
+// directory; File and Line are empty when the rule cannot tell.
+type Finding struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Fixable  bool   `json:"fixable,omitempty"`
+}
+
+// Rule describes a lint rule, with the severity it has unless
+// configuration overrides it.
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
 }
 
 type LintSkillResult struct {
-	Valid  bool
+	Valid bool
+	// Issues holds the messages of the error findings.
 	Issues []string
+	// Findings holds everything the enabled rules found; Fixed the
+	// findings a fix run repaired.
+	Findings []Finding
+	Fixed    []Finding
+	// Rules lists the rules that could report findings.
+	Rules []Rule
 }
 
 type SkillLinter interface {
-	Lint(ctx context.Context, skillDir string) (LintSkillResult, error)
+	Lint(ctx context.Context, skillDir string, fix bool) (LintSkillResult, error)
 }
 
 func (c LintSkillCommand) Normalized() LintSkillCommand {
-	return LintSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir)}
+	return LintSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir), Fix: c.Fix}
 }
 
 // Validate checks that the command has all required fields.
@@ -0,0 +1,96 @@
+package skillsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+)
+
+// DefaultWorkers is how many projects a fan-out sync installs into at once
+// when the command does not say.
+const DefaultWorkers = 4
+
+var ErrProjectsRequired = fmt.Errorf("select projects with --all-projects or --project")
+var ErrNoProjects = fmt.Errorf("no tracked projects to sync; add one with 'aios project add <path>'")
+
+// ProjectRef identifies a tracked project.
+type ProjectRef struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// SyncProjectsCommand syncs one skill into several tracked projects.
+type SyncProjectsCommand struct {
+	SkillDir string
+	// AllProjects selects every tracked project.
+	AllProjects bool
+	// Projects selects tracked projects by id or path.
+	Projects []string
+	Force    bool
+	Agents   []string
+	// Workers bounds how many projects are installed into concurrently.
+	Workers int
+}
+
+// ProjectSyncResult is the outcome of syncing the skill into one project.
+type ProjectSyncResult struct {
+	Project ProjectRef `json:"project"`
+	OK      bool       `json:"ok"`
+	Error   string     `json:"error,omitempty"`
+	// DurationMS is how long the install took, in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// SyncProjectsResult summarizes a fan-out sync. Results follow the order
+// the projects were selected in, whatever order they finished in.
+type SyncProjectsResult struct {
+	SkillID   string              `json:"skill_id"`
+	Results   []ProjectSyncResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// ProjectSource lists and looks up tracked projects.
+type ProjectSource interface {
+	ListProjects(ctx context.Context) ([]ProjectRef, error)
+	ResolveProject(ctx context.Context, selector string) (ProjectRef, error)
+}
+
+// ProgressFunc is called as each project finishes, with the number of
+// projects done so far and the total. It may be called from several
+// goroutines at once.
+type ProgressFunc func(result ProjectSyncResult, done, total int)
+
+func (c SyncProjectsCommand) Normalized() SyncProjectsCommand {
+	var projects []string
+	for _, p := range c.Projects {
+		if p = strings.TrimSpace(p); p != "" {
+			projects = append(projects, p)
+		}
+	}
+	workers := c.Workers
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	return SyncProjectsCommand{
+		SkillDir:    strings.TrimSpace(c.SkillDir),
+		AllProjects: c.AllProjects,
+		Projects:    projects,
+		Force:       c.Force,
+		Agents:      agentregistry.NormalizeNames(c.Agents),
+		Workers:     workers,
+	}
+}
+
+// Validate checks that the command names a skill and selects projects.
+func (c SyncProjectsCommand) Validate() error {
+	if c.SkillDir == "" {
+		return ErrSkillDirRequired
+	}
+	if !c.AllProjects && len(c.Projects) == 0 {
+		return ErrProjectsRequired
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package skillsync_test
+
+import (
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/domain/skillsync"
+)
+
+func TestSyncProjectsCommand_Normalized(t *testing.T) {
+	cmd := skillsync.SyncProjectsCommand{SkillDir: " ./review ", Projects: []string{" api ", ""}}.Normalized()
+	if cmd.SkillDir != "./review" || len(cmd.Projects) != 1 || cmd.Projects[0] != "api" {
+		t.Errorf("unexpected normalized command: %#v", cmd)
+	}
+	if cmd.Workers != skillsync.DefaultWorkers {
+		t.Errorf("expected default workers, got %d", cmd.Workers)
+	}
+}
+
+func TestSyncProjectsCommand_Validate(t *testing.T) {
+	if err := (skillsync.SyncProjectsCommand{AllProjects: true}).Normalized().Validate(); err != skillsync.ErrSkillDirRequired {
+		t.Errorf("expected ErrSkillDirRequired, got %v", err)
+	}
+	if err := (skillsync.SyncProjectsCommand{SkillDir: "x"}).Normalized().Validate(); err != skillsync.ErrProjectsRequired {
+		t.Errorf("expected ErrProjectsRequired, got %v", err)
+	}
+	if err := (skillsync.SyncProjectsCommand{SkillDir: "x", Projects: []string{"api"}}).Normalized().Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -4,12 +4,35 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 )
 
 var ErrSkillDirRequired = fmt.Errorf("skill-dir is required")
 
 type SyncSkillCommand struct {
 	SkillDir string
+	// Global installs into user-level agent skill directories instead of
+	// the project.
+	Global bool
+	// Force replaces existing entries that aios did not create, after
+	// backing them up.
+	Force bool
+	// Agents limits the install to the named agents, overriding the
+	// clients declared in skill.yaml. Empty installs to the declared
+	// clients, or to every agent when none are declared.
+	Agents []string
+}
+
+// InstallRequest describes a resolved skill and where to install it.
+type InstallRequest struct {
+	SkillID  string
+	SkillDir string
+	// ProjectDir overrides the configured project to install into.
+	ProjectDir string
+	Global     bool
+	Force      bool
+	Agents     []string
 }
 
 type SkillSpecResolver interface {
@@ -17,11 +40,11 @@ type SkillSpecResolver interface {
 }
 
 type ClientInstaller interface {
-	InstallSkillAcrossClients(ctx context.Context, skillID string, skillDir string) error
+	InstallSkillAcrossClients(ctx context.Context, request InstallRequest) error
 }
 
 func (c SyncSkillCommand) Normalized() SyncSkillCommand {
-	return SyncSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir)}
+	return SyncSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir), Global: c.Global, Force: c.Force, Agents: agentregistry.NormalizeNames(c.Agents)}
 }
 
 // Validate checks that the command has all required fields.
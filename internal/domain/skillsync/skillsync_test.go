@@ -26,3 +26,10 @@ func TestValidate_ValidSkillDir(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestNormalized_TrimsAgents(t *testing.T) {
+	cmd := skillsync.SyncSkillCommand{SkillDir: "/path/to/skill", Agents: []string{" cursor ", "", "claude-code"}}.Normalized()
+	if len(cmd.Agents) != 2 || cmd.Agents[0] != "cursor" || cmd.Agents[1] != "claude-code" {
+		t.Errorf("unexpected agents %q", cmd.Agents)
+	}
+}
@@ -4,31 +4,127 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 )
 
 var ErrSkillDirRequired = fmt.Errorf("skill-dir is required")
+var ErrUpdateAndCheck = fmt.Errorf("--update and --check cannot be combined")
+var ErrRecursiveSnapshots = fmt.Errorf("--recursive cannot be combined with --update or --check")
+var ErrNoSkillsFound = fmt.Errorf("no skills found")
 
 type TestSkillCommand struct {
 	SkillDir string
+	// Update writes each fixture's output to its expected file instead of
+	// comparing them.
+	Update bool
+	// Check reports expected files that do not match the current output,
+	// without writing them.
+	Check bool
+	// Recursive tests every skill found under SkillDir.
+	Recursive bool
+	// Parallel is how many fixtures of a skill run at once.
+	Parallel int
+	// Timeout bounds each fixture's execution; zero means no limit.
+	Timeout time.Duration
+}
+
+// RunOptions tunes a FixtureRunner run.
+type RunOptions struct {
+	Parallel int
+	Timeout  time.Duration
+}
+
+// SnapshotStatus values, as reported in SnapshotResult.Status.
+const (
+	SnapshotUnchanged = "unchanged"
+	SnapshotCreated   = "created"
+	SnapshotUpdated   = "updated"
+	SnapshotStale     = "stale"
+	SnapshotSkipped   = "skipped"
+	SnapshotFailed    = "error"
+)
+
+// SnapshotResult reports what snapshot mode did with one fixture's expected
+// file. Diff is a line diff from the old file to the current output.
+type SnapshotResult struct {
+	Name     string
+	Expected string
+	Status   string
+	Diff     string
+	Error    string
 }
 
 type FixtureResult struct {
 	Name   string
 	Passed bool
 	Error  string
+	// Mismatches lists where the output differs from the expected file.
+	Mismatches []Mismatch
+	// DurationMS is how long the fixture took to execute, in milliseconds.
+	DurationMS int64
+}
+
+// Mismatch is one difference between a fixture's expected output and the
+// actual output. Path is a JSON pointer or JSONPath selector; Expected and
+// Actual are JSON encodings, and Actual is empty when the value is missing.
+type Mismatch struct {
+	Path     string
+	Message  string
+	Expected string
+	Actual   string
+}
+
+// SkillTestResult is one skill's outcome in a recursive run. Error is set
+// when the skill's fixtures could not run at all, for example because its
+// skill.yaml is invalid.
+type SkillTestResult struct {
+	SkillDir   string
+	Results    []FixtureResult
+	Failed     int
+	Error      string
+	DurationMS int64
 }
 
 type TestSkillResult struct {
 	Results []FixtureResult
-	Failed  int
+	// Skills is set instead of Results in a recursive run.
+	Skills []SkillTestResult
+	// Snapshots is set instead of Results in update and check mode.
+	Snapshots []SnapshotResult
+	// Failed counts failed fixtures, plus skills that could not run in a
+	// recursive run.
+	Failed     int
+	DurationMS int64
 }
 
 type FixtureRunner interface {
-	Run(ctx context.Context, skillDir string) ([]FixtureResult, error)
+	Run(ctx context.Context, skillDir string, opts RunOptions) ([]FixtureResult, error)
+}
+
+// SkillFinder lists the skill directories under a directory tree.
+type SkillFinder interface {
+	FindSkills(ctx context.Context, root string) ([]string, error)
+}
+
+// SnapshotRunner runs fixtures and compares their output with the expected
+// files, rewriting missing or outdated ones when write is set.
+type SnapshotRunner interface {
+	Snapshot(ctx context.Context, skillDir string, write bool) ([]SnapshotResult, error)
 }
 
 func (c TestSkillCommand) Normalized() TestSkillCommand {
-	return TestSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir)}
+	parallel := c.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	return TestSkillCommand{
+		SkillDir:  strings.TrimSpace(c.SkillDir),
+		Update:    c.Update,
+		Check:     c.Check,
+		Recursive: c.Recursive,
+		Parallel:  parallel,
+		Timeout:   max(c.Timeout, 0),
+	}
 }
 
 // Validate checks that the command has all required fields.
@@ -36,6 +132,12 @@ func (c TestSkillCommand) Validate() error {
 	if c.SkillDir == "" {
 		return ErrSkillDirRequired
 	}
+	if c.Update && c.Check {
+		return ErrUpdateAndCheck
+	}
+	if c.Recursive && (c.Update || c.Check) {
+		return ErrRecursiveSnapshots
+	}
 	return nil
 }
 
@@ -53,3 +155,35 @@ func NewTestSkillResult(results []FixtureResult) TestSkillResult {
 		Failed:  failed,
 	}
 }
+
+// NewSnapshotResult constructs a TestSkillResult from snapshot results. Stale
+// snapshots and fixtures that could not run count as failed.
+func NewSnapshotResult(snapshots []SnapshotResult) TestSkillResult {
+	failed := 0
+	for _, s := range snapshots {
+		if s.Status == SnapshotStale || s.Status == SnapshotFailed {
+			failed++
+		}
+	}
+	return TestSkillResult{
+		Snapshots: snapshots,
+		Failed:    failed,
+	}
+}
+
+// NewRecursiveTestResult constructs a TestSkillResult from per-skill
+// results. Each skill's failed count is computed from its fixtures; a skill
+// that could not run counts as one failure.
+func NewRecursiveTestResult(skills []SkillTestResult) TestSkillResult {
+	out := TestSkillResult{Skills: skills}
+	for i := range out.Skills {
+		s := &out.Skills[i]
+		s.Failed = NewTestSkillResult(s.Results).Failed
+		if s.Error != "" {
+			s.Failed++
+		}
+		out.Failed += s.Failed
+		out.DurationMS += s.DurationMS
+	}
+	return out
+}
@@ -4,12 +4,29 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 )
 
 var ErrSkillDirRequired = fmt.Errorf("skill-dir is required")
 
 type UninstallSkillCommand struct {
 	SkillDir string
+	// Global removes the skill from user-level agent skill directories.
+	Global bool
+	// Force removes entries that aios did not create, after backing them up.
+	Force bool
+	// Agents limits the removal to the named agents. Empty removes the
+	// skill from every agent.
+	Agents []string
+}
+
+// UninstallRequest describes a resolved skill and the scope to remove it from.
+type UninstallRequest struct {
+	SkillID string
+	Global  bool
+	Force   bool
+	Agents  []string
 }
 
 type SkillIDResolver interface {
@@ -17,11 +34,11 @@ type SkillIDResolver interface {
 }
 
 type ClientUninstaller interface {
-	UninstallAcrossClients(ctx context.Context, skillID string) error
+	UninstallAcrossClients(ctx context.Context, request UninstallRequest) error
 }
 
 func (c UninstallSkillCommand) Normalized() UninstallSkillCommand {
-	return UninstallSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir)}
+	return UninstallSkillCommand{SkillDir: strings.TrimSpace(c.SkillDir), Global: c.Global, Force: c.Force, Agents: agentregistry.NormalizeNames(c.Agents)}
 }
 
 // Validate checks that the command has all required fields.
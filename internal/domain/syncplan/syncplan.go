@@ -4,17 +4,57 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 )
 
 var ErrSkillDirRequired = fmt.Errorf("skill-dir is required")
 
 type BuildSyncPlanCommand struct {
 	SkillDir string
+	// Global plans writes into user-level agent skill directories.
+	Global bool
+	// Agents limits the plan to the named agents, overriding the clients
+	// declared in skill.yaml.
+	Agents []string
+}
+
+// PlanRequest describes a resolved skill and the install scope to plan for.
+type PlanRequest struct {
+	SkillID  string
+	SkillDir string
+	Global   bool
+	Agents   []string
 }
 
 type BuildSyncPlanResult struct {
-	SkillID string   `json:"skill_id"`
-	Writes  []string `json:"writes"`
+	SkillID string `json:"skill_id"`
+	// Agents lists the names of the agents the skill is installed for.
+	Agents []string `json:"agents"`
+	Writes []string `json:"writes"`
+	// Files lists every file written, canonical skill directory first.
+	Files []string `json:"files"`
+	// Sections shows where each prompt section is installed.
+	Sections []PromptSection `json:"sections,omitempty"`
+}
+
+// PromptSection is where an installed skill keeps one prompt section:
+// inline in SKILL.md when it is required, otherwise in a reference file the
+// agent reads on demand.
+type PromptSection struct {
+	Trigger string `json:"trigger"`
+	// File is relative to the installed skill directory.
+	File string `json:"file"`
+}
+
+// PlannedWrites is what installing a skill would write: the agents it is
+// installed for, the canonical directory and agent entries, each file
+// written and where each prompt section goes.
+type PlannedWrites struct {
+	Agents   []string
+	Targets  []string
+	Files    []string
+	Sections []PromptSection
 }
 
 type SkillIDResolver interface {
@@ -22,11 +62,11 @@ type SkillIDResolver interface {
 }
 
 type WriteTargetPlanner interface {
-	PlanWriteTargets(ctx context.Context, skillID string) ([]string, error)
+	PlanWriteTargets(ctx context.Context, request PlanRequest) (PlannedWrites, error)
 }
 
 func (c BuildSyncPlanCommand) Normalized() BuildSyncPlanCommand {
-	return BuildSyncPlanCommand{SkillDir: strings.TrimSpace(c.SkillDir)}
+	return BuildSyncPlanCommand{SkillDir: strings.TrimSpace(c.SkillDir), Global: c.Global, Agents: agentregistry.NormalizeNames(c.Agents)}
 }
 
 // Validate checks that the command has all required fields.
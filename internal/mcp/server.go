@@ -14,6 +14,7 @@ import (
 	applicationproject "github.com/felixgeelhaar/aios/internal/application/projectinventory"
 	applicationworkspace "github.com/felixgeelhaar/aios/internal/application/workspaceorchestration"
 	"github.com/felixgeelhaar/aios/internal/builder"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
 	"github.com/felixgeelhaar/aios/internal/governance"
 	"github.com/felixgeelhaar/aios/internal/marketplace"
 	"github.com/felixgeelhaar/aios/internal/model"
@@ -21,11 +22,17 @@ import (
 	"github.com/felixgeelhaar/aios/internal/policy"
 	"github.com/felixgeelhaar/aios/internal/registry"
 	"github.com/felixgeelhaar/aios/internal/runtime"
+	"github.com/felixgeelhaar/aios/internal/secrets"
 	"github.com/felixgeelhaar/aios/internal/skill"
+	"github.com/felixgeelhaar/aios/internal/skillinstall"
 	"github.com/felixgeelhaar/aios/internal/sync"
 	mcpg "github.com/felixgeelhaar/mcp-go"
 )
 
+// mcpMaxConcurrentExecutions bounds how many execute_skill handlers run at
+// once; further calls wait for a free slot.
+const mcpMaxConcurrentExecutions = 4
+
 type PolicyInput struct {
 	Text string `json:"text" jsonschema:"required,description=Text to evaluate"`
 }
@@ -34,6 +41,15 @@ type ExecuteSkillInput struct {
 	ID      string         `json:"id" jsonschema:"required,description=Skill ID"`
 	Version string         `json:"version" jsonschema:"required,description=Skill version"`
 	Input   map[string]any `json:"input" jsonschema:"required,description=Skill input payload"`
+	// SkillDir, when set, validates input and output against the skill's
+	// declared schemas and, with a model provider configured, runs the
+	// skill's prompt.md on the routed model.
+	SkillDir   string `json:"skill_dir,omitempty" jsonschema:"description=Skill directory whose schemas the input and output are validated against and whose prompt runs on the routed model"`
+	Budget     string `json:"budget,omitempty" jsonschema:"description=Optional budget hint for model routing such as low; defaults to the skill's model.budget"`
+	PolicyPack string `json:"policy_pack,omitempty" jsonschema:"description=Optional model routing policy pack: cost-first, quality-first or balanced; defaults to the skill's model.policy_pack"`
+	// Triggers selects the optional prompt sections to render; omitted, all
+	// sections are.
+	Triggers []string `json:"triggers,omitempty" jsonschema:"description=Optional prompt section triggers to activate; all sections render when omitted"`
 }
 
 type SyncStateInput struct{}
@@ -49,7 +65,8 @@ type PackageSkillInput struct {
 	Output   string `json:"output,omitempty" jsonschema:"description=Optional output zip path"`
 }
 type UninstallSkillInput struct {
-	SkillDir string `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory"`
+	SkillDir string   `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory"`
+	Agents   []string `json:"agents,omitempty" jsonschema:"description=Optional agent names to remove the skill from; other agents keep it"`
 }
 type TrackProjectInput struct {
 	Path string `json:"path" jsonschema:"required,description=Absolute or relative project path to track"`
@@ -81,13 +98,19 @@ type RuntimeExecutionReportExportInput struct {
 	Output string `json:"output,omitempty" jsonschema:"description=Optional output path for runtime execution report"`
 }
 type SyncSkillInput struct {
-	SkillDir string `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory to sync"`
+	SkillDir string   `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory to sync"`
+	Agents   []string `json:"agents,omitempty" jsonschema:"description=Optional agent names to target instead of the skill's clients"`
 }
 type SyncPlanInput struct {
-	SkillDir string `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory for planning"`
+	SkillDir string   `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory for planning"`
+	Agents   []string `json:"agents,omitempty" jsonschema:"description=Optional agent names to target instead of the skill's clients"`
 }
 type LintSkillInput struct {
 	SkillDir string `json:"skill_dir" jsonschema:"required,description=Absolute or relative path to a skill directory to lint"`
+	Fix      bool   `json:"fix,omitempty" jsonschema:"description=Repair fixable findings, such as missing expected_ files, before reporting"`
+}
+type PruneSkillsInput struct {
+	Apply bool `json:"apply,omitempty" jsonschema:"description=Remove the entries found instead of only listing them"`
 }
 type InitSkillInput struct {
 	SkillDir string `json:"skill_dir" jsonschema:"required,description=Absolute or relative path where to create skill scaffold"`
@@ -99,16 +122,29 @@ type ServerDeps struct {
 	Commit    string
 	BuildDate string
 	Doctor    func() map[string]any
-	Uninstall func(skillDir string) (string, error)
-	SyncSkill func(ctx context.Context, skillDir string) (string, error)
-	SyncPlan  func(ctx context.Context, skillDir string) (map[string]any, error)
-	LintSkill func(ctx context.Context, skillDir string) (map[string]any, error)
+	// Uninstall, SyncSkill and SyncPlan target the agents named in
+	// agentNames, or the skill's clients when empty.
+	Uninstall func(skillDir string, agentNames []string) (string, error)
+	SyncSkill func(ctx context.Context, skillDir string, agentNames []string) (string, error)
+	SyncPlan  func(ctx context.Context, skillDir string, agentNames []string) (map[string]any, error)
+	LintSkill func(ctx context.Context, skillDir string, fix bool) (map[string]any, error)
 	InitSkill func(skillDir string) error
+	// PruneSkills finds orphaned skill entries and removes them when apply
+	// is set.
+	PruneSkills func(apply bool) (map[string]any, error)
+	// Provider, when set, runs execute_skill requests that name a skill_dir
+	// on the model the router selects.
+	Provider model.Provider
 }
 
 func NewServer(version string) *mcpg.Server {
 	mcpWorkspace := mcpWorkspaceDir()
+	var provider model.Provider
+	if p, ok := model.OpenAIProviderFromEnv(); ok {
+		provider = p
+	}
 	return NewServerWithDeps(version, ServerDeps{
+		Provider:  provider,
 		Sync:      sync.NewEngine(),
 		Version:   version,
 		Commit:    "dev",
@@ -116,7 +152,7 @@ func NewServer(version string) *mcpg.Server {
 		Doctor: func() map[string]any {
 			return map[string]any{"overall": true}
 		},
-		Uninstall: func(skillDir string) (string, error) {
+		Uninstall: func(skillDir string, agentNames []string) (string, error) {
 			spec, err := skill.LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 			if err != nil {
 				return "", err
@@ -125,13 +161,21 @@ func NewServer(version string) *mcpg.Server {
 			if loadErr != nil {
 				return "", loadErr
 			}
+			opts := agents.UninstallOptions{ProjectDir: mcpWorkspace}
+			if len(agentNames) > 0 {
+				targets, err := agentregistry.ResolveByNames(allAgents, agentNames)
+				if err != nil {
+					return "", err
+				}
+				opts.TargetAgents = targets
+			}
 			si := agents.NewSkillInstaller(allAgents)
-			if err := si.UninstallSkill(".", spec.ID); err != nil {
+			if _, err := si.Uninstall(spec.ID, opts); err != nil {
 				return "", err
 			}
 			return spec.ID, nil
 		},
-		SyncSkill: func(ctx context.Context, skillDir string) (string, error) {
+		SyncSkill: func(ctx context.Context, skillDir string, agentNames []string) (string, error) {
 			spec, err := skill.LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 			if err != nil {
 				return "", err
@@ -143,14 +187,17 @@ func NewServer(version string) *mcpg.Server {
 			if loadErr != nil {
 				return "", loadErr
 			}
-			si := agents.NewSkillInstaller(allAgents)
-			_, err = si.InstallSkill(spec.ID, agents.InstallOptions{ProjectDir: mcpWorkspace})
+			opts, _, err := skillinstall.Options(mcpWorkspace, skillDir, allAgents, agentNames, false, version)
 			if err != nil {
 				return "", err
 			}
+			si := agents.NewSkillInstaller(allAgents)
+			if _, err := si.InstallSkill(spec.ID, opts); err != nil {
+				return "", err
+			}
 			return spec.ID, nil
 		},
-		SyncPlan: func(ctx context.Context, skillDir string) (map[string]any, error) {
+		SyncPlan: func(ctx context.Context, skillDir string, agentNames []string) (map[string]any, error) {
 			spec, err := skill.LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 			if err != nil {
 				return nil, err
@@ -159,16 +206,50 @@ func NewServer(version string) *mcpg.Server {
 			if loadErr != nil {
 				return nil, loadErr
 			}
+			opts, _, err := skillinstall.Options(mcpWorkspace, skillDir, allAgents, agentNames, false, version)
+			if err != nil {
+				return nil, err
+			}
 			si := agents.NewSkillInstaller(allAgents)
-			writes := si.PlanWriteTargets(spec.ID, mcpWorkspace)
-			return map[string]any{"skill_id": spec.ID, "writes": writes}, nil
+			plan, err := si.PlanInstall(spec.ID, opts)
+			if err != nil {
+				return nil, err
+			}
+			disclosed := skill.DisclosePrompt(skill.LoadPrompt(skillDir))
+			sections := []map[string]string{}
+			for _, trigger := range disclosed.Required {
+				sections = append(sections, map[string]string{"trigger": trigger, "file": "SKILL.md"})
+			}
+			for _, ref := range disclosed.References {
+				sections = append(sections, map[string]string{"trigger": ref.Trigger, "file": ref.Path})
+			}
+			return map[string]any{"skill_id": spec.ID, "agents": plan.Agents, "writes": plan.Targets, "sections": sections}, nil
 		},
-		LintSkill: func(ctx context.Context, skillDir string) (map[string]any, error) {
-			res, err := skill.LintSkillDir(skillDir)
+		PruneSkills: func(apply bool) (map[string]any, error) {
+			allAgents, err := agents.LoadAll()
 			if err != nil {
 				return nil, err
 			}
-			return map[string]any{"valid": res.Valid, "issues": res.Issues}, nil
+			result, err := agents.NewSkillInstaller(allAgents).Prune(agents.PruneOptions{ProjectDir: mcpWorkspace, Apply: apply})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"applied": result.Applied, "actions": result.Actions, "failed": result.Failed()}, nil
+		},
+		LintSkill: func(ctx context.Context, skillDir string, fix bool) (map[string]any, error) {
+			res, err := skill.NewLinter().Lint(skillDir, skill.LintOptions{
+				Fix:      fix,
+				Executor: skill.NewModelExecutor(provider, skill.ExecutorOptions{}),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"valid":    res.Valid,
+				"issues":   res.Issues,
+				"findings": res.Findings,
+				"fixed":    res.Fixed,
+			}, nil
 		},
 		InitSkill: func(skillDir string) error {
 			return builder.BuildSkill(builder.Spec{
@@ -187,7 +268,8 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 	})
 
 	policyEngine := policy.NewEngine()
-	executor := skill.NewExecutor()
+	// Only skills given by skill_dir have a prompt to run on the provider.
+	executor := skill.NewModelExecutor(deps.Provider, skill.ExecutorOptions{MaxConcurrent: mcpMaxConcurrentExecutions})
 	modelRouter := model.NewRouter()
 	runtimeExec := runtime.New(mcpWorkspaceDir(), runtime.NewMemoryTokenStore())
 	projectRepo := mcpProjectInventoryRepository{workspaceDir: mcpWorkspaceDir()}
@@ -206,27 +288,60 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 
 	srv.Tool("execute_skill").
 		Description("Execute a local skill with strict artifact validation").
-		Handler(func(input ExecuteSkillInput) (map[string]any, error) {
-			plan, err := runtimeExec.PrepareExecution(runtime.ExecutionRequest{
-				SkillID: input.ID,
-				Version: input.Version,
-				Input:   input.Input,
-			})
-			if err != nil {
-				return nil, err
-			}
+		Handler(func(ctx context.Context, input ExecuteSkillInput) (map[string]any, error) {
 			artifact := skill.Artifact{
 				ID:           input.ID,
 				Version:      input.Version,
 				InputSchema:  "inline",
 				OutputSchema: "inline",
+				Triggers:     input.Triggers,
+			}
+			route := runtime.ExecutionRequest{
+				SkillID:    input.ID,
+				Version:    input.Version,
+				Input:      input.Input,
+				Budget:     input.Budget,
+				PolicyPack: input.PolicyPack,
+			}
+			if strings.TrimSpace(input.SkillDir) != "" {
+				spec, err := skill.LoadSkillSpec(filepath.Join(input.SkillDir, "skill.yaml"))
+				if err != nil {
+					return nil, err
+				}
+				if spec.ID != input.ID {
+					return nil, fmt.Errorf("skill_dir holds skill %q, not %q", spec.ID, input.ID)
+				}
+				schemas, err := skill.LoadSkillSchemas(input.SkillDir, spec)
+				if err != nil {
+					return nil, err
+				}
+				artifact.InputSchema = spec.Inputs.Schema
+				artifact.OutputSchema = spec.Outputs.Schema
+				artifact.Schemas = schemas
+				artifact.PromptPath = filepath.Join(input.SkillDir, "prompt.md")
+				artifact.Guardrails = spec.Guardrails
+				if artifact.Timeout, err = spec.ExecutionTimeout(); err != nil {
+					return nil, err
+				}
+				if route.PolicyPack == "" {
+					route.PolicyPack = spec.Model.PolicyPack
+				}
+				if route.Budget == "" {
+					route.Budget = spec.Model.Budget
+				}
 			}
-			out, err := executor.Execute(artifact, plan.SanitizedInput)
+			plan, err := runtimeExec.PrepareExecution(route)
 			if err != nil {
 				return nil, err
 			}
-			out["policy_telemetry"] = plan.PolicyTelemetry
+			out, report, err := runtimeExec.Execute(ctx, executor, artifact, plan)
+			if err != nil {
+				return nil, err
+			}
+			out["policy_telemetry"] = report.PolicyTelemetry
 			out["model"] = plan.Model
+			out["execution_outcome"] = report.ExecutionOutcome
+			out["duration_ms"] = report.DurationMS
 			return out, nil
 		})
 
@@ -276,9 +391,10 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 				}
 			}
 			return map[string]any{
-				"total":  len(results),
-				"passed": passed,
-				"failed": len(results) - passed,
+				"total":   len(results),
+				"passed":  passed,
+				"failed":  len(results) - passed,
+				"results": results,
 			}, nil
 		})
 
@@ -356,7 +472,7 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if deps.Uninstall == nil {
 				return nil, fmt.Errorf("uninstall function not configured")
 			}
-			id, err := deps.Uninstall(input.SkillDir)
+			id, err := deps.Uninstall(input.SkillDir, input.Agents)
 			if err != nil {
 				return nil, err
 			}
@@ -372,7 +488,7 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if deps.SyncSkill == nil {
 				return nil, fmt.Errorf("sync function not configured")
 			}
-			id, err := deps.SyncSkill(context.Background(), input.SkillDir)
+			id, err := deps.SyncSkill(context.Background(), input.SkillDir, input.Agents)
 			if err != nil {
 				return nil, err
 			}
@@ -388,7 +504,7 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if deps.SyncPlan == nil {
 				return nil, fmt.Errorf("sync_plan function not configured")
 			}
-			result, err := deps.SyncPlan(context.Background(), input.SkillDir)
+			result, err := deps.SyncPlan(context.Background(), input.SkillDir, input.Agents)
 			if err != nil {
 				return nil, err
 			}
@@ -396,7 +512,7 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 		})
 
 	srv.Tool("lint_skill").
-		Description("Validate skill structure, SKILL.md syntax, and fixture consistency.").
+		Description("Validate skill structure, SKILL.md syntax, and fixture consistency. Returns findings with rule_id, severity, message, file and line; fix repairs the fixable ones first.").
 		Handler(func(input LintSkillInput) (map[string]any, error) {
 			if strings.TrimSpace(input.SkillDir) == "" {
 				return nil, fmt.Errorf("skill_dir is required")
@@ -404,13 +520,22 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if deps.LintSkill == nil {
 				return nil, fmt.Errorf("lint function not configured")
 			}
-			result, err := deps.LintSkill(context.Background(), input.SkillDir)
+			result, err := deps.LintSkill(context.Background(), input.SkillDir, input.Fix)
 			if err != nil {
 				return nil, err
 			}
 			return result, nil
 		})
 
+	srv.Tool("prune_skills").
+		Description("Find dangling skill links, stale copies and unreferenced canonical skills left behind by removed installs. Dry run unless apply is set.").
+		Handler(func(input PruneSkillsInput) (map[string]any, error) {
+			if deps.PruneSkills == nil {
+				return nil, fmt.Errorf("prune function not configured")
+			}
+			return deps.PruneSkills(input.Apply)
+		})
+
 	srv.Tool("skill_init").
 		Description("Create a skill scaffold with standard file structure including SKILL.md, fixtures, and configuration.").
 		Handler(func(input InitSkillInput) (map[string]any, error) {
@@ -528,25 +653,34 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if err != nil {
 				return nil, err
 			}
+			report, err := secrets.Scan(input.SkillDir, "")
+			if err != nil {
+				return nil, err
+			}
+			if err := report.Err(); err != nil {
+				return nil, fmt.Errorf("publish %s: %w", spec.ID, err)
+			}
 			if cloudRegistry == nil {
 				return nil, fmt.Errorf("registry not initialized")
 			}
-			allAgents, loadErr := agents.LoadAll()
-			if loadErr != nil {
-				return nil, loadErr
-			}
-			agentNames := make([]string, len(allAgents))
-			for i, a := range allAgents {
-				agentNames[i] = a.Name
+			clients := spec.Clients
+			if len(clients) == 0 {
+				allAgents, loadErr := agents.LoadAll()
+				if loadErr != nil {
+					return nil, loadErr
+				}
+				for _, a := range allAgents {
+					clients = append(clients, a.Name)
+				}
 			}
 			if err := cloudRegistry.Publish(registry.SkillVersion{
 				ID:                spec.ID,
 				Version:           spec.Version,
-				CompatibleClients: agentNames,
+				CompatibleClients: clients,
 			}); err != nil {
 				return nil, err
 			}
-			return map[string]any{"published": true, "skill_id": spec.ID, "version": spec.Version}, nil
+			return map[string]any{"published": true, "skill_id": spec.ID, "version": spec.Version, "compatible_clients": clients}, nil
 		})
 
 	srv.Tool("marketplace_list").
@@ -640,19 +774,22 @@ func NewServerWithDeps(version string, deps ServerDeps) *mcpg.Server {
 			if strings.TrimSpace(target) == "" {
 				target = filepath.Join(mcpWorkspaceDir(), "state", "runtime-execution-report.json")
 			}
-			plan, err := runtimeExec.PrepareExecution(runtime.ExecutionRequest{
-				SkillID: "runtime-report",
-				Version: "0.1.0",
-				Input:   map[string]any{"query": "status"},
-			})
-			if err != nil {
-				return nil, err
+			report, ok := runtimeExec.LastExecutionReport()
+			if !ok {
+				plan, err := runtimeExec.PrepareExecution(runtime.ExecutionRequest{
+					SkillID: "runtime-report",
+					Version: "0.1.0",
+					Input:   map[string]any{"query": "status"},
+				})
+				if err != nil {
+					return nil, err
+				}
+				report = runtime.BuildExecutionReport(plan, runtime.OutcomeOK)
 			}
-			report := runtime.BuildExecutionReport(plan, "ok")
 			if err := (mcpExecutionReportStore{}).WriteReport(target, report); err != nil {
 				return nil, err
 			}
-			return map[string]any{"path": target, "skill_id": report.SkillID, "model": report.Model}, nil
+			return map[string]any{"path": target, "skill_id": report.SkillID, "model": report.Model, "execution_outcome": report.ExecutionOutcome}, nil
 		})
 
 	srv.Resource("aios://status/health").
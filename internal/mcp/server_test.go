@@ -3,12 +3,16 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	domainproject "github.com/felixgeelhaar/aios/internal/domain/projectinventory"
+	"github.com/felixgeelhaar/aios/internal/model"
 	"github.com/felixgeelhaar/aios/internal/policy"
 	"github.com/felixgeelhaar/aios/internal/sync"
 	mcpg "github.com/felixgeelhaar/mcp-go"
@@ -120,8 +124,8 @@ func TestMCPFilesystemWorkspaceLinksEnsure(t *testing.T) {
 func TestServerRegistersToolsAndResources(t *testing.T) {
 	srv := NewServerWithDeps("0.1.0", ServerDeps{Sync: sync.NewEngine()})
 	tools := srv.Tools()
-	if len(tools) != 27 {
-		t.Fatalf("expected twenty-seven tools, got %d", len(tools))
+	if len(tools) != 28 {
+		t.Fatalf("expected twenty-eight tools, got %d", len(tools))
 	}
 	toolByName := map[string]bool{}
 	for _, tool := range tools {
@@ -146,6 +150,7 @@ func TestServerRegistersToolsAndResources(t *testing.T) {
 		"sync_execute",
 		"sync_plan",
 		"lint_skill",
+		"prune_skills",
 		"skill_init",
 	} {
 		if !toolByName[name] {
@@ -312,6 +317,97 @@ func TestExecuteSkillBlockedByPromptInjectionHook(t *testing.T) {
 	}
 }
 
+func TestExecuteSkillValidatesInputAgainstSkillDirSchema(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"skill.yaml":         "id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"schema.input.json":  `{"type":"object","required":["query"],"properties":{"query":{"type":"string"},"limit":{"type":"integer","maximum":10}}}`,
+		"schema.output.json": `{"type":"object","properties":{"status":{"type":"string"}}}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := NewServerWithDeps("0.1.0", ServerDeps{Sync: sync.NewEngine()})
+	tool, ok := srv.GetTool("execute_skill")
+	if !ok {
+		t.Fatal("missing execute_skill tool")
+	}
+	call := func(input string) error {
+		_, err := tool.Execute(context.Background(), json.RawMessage(`{
+			"id":"roadmap-reader",
+			"version":"0.1.0",
+			"skill_dir":`+strconv.Quote(dir)+`,
+			"input":`+input+`
+		}`))
+		return err
+	}
+	if err := call(`{"query":"show roadmap"}`); err != nil {
+		t.Fatalf("execute_skill failed: %v", err)
+	}
+	err := call(`{"limit":20}`)
+	if err == nil || !strings.Contains(err.Error(), "/limit: must be <= 10") || !strings.Contains(err.Error(), "/query: required property is missing") {
+		t.Fatalf("expected schema violations, got %v", err)
+	}
+}
+
+func TestExecuteSkillRunsPromptOnRoutedModel(t *testing.T) {
+	var models []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		models = append(models, req.Model)
+		if len(req.Messages) == 0 || !strings.Contains(req.Messages[0].Content, "Summarize the q3 roadmap.") {
+			t.Errorf("prompt.md was not rendered: %+v", req.Messages)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"status\":\"summarized\"}"}}]}`))
+	}))
+	defer api.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"skill.yaml":         "id: roadmap-reader\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":          "Summarize the {{ input.query }} roadmap.\n",
+		"schema.input.json":  `{"type":"object","properties":{"query":{"type":"string"}}}`,
+		"schema.output.json": `{"type":"object","required":["status"],"properties":{"status":{"type":"string"}}}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := NewServerWithDeps("0.1.0", ServerDeps{Sync: sync.NewEngine(), Provider: model.NewOpenAIProvider(api.URL, "")})
+	tool, ok := srv.GetTool("execute_skill")
+	if !ok {
+		t.Fatal("missing execute_skill tool")
+	}
+	out, err := tool.Execute(context.Background(), json.RawMessage(`{
+		"id":"roadmap-reader",
+		"version":"0.1.0",
+		"skill_dir":`+strconv.Quote(dir)+`,
+		"policy_pack":"cost-first",
+		"input":{"query":"q3"}
+	}`))
+	if err != nil {
+		t.Fatalf("execute_skill failed: %v", err)
+	}
+	body, ok := out.(map[string]any)
+	if !ok || body["status"] != "summarized" || body["model"] != "gpt-4.1-mini" {
+		t.Fatalf("unexpected output: %#v", out)
+	}
+	if len(models) != 1 || models[0] != "gpt-4.1-mini" {
+		t.Fatalf("expected the routed model to be called once, got %v", models)
+	}
+}
+
 func TestGovernanceAuditExportAndVerifyTools(t *testing.T) {
 	root := t.TempDir()
 	t.Setenv("AIOS_WORKSPACE_DIR", root)
@@ -450,8 +546,8 @@ func TestPanicRecoveryMiddlewareCanBeWired(t *testing.T) {
 
 	// Verify the server is still functional after middleware construction.
 	tools := srv.Tools()
-	if len(tools) != 27 {
-		t.Fatalf("expected 27 tools, got %d", len(tools))
+	if len(tools) != 28 {
+		t.Fatalf("expected 28 tools, got %d", len(tools))
 	}
 }
 
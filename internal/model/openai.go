@@ -0,0 +1,142 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIBaseURL is the API root used when no base URL is configured.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+const (
+	openAIRequestTimeout = 2 * time.Minute
+	// openAIMaxErrorBody caps how much of an error response is quoted back.
+	openAIMaxErrorBody = 512
+)
+
+// OpenAIProvider calls an OpenAI-compatible /chat/completions endpoint, which
+// covers OpenAI itself and local servers such as Ollama, vLLM or LM Studio.
+type OpenAIProvider struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+var _ Provider = (*OpenAIProvider)(nil)
+
+// NewOpenAIProvider returns a provider for the API rooted at baseURL, or
+// DefaultOpenAIBaseURL when it is empty. apiKey may be empty for local
+// servers that do not authenticate.
+func NewOpenAIProvider(baseURL, apiKey string) *OpenAIProvider {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAIProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: openAIRequestTimeout},
+	}
+}
+
+// OpenAIProviderFromEnv builds a provider from AIOS_MODEL_BASE_URL and
+// AIOS_MODEL_API_KEY, falling back to OPENAI_API_KEY for the key. It reports
+// false when neither a base URL nor a key is set.
+func OpenAIProviderFromEnv() (*OpenAIProvider, bool) {
+	baseURL := strings.TrimSpace(os.Getenv("AIOS_MODEL_BASE_URL"))
+	apiKey := strings.TrimSpace(os.Getenv("AIOS_MODEL_API_KEY"))
+	if apiKey == "" {
+		apiKey = strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
+	}
+	if baseURL == "" && apiKey == "" {
+		return nil, false
+	}
+	return NewOpenAIProvider(baseURL, apiKey), true
+}
+
+type openAIChatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []Message           `json:"messages"`
+	ResponseFormat *openAIResponseType `json:"response_format,omitempty"`
+}
+
+type openAIResponseType struct {
+	Type string `json:"type"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete posts req to the chat completions endpoint and returns the first
+// choice.
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if req.Model == "" {
+		return CompletionResponse{}, ErrNoModel
+	}
+	body := openAIChatRequest{Model: req.Model, Messages: req.Messages}
+	if req.JSON {
+		body.ResponseFormat = &openAIResponseType{Type: "json_object"}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("chat completion: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("read chat completion: %w", err)
+	}
+
+	var out openAIChatResponse
+	decodeErr := json.Unmarshal(data, &out)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && out.Error != nil && out.Error.Message != "" {
+			return CompletionResponse{}, fmt.Errorf("chat completion: %s: %s", resp.Status, out.Error.Message)
+		}
+		if len(data) > openAIMaxErrorBody {
+			data = data[:openAIMaxErrorBody]
+		}
+		return CompletionResponse{}, fmt.Errorf("chat completion: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if decodeErr != nil {
+		return CompletionResponse{}, fmt.Errorf("parse chat completion: %w", decodeErr)
+	}
+	if len(out.Choices) == 0 {
+		return CompletionResponse{}, fmt.Errorf("chat completion returned no choices")
+	}
+	answered := out.Model
+	if answered == "" {
+		answered = req.Model
+	}
+	return CompletionResponse{Model: answered, Content: out.Choices[0].Message.Content}, nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIProviderComplete(t *testing.T) {
+	var got openAIChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk-test" {
+			t.Errorf("unexpected authorization %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"model":"gpt-4.1-mini-2025-04-14","choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"}}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(srv.URL+"/v1/", "sk-test")
+	resp, err := p.Complete(context.Background(), CompletionRequest{
+		Model:    "gpt-4.1-mini",
+		Messages: []Message{{Role: RoleSystem, Content: "be brief"}, {Role: RoleUser, Content: "hi"}},
+		JSON:     true,
+	})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if resp.Content != `{"ok":true}` || resp.Model != "gpt-4.1-mini-2025-04-14" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if got.Model != "gpt-4.1-mini" || len(got.Messages) != 2 || got.ResponseFormat == nil || got.ResponseFormat.Type != "json_object" {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+}
+
+func TestOpenAIProviderReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(srv.URL, "")
+	_, err := p.Complete(context.Background(), CompletionRequest{Model: "gpt-4.1"})
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("expected api error, got %v", err)
+	}
+	if _, err := p.Complete(context.Background(), CompletionRequest{}); err != ErrNoModel {
+		t.Fatalf("expected ErrNoModel, got %v", err)
+	}
+}
+
+func TestOpenAIProviderFromEnv(t *testing.T) {
+	t.Setenv("AIOS_MODEL_BASE_URL", "")
+	t.Setenv("AIOS_MODEL_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, ok := OpenAIProviderFromEnv(); ok {
+		t.Fatal("expected no provider without configuration")
+	}
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+	p, ok := OpenAIProviderFromEnv()
+	if !ok || p.APIKey != "sk-env" || p.BaseURL != DefaultOpenAIBaseURL {
+		t.Fatalf("unexpected provider: %+v", p)
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoModel is returned by providers asked to complete without a model.
+var ErrNoModel = errors.New("model is required")
+
+// Message is one turn of a chat completion.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Chat message roles.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// CompletionRequest asks a provider to continue a conversation with Model,
+// normally the one Router.Decide selected.
+type CompletionRequest struct {
+	Model    string
+	Messages []Message
+	// JSON asks the provider to constrain the reply to a JSON object.
+	JSON bool
+}
+
+// CompletionResponse is a provider's reply. Model is the model that
+// answered, as reported by the provider.
+type CompletionResponse struct {
+	Model   string
+	Content string
+}
+
+// Provider completes chat conversations against a hosted or local model.
+type Provider interface {
+	Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+}
@@ -10,6 +10,8 @@ type RuntimeTelemetry struct {
 	Violations []string `json:"violations"`
 	Redactions int      `json:"redactions"`
 	Blocked    bool     `json:"blocked"`
+	// Guardrails lists what the skill's declared guardrails found.
+	Guardrails []GuardrailEvent `json:"guardrails,omitempty"`
 }
 
 func (e *Engine) Evaluate(text string) []string {
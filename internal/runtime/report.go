@@ -1,7 +1,23 @@
 package runtime
 
 import (
+	"errors"
 	"time"
+
+	"github.com/felixgeelhaar/aios/internal/skill"
+)
+
+// Execution outcomes recorded in ExecutionReport.ExecutionOutcome.
+const (
+	OutcomeOK            = "ok"
+	OutcomeTimeout       = "timeout"
+	OutcomeCanceled      = "canceled"
+	OutcomeHandlerFailed = "handler_failed"
+	// OutcomeBlocked means one of the skill's guardrails stopped it.
+	OutcomeBlocked = "blocked"
+	// OutcomeFailed covers failures outside the handler, such as input or
+	// output that violates the skill's schemas.
+	OutcomeFailed = "failed"
 )
 
 type ExecutionReport struct {
@@ -11,6 +27,8 @@ type ExecutionReport struct {
 	Model            string `json:"model"`
 	PolicyTelemetry  any    `json:"policy_telemetry"`
 	ExecutionOutcome string `json:"execution_outcome"`
+	Error            string `json:"error,omitempty"`
+	DurationMS       int64  `json:"duration_ms,omitempty"`
 }
 
 // ExecutionReportStore abstracts persistence of runtime execution reports.
@@ -28,3 +46,32 @@ func BuildExecutionReport(plan ExecutionPlan, outcome string) ExecutionReport {
 		ExecutionOutcome: outcome,
 	}
 }
+
+// BuildExecutionResultReport reports an execution of plan that took
+// duration and ended with err, which may be nil.
+func BuildExecutionResultReport(plan ExecutionPlan, err error, duration time.Duration) ExecutionReport {
+	report := BuildExecutionReport(plan, ExecutionOutcome(err))
+	report.DurationMS = duration.Milliseconds()
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// ExecutionOutcome classifies an error from skill.Executor.ExecuteContext.
+func ExecutionOutcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.As(err, new(*skill.TimeoutError)):
+		return OutcomeTimeout
+	case errors.As(err, new(*skill.CanceledError)):
+		return OutcomeCanceled
+	case errors.As(err, new(*skill.HandlerError)):
+		return OutcomeHandlerFailed
+	case errors.As(err, new(*skill.GuardrailError)):
+		return OutcomeBlocked
+	default:
+		return OutcomeFailed
+	}
+}
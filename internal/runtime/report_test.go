@@ -1,7 +1,14 @@
 package runtime
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/policy"
+	"github.com/felixgeelhaar/aios/internal/skill"
 )
 
 func TestBuildExecutionReport(t *testing.T) {
@@ -23,3 +30,69 @@ func TestBuildExecutionReport(t *testing.T) {
 		t.Fatal("expected non-empty GeneratedAt")
 	}
 }
+
+func TestExecutionOutcomeClassifiesExecutorErrors(t *testing.T) {
+	cases := map[string]error{
+		OutcomeOK:            nil,
+		OutcomeTimeout:       &skill.TimeoutError{SkillID: "s"},
+		OutcomeCanceled:      fmt.Errorf("wrapped: %w", &skill.CanceledError{SkillID: "s"}),
+		OutcomeHandlerFailed: &skill.HandlerError{SkillID: "s", Err: errors.New("boom")},
+		OutcomeBlocked:       &skill.GuardrailError{SkillID: "s"},
+		OutcomeFailed:        errors.New("input: /query: required property is missing"),
+	}
+	for want, err := range cases {
+		if got := ExecutionOutcome(err); got != want {
+			t.Errorf("%v: expected %s, got %s", err, want, got)
+		}
+	}
+}
+
+func TestRuntimeExecuteRecordsReport(t *testing.T) {
+	r := New(t.TempDir(), NewMemoryTokenStore())
+	if _, ok := r.LastExecutionReport(); ok {
+		t.Fatal("expected no report before any execution")
+	}
+	plan, err := r.PrepareExecution(ExecutionRequest{SkillID: "slow-skill", Version: "0.1.0", Input: map[string]any{"query": "x"}, PolicyPack: "cost-first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := skill.NewExecutor()
+	models := make(chan string, 1)
+	exec.RegisterContextHandler("slow-skill", func(ctx context.Context, a skill.Artifact, _ map[string]any) (map[string]any, error) {
+		models <- a.Model
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	artifact := skill.Artifact{ID: "slow-skill", Version: "0.1.0", InputSchema: "inline", OutputSchema: "inline", Timeout: 10 * time.Millisecond}
+
+	_, report, err := r.Execute(context.Background(), exec, artifact, plan)
+	if err == nil || report.ExecutionOutcome != OutcomeTimeout || report.Error != err.Error() {
+		t.Fatalf("expected timeout report, got %+v (%v)", report, err)
+	}
+	if model := <-models; model != "gpt-4.1-mini" || report.Model != model {
+		t.Fatalf("expected routed model to reach the handler, got %q", model)
+	}
+	if last, ok := r.LastExecutionReport(); !ok || last.GeneratedAt != report.GeneratedAt || last.ExecutionOutcome != OutcomeTimeout {
+		t.Fatalf("expected last report to be recorded, got %+v", last)
+	}
+}
+
+func TestRuntimeExecuteRecordsGuardrailsInTelemetry(t *testing.T) {
+	r := New(t.TempDir(), NewMemoryTokenStore())
+	plan, err := r.PrepareExecution(ExecutionRequest{SkillID: "mailer", Version: "0.1.0", Input: map[string]any{"to": "bob@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifact := skill.Artifact{ID: "mailer", Version: "0.1.0", InputSchema: "inline", OutputSchema: "inline", Guardrails: []string{"no-pii"}}
+	_, report, err := r.Execute(context.Background(), skill.NewExecutor(), artifact, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	telemetry, ok := report.PolicyTelemetry.(policy.RuntimeTelemetry)
+	if !ok || telemetry.Redactions != 1 || len(telemetry.Guardrails) != 1 || telemetry.Guardrails[0].Guardrail != "no-pii" {
+		t.Fatalf("expected guardrail telemetry in report, got %#v", report.PolicyTelemetry)
+	}
+	if len(plan.PolicyTelemetry.Guardrails) != 0 {
+		t.Fatal("Execute must not modify the caller's plan")
+	}
+}
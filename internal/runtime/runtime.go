@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/felixgeelhaar/aios/internal/model"
 	"github.com/felixgeelhaar/aios/internal/policy"
+	"github.com/felixgeelhaar/aios/internal/skill"
 	"github.com/felixgeelhaar/fortify/circuitbreaker"
 	"github.com/felixgeelhaar/fortify/retry"
 )
@@ -25,6 +27,9 @@ type Runtime struct {
 
 	connectorCB circuitbreaker.CircuitBreaker[struct{}]
 	writeRetry  retry.Retry[struct{}]
+
+	mu         sync.Mutex
+	lastReport *ExecutionReport
 }
 
 type ExecutionRequest struct {
@@ -120,3 +125,30 @@ func (r *Runtime) PrepareExecution(req ExecutionRequest) (ExecutionPlan, error)
 		PolicyTelemetry: telemetry,
 	}, nil
 }
+
+// Execute runs artifact on exec with plan's sanitized input and model, and
+// records the outcome as the latest execution report. What the skill's
+// guardrails found is added to the report's policy telemetry. The report is
+// returned whether or not the execution failed.
+func (r *Runtime) Execute(ctx context.Context, exec *skill.Executor, artifact skill.Artifact, plan ExecutionPlan) (map[string]any, ExecutionReport, error) {
+	artifact.Model = plan.Model
+	start := time.Now()
+	out, guardrails, err := exec.ExecuteWithTelemetry(ctx, artifact, plan.SanitizedInput)
+	plan.PolicyTelemetry.Violations = append([]string{}, plan.PolicyTelemetry.Violations...)
+	plan.PolicyTelemetry.RecordGuardrails(guardrails.Guardrails)
+	report := BuildExecutionResultReport(plan, err, time.Since(start))
+	r.mu.Lock()
+	r.lastReport = &report
+	r.mu.Unlock()
+	return out, report, err
+}
+
+// LastExecutionReport returns the report of the latest Execute call, if any.
+func (r *Runtime) LastExecutionReport() (ExecutionReport, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastReport == nil {
+		return ExecutionReport{}, false
+	}
+	return *r.lastReport, true
+}
@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BaselineFile records the accepted findings of a skill directory.
+const BaselineFile = ".aios-secrets-baseline.json"
+
+const baselineVersion = 1
+
+// Baseline is the content of a .aios-secrets-baseline.json: findings that
+// were reviewed and accepted, such as test tokens in fixtures.
+type Baseline struct {
+	Version  int       `json:"version"`
+	Accepted []Finding `json:"accepted"`
+}
+
+// Report is the outcome of scanning a directory against its baseline.
+type Report struct {
+	// Findings are the secrets the baseline does not accept.
+	Findings []Finding `json:"findings"`
+	// Accepted are the secrets the baseline accepts.
+	Accepted []Finding `json:"accepted"`
+	// Baseline is the baseline file applied; empty when there was none.
+	Baseline string `json:"baseline,omitempty"`
+}
+
+// LoadBaseline reads a baseline file. A missing file yields an empty
+// baseline.
+func LoadBaseline(path string) (Baseline, error) {
+	// #nosec G304 -- path is a baseline file chosen by the caller.
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Baseline{Version: baselineVersion}, nil
+	}
+	if err != nil {
+		return Baseline{}, fmt.Errorf("read %s: %w", path, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Baseline{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return Baseline{}, fmt.Errorf("%s: unsupported baseline version %d", path, b.Version)
+	}
+	return b, nil
+}
+
+// WriteBaseline writes a baseline accepting findings.
+func WriteBaseline(path string, findings []Finding) error {
+	b := Baseline{Version: baselineVersion, Accepted: findings}
+	if b.Accepted == nil {
+		b.Accepted = []Finding{}
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Split separates the findings the baseline accepts from the others.
+func (b Baseline) Split(findings []Finding) (remaining, accepted []Finding) {
+	known := make(map[string]bool, len(b.Accepted))
+	for _, f := range b.Accepted {
+		known[f.Fingerprint] = true
+	}
+	remaining, accepted = []Finding{}, []Finding{}
+	for _, f := range findings {
+		if known[f.Fingerprint] {
+			accepted = append(accepted, f)
+		} else {
+			remaining = append(remaining, f)
+		}
+	}
+	return remaining, accepted
+}
+
+// Scan scans dir and applies the baseline at baselinePath, or the
+// .aios-secrets-baseline.json in dir when baselinePath is empty.
+func Scan(dir, baselinePath string) (Report, error) {
+	if baselinePath == "" {
+		baselinePath = filepath.Join(dir, BaselineFile)
+	}
+	findings, err := NewScanner().ScanDir(dir)
+	if err != nil {
+		return Report{}, err
+	}
+	baseline, err := LoadBaseline(baselinePath)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{}
+	report.Findings, report.Accepted = baseline.Split(findings)
+	if _, err := os.Stat(baselinePath); err == nil {
+		report.Baseline = baselinePath
+	}
+	return report, nil
+}
+
+// Err reports the findings the baseline does not accept as an error.
+func (r Report) Err() error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		lines = append(lines, f.String())
+	}
+	return fmt.Errorf("%d possible secret(s) found:\n  %s\n\nRemove them, or accept reviewed findings with 'aios skills scan <skill-dir> --update-baseline'", len(r.Findings), strings.Join(lines, "\n  "))
+}
@@ -0,0 +1,253 @@
+// Package secrets finds credentials embedded in skill files. It combines
+// provider-specific detectors with a Shannon-entropy check on string
+// literals, and lets a baseline file accept known findings.
+package secrets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// EntropyDetector names findings of the entropy check.
+const EntropyDetector = "high-entropy-string"
+
+const (
+	// maxScanFileSize bounds the files ScanDir reads; larger files are
+	// skipped.
+	maxScanFileSize = 1 << 20
+	// minLiteralLength and minHexLiteralLength are the shortest tokens the
+	// entropy check considers.
+	minLiteralLength    = 20
+	minHexLiteralLength = 32
+	// base64Entropy and hexEntropy are the Shannon entropies, in bits per
+	// character, above which a token is reported.
+	base64Entropy = 4.2
+	hexEntropy    = 3.0
+	// maxExcerptLength bounds Finding.Excerpt, in characters.
+	maxExcerptLength = 160
+)
+
+var (
+	// literalRe matches quoted strings and the values of key: value and
+	// key=value assignments.
+	literalRe = regexp.MustCompile(`"([^"\n]*)"|'([^'\n]*)'|[:=][ \t]*([^\s"',;]+)`)
+	// tokenRe matches the runs of a literal that could be an encoded secret.
+	tokenRe = regexp.MustCompile(`[A-Za-z0-9+/_\-]+={0,2}`)
+	hexRe   = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+// Detector recognizes one kind of secret.
+type Detector struct {
+	Name string
+	// Pattern matches the secret in a line.
+	Pattern *regexp.Regexp
+	// Group is the submatch holding the secret; zero uses the whole match.
+	Group int
+}
+
+// Detectors returns the built-in detectors, most specific first.
+func Detectors() []Detector {
+	return []Detector{
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+		{Name: "aws-access-key-id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+		{Name: "aws-secret-access-key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`), Group: 1},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+		{Name: "slack-webhook", Pattern: regexp.MustCompile(`https://hooks\.slack\.com/services/[A-Za-z0-9_/]+`)},
+		{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{20,}`)},
+		{Name: "anthropic-api-key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+		{Name: "openai-api-key", Pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9]{20,}`)},
+		{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[A-Za-z0-9\-_]{30,}`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+		{Name: "bearer-token", Pattern: regexp.MustCompile(`(?i)\bBearer\s+([A-Za-z0-9\-._~+/]+=*)`), Group: 1},
+		{Name: "credential-assignment", Pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret[_-]?key|client[_-]?secret|access[_-]?token|private[_-]?key|password)\s*[:=]\s*(\S+)`), Group: 1},
+	}
+}
+
+// Finding is one suspected secret.
+type Finding struct {
+	Detector string `json:"detector"`
+	// File is slash-separated and relative to the scanned directory.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Excerpt is the line with the secret redacted.
+	Excerpt string `json:"excerpt"`
+	// Fingerprint identifies the secret in a baseline without revealing
+	// it. It depends on the detector and the secret only, so moving the
+	// secret, or installing the skill, keeps it.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// String renders a finding as "file:line: detector: excerpt".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Detector, f.Excerpt)
+}
+
+// Scanner finds secrets in text and files.
+type Scanner struct {
+	Detectors []Detector
+	// Entropy enables the entropy check on string literals.
+	Entropy bool
+}
+
+// NewScanner returns a Scanner with the built-in detectors and the entropy
+// check enabled.
+func NewScanner() *Scanner {
+	return &Scanner{Detectors: Detectors(), Entropy: true}
+}
+
+// ScanText returns the secrets in text, attributing them to file.
+func (s *Scanner) ScanText(file, text string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(text, "\n") {
+		var spans [][2]int
+		add := func(detector string, start, end int) {
+			for _, sp := range spans {
+				if start < sp[1] && sp[0] < end {
+					return
+				}
+			}
+			spans = append(spans, [2]int{start, end})
+			secret := line[start:end]
+			findings = append(findings, Finding{
+				Detector:    detector,
+				File:        file,
+				Line:        i + 1,
+				Excerpt:     excerpt(line, start, end),
+				Fingerprint: Fingerprint(detector, secret),
+			})
+		}
+		for _, d := range s.Detectors {
+			for _, m := range d.Pattern.FindAllStringSubmatchIndex(line, -1) {
+				if 2*d.Group+1 < len(m) && m[2*d.Group] >= 0 {
+					add(d.Name, m[2*d.Group], m[2*d.Group+1])
+				}
+			}
+		}
+		if !s.Entropy {
+			continue
+		}
+		for _, m := range literalRe.FindAllStringSubmatchIndex(line, -1) {
+			for g := 1; g <= 3; g++ {
+				start, end := m[2*g], m[2*g+1]
+				if start < 0 {
+					continue
+				}
+				for _, t := range tokenRe.FindAllStringIndex(line[start:end], -1) {
+					if token := line[start+t[0] : start+t[1]]; highEntropy(token) {
+						add(EntropyDetector, start+t[0], start+t[1])
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// ScanDir scans the files under root, skipping .git, the baseline file,
+// binary files and files over 1 MiB. Findings are ordered by file and line.
+func (s *Scanner) ScanDir(root string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || d.Name() == BaselineFile {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxScanFileSize {
+			return nil
+		}
+		// #nosec G304 -- path is a file under the directory being scanned.
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, s.ScanText(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", root, err)
+	}
+	return findings, nil
+}
+
+// Fingerprint identifies a secret found by detector.
+func Fingerprint(detector, secret string) string {
+	sum := sha256.Sum256([]byte(detector + "\x00" + secret))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Redact hides all but the first four characters of a secret, and all of
+// a short one.
+func Redact(secret string) string {
+	n := utf8.RuneCountInString(secret)
+	if n <= 8 {
+		return strings.Repeat("*", n)
+	}
+	return string([]rune(secret)[:4]) + strings.Repeat("*", min(n-4, 16))
+}
+
+// excerpt returns line with line[start:end] redacted, trimmed and
+// shortened to maxExcerptLength characters.
+func excerpt(line string, start, end int) string {
+	out := []rune(strings.TrimSpace(line[:start] + Redact(line[start:end]) + line[end:]))
+	if len(out) > maxExcerptLength {
+		out = append(out[:maxExcerptLength-1], '…')
+	}
+	return string(out)
+}
+
+// highEntropy reports whether token looks like an encoded secret: long
+// enough, mixing letters and digits, and random enough for its alphabet.
+func highEntropy(token string) bool {
+	if len(token) < minLiteralLength || !strings.ContainsAny(token, "0123456789") || strings.Trim(token, "0123456789+/_-=") == "" {
+		return false
+	}
+	if hexRe.MatchString(token) {
+		return len(token) >= minHexLiteralLength && shannonEntropy(token) >= hexEntropy
+	}
+	return shannonEntropy(token) >= base64Entropy
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -1,6 +1,9 @@
 package skill
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Artifact struct {
 	ID           string
@@ -10,6 +13,17 @@ type Artifact struct {
 	InputSchema  string
 	OutputSchema string
 	Guardrails   []string
+	// Model is the model handlers that call a provider should use, normally
+	// the one model.Router.Decide selected for this execution.
+	Model string
+	// Schemas, when set, makes Executor.Execute validate the input and the
+	// handler's output against the skill's compiled schemas.
+	Schemas *SkillSchemas
+	// Timeout bounds each execution; zero means the executor's default.
+	Timeout time.Duration
+	// Triggers selects the optional prompt sections handlers render; nil
+	// renders every section.
+	Triggers []string
 }
 
 func (a Artifact) Validate() error {
@@ -0,0 +1,435 @@
+package skill
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultApproxTolerance is the tolerance $approx uses when the assertion
+// does not set $tolerance.
+const DefaultApproxTolerance = 1e-9
+
+// Mismatch is one difference between a fixture's expected file and the
+// executor output. Path is a JSON pointer into the output, or the JSONPath
+// selector when the selector matched several values. Expected and Actual are
+// JSON encodings; Actual is empty when the value is missing.
+type Mismatch struct {
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// MatchExpected compares executor output against the assertions of an
+// expected file and returns every mismatch, sorted by path.
+//
+// Top-level keys name output properties; output properties the expected file
+// does not mention are ignored. Below the top level, values must be deeply
+// equal: objects need the same keys and arrays the same length. A key that
+// starts with "$." or "$[" is a JSONPath selector instead, such as
+// "$.items[0].title" or "$.items[*].id"; a selector with a wildcard matches
+// its assertion against the array of selected values.
+//
+// An object whose keys all start with "$" is an assertion rather than a
+// literal value:
+//
+//	{"$exists": false}                 the value is absent (or present, with true)
+//	{"$regex": "^v\\d+"}               a string matching the pattern
+//	{"$contains": "x"}                 a string containing x, an array with an
+//	                                   element matching x, or an object whose
+//	                                   properties include x's
+//	{"$approx": 0.5, "$tolerance": 0.01}  a number within the tolerance
+//	{"$len": 3}                        a string, array or object of that length;
+//	                                   the length may itself be an assertion
+//
+// Several operators in one object must all hold.
+func MatchExpected(expected, actual map[string]any) []Mismatch {
+	normalized, err := normalizeJSON(actual)
+	if err != nil {
+		return []Mismatch{{Message: err.Error()}}
+	}
+	out, _ := normalized.(map[string]any)
+	m := &matcher{}
+	for _, key := range sortedKeys(expected) {
+		if isJSONPath(key) {
+			m.matchSelector(key, expected[key], out)
+			continue
+		}
+		value, present := out[key]
+		m.match("/"+escapePointer(key), expected[key], value, present)
+	}
+	sort.SliceStable(m.mismatches, func(i, j int) bool { return m.mismatches[i].Path < m.mismatches[j].Path })
+	return m.mismatches
+}
+
+type matcher struct {
+	mismatches []Mismatch
+}
+
+func (m *matcher) fail(path, message string, expected, actual any, present bool) {
+	mm := Mismatch{Path: path, Message: message}
+	if expected != nil {
+		mm.Expected = encodeValue(expected)
+	}
+	if present {
+		mm.Actual = encodeValue(actual)
+	}
+	m.mismatches = append(m.mismatches, mm)
+}
+
+// ok reports whether actual satisfies expected without recording mismatches.
+func (m *matcher) ok(expected, actual any, present bool) bool {
+	sub := &matcher{}
+	sub.match("", expected, actual, present)
+	return len(sub.mismatches) == 0
+}
+
+func (m *matcher) match(path string, expected, actual any, present bool) {
+	if ops, ok := assertionOperators(expected); ok {
+		m.matchOperators(path, ops, actual, present)
+		return
+	}
+	if !present {
+		m.fail(path, "missing", expected, nil, false)
+		return
+	}
+	switch exp := expected.(type) {
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok {
+			m.fail(path, "expected object, got "+jsonType(actual), expected, actual, true)
+			return
+		}
+		for _, key := range sortedKeys(exp) {
+			value, present := act[key]
+			m.match(path+"/"+escapePointer(key), exp[key], value, present)
+		}
+		for _, key := range sortedKeys(act) {
+			if _, ok := exp[key]; !ok {
+				m.fail(path+"/"+escapePointer(key), "unexpected property", nil, act[key], true)
+			}
+		}
+	case []any:
+		act, ok := actual.([]any)
+		if !ok {
+			m.fail(path, "expected array, got "+jsonType(actual), expected, actual, true)
+			return
+		}
+		if len(act) != len(exp) {
+			m.fail(path, fmt.Sprintf("expected %d items, got %d", len(exp), len(act)), expected, actual, true)
+			return
+		}
+		for i := range exp {
+			m.match(path+"/"+strconv.Itoa(i), exp[i], act[i], true)
+		}
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			m.fail(path, "not equal", expected, actual, true)
+		}
+	}
+}
+
+// assertionOperators returns expected as an operator object when every key
+// starts with "$".
+func assertionOperators(expected any) (map[string]any, bool) {
+	obj, ok := expected.(map[string]any)
+	if !ok || len(obj) == 0 {
+		return nil, false
+	}
+	for key := range obj {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+func (m *matcher) matchOperators(path string, ops map[string]any, actual any, present bool) {
+	if want, ok := ops["$exists"]; ok {
+		b, isBool := want.(bool)
+		switch {
+		case !isBool:
+			m.fail(path, "$exists takes true or false", nil, nil, false)
+		case b && !present:
+			m.fail(path, "missing", nil, nil, false)
+		case !b && present:
+			m.fail(path, "expected no value", nil, actual, true)
+		}
+	}
+	for _, op := range sortedKeys(ops) {
+		arg := ops[op]
+		switch op {
+		case "$exists":
+			continue
+		case "$tolerance":
+			if _, ok := ops["$approx"]; !ok {
+				m.fail(path, "$tolerance requires $approx", nil, nil, false)
+			}
+			continue
+		case "$contains", "$regex", "$approx", "$len":
+		default:
+			m.fail(path, "unknown assertion "+op, nil, nil, false)
+			continue
+		}
+		if !present {
+			m.fail(path, "missing", ops, nil, false)
+			return
+		}
+		switch op {
+		case "$contains":
+			m.matchContains(path, arg, actual)
+		case "$regex":
+			m.matchRegex(path, arg, actual)
+		case "$approx":
+			m.matchApprox(path, arg, ops["$tolerance"], actual)
+		case "$len":
+			m.matchLen(path, arg, actual)
+		}
+	}
+}
+
+func (m *matcher) matchContains(path string, want, actual any) {
+	switch act := actual.(type) {
+	case string:
+		s, ok := want.(string)
+		if !ok {
+			m.fail(path, "$contains on a string takes a string", want, actual, true)
+		} else if !strings.Contains(act, s) {
+			m.fail(path, "does not contain "+encodeValue(s), nil, actual, true)
+		}
+	case []any:
+		for _, item := range act {
+			if m.ok(want, item, true) {
+				return
+			}
+		}
+		m.fail(path, "no item matches "+encodeValue(want), nil, actual, true)
+	case map[string]any:
+		exp, ok := want.(map[string]any)
+		if !ok {
+			m.fail(path, "$contains on an object takes an object", want, actual, true)
+			return
+		}
+		for _, key := range sortedKeys(exp) {
+			value, present := act[key]
+			m.match(path+"/"+escapePointer(key), exp[key], value, present)
+		}
+	default:
+		m.fail(path, "$contains needs a string, array or object, got "+jsonType(actual), nil, actual, true)
+	}
+}
+
+func (m *matcher) matchRegex(path string, pattern, actual any) {
+	p, ok := pattern.(string)
+	if !ok {
+		m.fail(path, "$regex takes a string", nil, nil, false)
+		return
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		m.fail(path, fmt.Sprintf("invalid $regex %q: %v", p, err), nil, nil, false)
+		return
+	}
+	s, ok := actual.(string)
+	if !ok {
+		m.fail(path, "$regex needs a string, got "+jsonType(actual), nil, actual, true)
+		return
+	}
+	if !re.MatchString(s) {
+		m.fail(path, fmt.Sprintf("does not match %q", p), nil, actual, true)
+	}
+}
+
+func (m *matcher) matchApprox(path string, want, tolerance, actual any) {
+	target, ok := want.(float64)
+	if !ok {
+		m.fail(path, "$approx takes a number", nil, nil, false)
+		return
+	}
+	tol := DefaultApproxTolerance
+	if tolerance != nil {
+		t, ok := tolerance.(float64)
+		if !ok || t < 0 {
+			m.fail(path, "$tolerance takes a non-negative number", nil, nil, false)
+			return
+		}
+		tol = t
+	}
+	n, ok := actual.(float64)
+	if !ok {
+		m.fail(path, "$approx needs a number, got "+jsonType(actual), want, actual, true)
+		return
+	}
+	if math.Abs(n-target) > tol {
+		m.fail(path, fmt.Sprintf("not within %v", tol), want, actual, true)
+	}
+}
+
+func (m *matcher) matchLen(path string, want, actual any) {
+	var n int
+	switch act := actual.(type) {
+	case string:
+		n = utf8.RuneCountInString(act)
+	case []any:
+		n = len(act)
+	case map[string]any:
+		n = len(act)
+	default:
+		m.fail(path, "$len needs a string, array or object, got "+jsonType(actual), nil, actual, true)
+		return
+	}
+	if _, isOps := assertionOperators(want); isOps {
+		sub := &matcher{}
+		sub.match(path, want, float64(n), true)
+		for _, mm := range sub.mismatches {
+			mm.Message = "length " + mm.Message
+			m.mismatches = append(m.mismatches, mm)
+		}
+		return
+	}
+	if !reflect.DeepEqual(want, float64(n)) {
+		m.fail(path, "wrong length", want, float64(n), true)
+	}
+}
+
+func isJSONPath(key string) bool {
+	return strings.HasPrefix(key, "$.") || strings.HasPrefix(key, "$[")
+}
+
+// matchSelector evaluates a JSONPath selector against the output and
+// matches expected against what it selects.
+func (m *matcher) matchSelector(selector string, expected any, root map[string]any) {
+	steps, err := parseJSONPath(selector)
+	if err != nil {
+		m.fail(selector, err.Error(), nil, nil, false)
+		return
+	}
+	nodes := []selected{{value: root}}
+	wildcard := false
+	for _, step := range steps {
+		wildcard = wildcard || step.wildcard
+		var next []selected
+		for _, n := range nodes {
+			next = append(next, step.apply(n)...)
+		}
+		nodes = next
+	}
+	if wildcard {
+		values := make([]any, len(nodes))
+		for i, n := range nodes {
+			values[i] = n.value
+		}
+		m.match(selector, expected, values, true)
+		return
+	}
+	if len(nodes) == 0 {
+		m.match(selector, expected, nil, false)
+		return
+	}
+	m.match(nodes[0].pointer, expected, nodes[0].value, true)
+}
+
+// selected is a value a JSONPath selector reached, with its JSON pointer.
+type selected struct {
+	pointer string
+	value   any
+}
+
+// pathStep is one segment of a JSONPath selector: a property name, an array
+// index, or a wildcard over every member.
+type pathStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func (s pathStep) apply(n selected) []selected {
+	switch v := n.value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			out := make([]selected, 0, len(v))
+			for _, key := range sortedKeys(v) {
+				out = append(out, selected{n.pointer + "/" + escapePointer(key), v[key]})
+			}
+			return out
+		}
+		if s.isIndex {
+			return nil
+		}
+		if value, ok := v[s.name]; ok {
+			return []selected{{n.pointer + "/" + escapePointer(s.name), value}}
+		}
+	case []any:
+		if s.wildcard {
+			out := make([]selected, len(v))
+			for i, item := range v {
+				out[i] = selected{n.pointer + "/" + strconv.Itoa(i), item}
+			}
+			return out
+		}
+		i := s.index
+		if i < 0 {
+			i += len(v)
+		}
+		if s.isIndex && i >= 0 && i < len(v) {
+			return []selected{{n.pointer + "/" + strconv.Itoa(i), v[i]}}
+		}
+	}
+	return nil
+}
+
+// parseJSONPath parses the JSONPath subset fixtures use: $, .name, .*,
+// ['name'], ["name"], [n] (negative counts from the end) and [*].
+func parseJSONPath(expr string) ([]pathStep, error) {
+	rest := strings.TrimPrefix(expr, "$")
+	var steps []pathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid JSONPath %q: empty property name", expr)
+			case "*":
+				steps = append(steps, pathStep{wildcard: true})
+			default:
+				steps = append(steps, pathStep{name: name})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed [", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{name: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSONPath %q: bad index %q", expr, inner)
+				}
+				steps = append(steps, pathStep{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q", expr)
+		}
+	}
+	return steps, nil
+}
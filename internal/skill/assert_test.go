@@ -0,0 +1,132 @@
+package skill
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeMap(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var out map[string]any
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return out
+}
+
+func mismatchPaths(mismatches []Mismatch) map[string]Mismatch {
+	out := make(map[string]Mismatch, len(mismatches))
+	for _, m := range mismatches {
+		out[m.Path] = m
+	}
+	return out
+}
+
+func TestMatchExpectedDeepEquality(t *testing.T) {
+	actual := decodeMap(t, `{
+		"status": "ok",
+		"summary": {"title": "Q3", "tags": ["a", "b"]},
+		"ignored": true
+	}`)
+	if got := MatchExpected(decodeMap(t, `{"status":"ok","summary":{"title":"Q3","tags":["a","b"]}}`), actual); len(got) != 0 {
+		t.Fatalf("expected match, got %v", got)
+	}
+
+	got := mismatchPaths(MatchExpected(decodeMap(t, `{
+		"status": "done",
+		"summary": {"title": "Q4", "tags": ["a"], "owner": "me"},
+		"missing": 1
+	}`), map[string]any{
+		"status":  "ok",
+		"summary": map[string]any{"title": "Q3", "tags": []string{"a", "b"}, "extra": 1},
+	}))
+	want := map[string]Mismatch{
+		"/status":        {Path: "/status", Message: "not equal", Expected: `"done"`, Actual: `"ok"`},
+		"/summary/title": {Path: "/summary/title", Message: "not equal", Expected: `"Q4"`, Actual: `"Q3"`},
+		"/summary/tags":  {Path: "/summary/tags", Message: "expected 1 items, got 2", Expected: `["a"]`, Actual: `["a","b"]`},
+		"/summary/owner": {Path: "/summary/owner", Message: "missing", Expected: `"me"`},
+		"/summary/extra": {Path: "/summary/extra", Message: "unexpected property", Actual: `1`},
+		"/missing":       {Path: "/missing", Message: "missing", Expected: `1`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d mismatches, got %v", len(want), got)
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s: expected %+v, got %+v", path, w, got[path])
+		}
+	}
+}
+
+func TestMatchExpectedOperators(t *testing.T) {
+	actual := decodeMap(t, `{
+		"title": "Release v12 notes",
+		"score": 0.8312,
+		"items": [{"id": 1, "kind": "bug"}, {"id": 2, "kind": "feature"}],
+		"meta": {"lang": "en", "words": 120}
+	}`)
+	passing := `{
+		"title": {"$regex": "^Release v\\d+", "$contains": "notes", "$len": 17},
+		"score": {"$approx": 0.83, "$tolerance": 0.01},
+		"items": {"$contains": {"kind": "feature", "id": {"$exists": true}}, "$len": {"$approx": 2}},
+		"meta": {"$contains": {"lang": "en"}},
+		"draft": {"$exists": false}
+	}`
+	if got := MatchExpected(decodeMap(t, passing), actual); len(got) != 0 {
+		t.Fatalf("expected assertions to hold, got %v", got)
+	}
+
+	failing := `{
+		"title": {"$regex": "^Draft"},
+		"score": {"$approx": 0.9},
+		"items": {"$contains": {"kind": "chore"}, "$len": 3},
+		"meta": {"$contains": {"lang": "de"}},
+		"draft": {"$exists": true},
+		"other": {"$bogus": 1}
+	}`
+	got := mismatchPaths(MatchExpected(decodeMap(t, failing), actual))
+	for _, path := range []string{"/title", "/score", "/items", "/meta/lang", "/draft", "/other"} {
+		if _, ok := got[path]; !ok {
+			t.Errorf("missing mismatch at %s; got %v", path, got)
+		}
+	}
+	if got["/other"].Message != "unknown assertion $bogus" {
+		t.Errorf("unexpected message for unknown operator: %+v", got["/other"])
+	}
+}
+
+func TestMatchExpectedJSONPath(t *testing.T) {
+	actual := decodeMap(t, `{
+		"items": [{"id": "a", "title": "First"}, {"id": "b", "title": "Second"}],
+		"meta": {"weird key": 3}
+	}`)
+	passing := `{
+		"$.items[0].title": "First",
+		"$.items[-1].id": "b",
+		"$.items[*].id": ["a", "b"],
+		"$.meta['weird key']": 3,
+		"$.items[5]": {"$exists": false}
+	}`
+	if got := MatchExpected(decodeMap(t, passing), actual); len(got) != 0 {
+		t.Fatalf("expected selectors to match, got %v", got)
+	}
+
+	got := mismatchPaths(MatchExpected(decodeMap(t, `{
+		"$.items[1].title": "Third",
+		"$.items[*].title": {"$contains": "Fourth"},
+		"$.nothing.here": 1,
+		"$.items[x]": 1
+	}`), actual))
+	if got["/items/1/title"].Expected != `"Third"` {
+		t.Errorf("expected concrete pointer for single selector, got %v", got)
+	}
+	if _, ok := got["$.items[*].title"]; !ok {
+		t.Errorf("expected wildcard selector path, got %v", got)
+	}
+	if got["$.nothing.here"].Message != "missing" {
+		t.Errorf("expected missing selector target, got %v", got)
+	}
+	if _, ok := got["$.items[x]"]; !ok {
+		t.Errorf("expected invalid selector to be reported, got %v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package skill
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ReferencesDir is the directory of an installed skill that optional prompt
+// sections are written to.
+const ReferencesDir = "references"
+
+// ReferenceFile is an optional prompt section installed beside SKILL.md for
+// the agent to read when the section's trigger applies.
+type ReferenceFile struct {
+	Trigger     string
+	Description string
+	// Path is the slash-separated location of the file relative to the
+	// installed skill directory.
+	Path    string
+	Content string
+}
+
+// DisclosedPrompt is a prompt laid out for progressive disclosure.
+type DisclosedPrompt struct {
+	// Body is the SKILL.md body: the base prompt, the required sections
+	// and an index of the reference files.
+	Body string
+	// Required lists the triggers of the sections inlined in Body.
+	Required []string
+	// References holds one file per optional trigger, in prompt order.
+	References []ReferenceFile
+}
+
+// DisclosePrompt splits a prompt.md body so agents load only the context
+// they need: the base prompt and required sections stay in SKILL.md, and
+// each optional section moves to references/<trigger>.md, listed in an index
+// at the end of SKILL.md. Sections sharing a trigger share a file. A prompt
+// without sections is returned unchanged.
+func DisclosePrompt(promptBody string) DisclosedPrompt {
+	prompt := ParseProgressivePrompt(promptBody)
+	if len(prompt.Sections) == 0 {
+		return DisclosedPrompt{Body: promptBody}
+	}
+
+	var out DisclosedPrompt
+	parts := []string{strings.TrimSpace(prompt.BasePrompt)}
+	byTrigger := map[string]int{}
+	used := map[string]bool{}
+	for _, section := range prompt.Sections {
+		content := strings.TrimSpace(section.Content)
+		if section.Required {
+			if !containsString(out.Required, section.Trigger) {
+				out.Required = append(out.Required, section.Trigger)
+			}
+			parts = append(parts, content)
+			continue
+		}
+		if i, ok := byTrigger[section.Trigger]; ok {
+			ref := &out.References[i]
+			ref.Content = strings.TrimSuffix(ref.Content, "\n") + "\n\n" + content + "\n"
+			if ref.Description == "" {
+				ref.Description = section.Description
+			}
+			continue
+		}
+		byTrigger[section.Trigger] = len(out.References)
+		out.References = append(out.References, ReferenceFile{
+			Trigger:     section.Trigger,
+			Description: section.Description,
+			Path:        referencePath(section.Trigger, used),
+			Content:     content + "\n",
+		})
+	}
+	if len(out.References) > 0 {
+		parts = append(parts, referenceIndex(out.References))
+	}
+
+	var body []string
+	for _, p := range parts {
+		if p != "" {
+			body = append(body, p)
+		}
+	}
+	out.Body = strings.Join(body, "\n\n") + "\n"
+	return out
+}
+
+// referencePath names the reference file for trigger, keeping names unique
+// among those already used.
+func referencePath(trigger string, used map[string]bool) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(trigger) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	base := strings.Trim(b.String(), "-")
+	if base == "" {
+		base = "section"
+	}
+	name := base
+	for n := 2; used[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	used[name] = true
+	return path.Join(ReferencesDir, name+".md")
+}
+
+// referenceIndex tells the agent which reference file to read for which
+// trigger.
+func referenceIndex(refs []ReferenceFile) string {
+	var b strings.Builder
+	b.WriteString("## References\n\n")
+	b.WriteString("Read a reference file only when the task calls for it:\n\n")
+	for _, ref := range refs {
+		when := ref.Description
+		if when == "" {
+			when = "when the task involves " + ref.Trigger
+		}
+		fmt.Fprintf(&b, "- [%s](%s): %s\n", ref.Path, ref.Path, when)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
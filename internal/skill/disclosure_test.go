@@ -0,0 +1,70 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisclosePromptSplitsOptionalSections(t *testing.T) {
+	body := `Review the change.
+
+# @section *security
+Flag leaked secrets.
+
+# @section code: when the change touches Go code
+Check error handling.
+
+# @section Style Guide
+Follow the house style.
+
+# @section code
+Check tests.
+`
+	got := DisclosePrompt(body)
+
+	wantBody := `Review the change.
+
+Flag leaked secrets.
+
+## References
+
+Read a reference file only when the task calls for it:
+
+- [references/code.md](references/code.md): when the change touches Go code
+- [references/style-guide.md](references/style-guide.md): when the task involves Style Guide
+`
+	if got.Body != wantBody {
+		t.Fatalf("unexpected body:\n%s\nwant:\n%s", got.Body, wantBody)
+	}
+	if len(got.Required) != 1 || got.Required[0] != "security" {
+		t.Errorf("expected security inlined, got %v", got.Required)
+	}
+	if len(got.References) != 2 {
+		t.Fatalf("expected two references, got %#v", got.References)
+	}
+	if ref := got.References[0]; ref.Trigger != "code" || ref.Content != "Check error handling.\n\nCheck tests.\n" {
+		t.Errorf("expected sections sharing a trigger to share a file, got %#v", ref)
+	}
+	if got.References[1].Path != "references/style-guide.md" {
+		t.Errorf("unexpected reference path %q", got.References[1].Path)
+	}
+}
+
+func TestDisclosePromptWithoutSectionsIsUnchanged(t *testing.T) {
+	body := "# Prompt\n\nDo the thing.\n"
+	got := DisclosePrompt(body)
+	if got.Body != body || len(got.References) != 0 || len(got.Required) != 0 {
+		t.Fatalf("expected prompt unchanged, got %#v", got)
+	}
+}
+
+func TestReferencePathKeepsNamesUnique(t *testing.T) {
+	used := map[string]bool{}
+	var paths []string
+	for _, trigger := range []string{"a b", "a-b", "!!"} {
+		paths = append(paths, referencePath(trigger, used))
+	}
+	if got := strings.Join(paths, ","); got != "references/a-b.md,references/a-b-2.md,references/section.md" {
+		t.Fatalf("unexpected paths %s", got)
+	}
+}
@@ -1,39 +1,305 @@
 package skill
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/policy"
+)
 
 // HandlerFunc processes a skill artifact with input and returns output.
+// Handlers of this form cannot observe deadlines; prefer ContextHandlerFunc.
 type HandlerFunc func(artifact Artifact, input map[string]any) (map[string]any, error)
 
-// Executor dispatches skill execution to registered handlers.
+// ContextHandlerFunc processes a skill artifact with input and returns
+// output. It should return promptly once ctx is done; the executor stops
+// waiting for it at that point either way.
+type ContextHandlerFunc func(ctx context.Context, artifact Artifact, input map[string]any) (map[string]any, error)
+
+// TimeoutError is returned when an execution outlived its deadline, either
+// the skill's timeout or one set on the caller's context.
+type TimeoutError struct {
+	SkillID string
+	// Timeout is the skill or executor timeout that applied; zero when the
+	// deadline came from the caller's context.
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("skill %s timed out after %s", e.SkillID, e.Timeout)
+	}
+	return fmt.Sprintf("skill %s timed out", e.SkillID)
+}
+
+// Unwrap lets errors.Is match context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error { return context.DeadlineExceeded }
+
+// CanceledError is returned when the caller canceled an execution.
+type CanceledError struct {
+	SkillID string
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("skill %s execution canceled", e.SkillID)
+}
+
+// Unwrap lets errors.Is match context.Canceled.
+func (e *CanceledError) Unwrap() error { return context.Canceled }
+
+// HandlerError wraps an error returned, or a panic raised, by a handler. Its
+// message is the handler's own.
+type HandlerError struct {
+	SkillID string
+	Err     error
+}
+
+func (e *HandlerError) Error() string { return e.Err.Error() }
+
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// GuardrailError is returned when one of the skill's guardrails blocked its
+// input or output.
+type GuardrailError struct {
+	SkillID string
+	Event   policy.GuardrailEvent
+}
+
+func (e *GuardrailError) Error() string {
+	return fmt.Sprintf("guardrail %s blocked %s of skill %s: %s", e.Event.Guardrail, e.Event.Stage, e.SkillID, e.Event.Violation)
+}
+
+// ExecutorOptions tunes NewExecutorWithOptions.
+type ExecutorOptions struct {
+	// MaxConcurrent bounds how many handlers run at once across all skills;
+	// zero means no limit. Executions beyond it wait for a free slot.
+	MaxConcurrent int
+	// DefaultTimeout bounds executions of skills that declare no timeout;
+	// zero means no limit.
+	DefaultTimeout time.Duration
+	// Guardrails resolves the names in Artifact.Guardrails; nil uses
+	// policy.NewGuardrailRegistry.
+	Guardrails *policy.GuardrailRegistry
+}
+
+// Executor dispatches skill execution to registered handlers. It is safe for
+// concurrent use, including registering handlers while executing.
 type Executor struct {
-	handlers map[string]HandlerFunc
+	mu             sync.RWMutex
+	handlers       map[string]ContextHandlerFunc
+	fallback       ContextHandlerFunc
+	slots          chan struct{}
+	defaultTimeout time.Duration
+	guardrails     *policy.GuardrailRegistry
 }
 
-// NewExecutor creates an Executor with an empty handler registry.
+// NewExecutor creates an Executor with an empty handler registry and no
+// concurrency or time limits.
 func NewExecutor() *Executor {
-	return &Executor{handlers: make(map[string]HandlerFunc)}
+	return NewExecutorWithOptions(ExecutorOptions{})
+}
+
+// NewExecutorWithOptions creates an Executor with an empty handler registry
+// and the limits opts sets.
+func NewExecutorWithOptions(opts ExecutorOptions) *Executor {
+	e := &Executor{
+		handlers:       make(map[string]ContextHandlerFunc),
+		defaultTimeout: max(opts.DefaultTimeout, 0),
+		guardrails:     opts.Guardrails,
+	}
+	if e.guardrails == nil {
+		e.guardrails = policy.NewGuardrailRegistry()
+	}
+	if opts.MaxConcurrent > 0 {
+		e.slots = make(chan struct{}, opts.MaxConcurrent)
+	}
+	return e
 }
 
 // RegisterHandler associates a handler with a skill ID.
 func (e *Executor) RegisterHandler(skillID string, handler HandlerFunc) {
+	e.RegisterContextHandler(skillID, func(_ context.Context, a Artifact, input map[string]any) (map[string]any, error) {
+		return handler(a, input)
+	})
+}
+
+// RegisterContextHandler associates a context-aware handler with a skill ID.
+func (e *Executor) RegisterContextHandler(skillID string, handler ContextHandlerFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.handlers[skillID] = handler
 }
 
-// Execute validates the artifact, dispatches to a registered handler if one
-// exists, or falls back to the default stub response.
+// SetFallbackHandler sets the handler used for skills without a registered
+// handler, such as one built by NewModelHandler. A nil handler restores the
+// default stub response.
+func (e *Executor) SetFallbackHandler(handler ContextHandlerFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fallback = handler
+}
+
+func (e *Executor) handler(skillID string) ContextHandlerFunc {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if handler, ok := e.handlers[skillID]; ok {
+		return handler
+	}
+	return e.fallback
+}
+
+// StubOutput is the response for skills without a handler.
+func StubOutput(a Artifact) map[string]any {
+	return map[string]any{
+		"skill_id": a.ID,
+		"status":   "ok",
+	}
+}
+
+// Execute runs ExecuteContext without a caller deadline.
 func (e *Executor) Execute(a Artifact, input map[string]any) (map[string]any, error) {
+	return e.ExecuteContext(context.Background(), a, input)
+}
+
+// ExecuteContext runs ExecuteWithTelemetry and drops the telemetry.
+func (e *Executor) ExecuteContext(ctx context.Context, a Artifact, input map[string]any) (map[string]any, error) {
+	out, _, err := e.ExecuteWithTelemetry(ctx, a, input)
+	return out, err
+}
+
+// ExecuteWithTelemetry validates the artifact, dispatches to a registered
+// handler if one exists, or falls back to the fallback handler or
+// StubOutput. When the artifact carries compiled schemas, the input and the
+// handler's output are validated against them; the stub response is not,
+// since it stands in for a handler.
+//
+// The artifact's guardrails run on the validated input before the handler
+// and on the handler's output after it; what they find is returned as
+// telemetry, even when the execution fails.
+//
+// The handler runs under Artifact.Timeout, or the executor's default, and
+// waits for a free slot when the executor limits concurrency. Failures are
+// reported as *TimeoutError, *CanceledError, *HandlerError or
+// *GuardrailError.
+func (e *Executor) ExecuteWithTelemetry(ctx context.Context, a Artifact, input map[string]any) (map[string]any, policy.RuntimeTelemetry, error) {
+	telemetry := policy.RuntimeTelemetry{Violations: []string{}}
 	if err := a.Validate(); err != nil {
-		return nil, err
+		return nil, telemetry, err
 	}
 	if len(input) == 0 {
-		return nil, fmt.Errorf("input is required")
+		return nil, telemetry, fmt.Errorf("input is required")
 	}
-	if handler, ok := e.handlers[a.ID]; ok {
-		return handler(a, input)
+	if a.Schemas != nil && a.Schemas.Input != nil {
+		if err := a.Schemas.Input.Validate(input); err != nil {
+			return nil, telemetry, fmt.Errorf("input: %w", err)
+		}
 	}
-	return map[string]any{
-		"skill_id": a.ID,
-		"status":   "ok",
-	}, nil
+	input, err := e.guard(a, policy.GuardrailInput, input, &telemetry)
+	if err != nil {
+		return nil, telemetry, err
+	}
+	handler := e.handler(a.ID)
+	if handler == nil {
+		return StubOutput(a), telemetry, nil
+	}
+
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = e.defaultTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	out, err := e.run(ctx, handler, a, input)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, telemetry, contextError(ctx, a.ID, timeout)
+		}
+		return nil, telemetry, &HandlerError{SkillID: a.ID, Err: err}
+	}
+	if a.Schemas != nil && a.Schemas.Output != nil {
+		if err := a.Schemas.Output.Validate(out); err != nil {
+			return nil, telemetry, fmt.Errorf("output: %w", err)
+		}
+	}
+	out, err = e.guard(a, policy.GuardrailOutput, out, &telemetry)
+	if err != nil {
+		return nil, telemetry, err
+	}
+	return out, telemetry, nil
+}
+
+// guard applies the artifact's guardrails for stage to payload, recording
+// their findings in telemetry. Payloads are normalized to plain JSON values
+// first so hooks see every string, whatever Go types a handler used.
+func (e *Executor) guard(a Artifact, stage policy.GuardrailStage, payload map[string]any, telemetry *policy.RuntimeTelemetry) (map[string]any, error) {
+	if len(a.Guardrails) == 0 {
+		return payload, nil
+	}
+	normalized, err := normalizeJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", stage, err)
+	}
+	m, _ := normalized.(map[string]any)
+	guarded, events, err := e.guardrails.Apply(a.Guardrails, stage, m)
+	if err != nil {
+		return nil, err
+	}
+	telemetry.RecordGuardrails(events)
+	for _, event := range events {
+		if event.Blocked {
+			return nil, &GuardrailError{SkillID: a.ID, Event: event}
+		}
+	}
+	return guarded, nil
+}
+
+// run calls handler once a slot is free and returns when it does or ctx is
+// done, whichever comes first. A handler left running keeps its slot until
+// it returns, so abandoned handlers still count against the limit.
+func (e *Executor) run(ctx context.Context, handler ContextHandlerFunc, a Artifact, input map[string]any) (map[string]any, error) {
+	if e.slots != nil {
+		select {
+		case e.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	type outcome struct {
+		out map[string]any
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if e.slots != nil {
+				<-e.slots
+			}
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		out, err := handler(ctx, a, input)
+		done <- outcome{out, err}
+	}()
+	select {
+	case o := <-done:
+		return o.out, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func contextError(ctx context.Context, skillID string, timeout time.Duration) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{SkillID: skillID, Timeout: timeout}
+	}
+	return &CanceledError{SkillID: skillID}
 }
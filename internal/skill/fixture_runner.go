@@ -1,94 +1,238 @@
 package skill
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/model"
 )
 
+// ErrFixtureTimeout is returned for a fixture whose execution outlived
+// FixtureOptions.Timeout.
+var ErrFixtureTimeout = errors.New("fixture timed out")
+
 type FixtureResult struct {
-	Name   string
-	Passed bool
-	Error  string
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+	// Mismatches lists how the output differs from the expected file.
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+	// DurationMS is how long the fixture took to execute, in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
 }
 
-// RunFixtureSuite runs fixture tests using a default executor (stub fallback).
-func RunFixtureSuite(skillDir string) ([]FixtureResult, error) {
-	return RunFixtureSuiteWithExecutor(skillDir, NewExecutor())
+// FixtureOptions tunes how a fixture suite runs.
+type FixtureOptions struct {
+	// Parallel is how many fixtures run at once; values below 1 run them
+	// one after another. Handlers must be safe for concurrent use when it
+	// is above 1.
+	Parallel int
+	// Timeout bounds each fixture's execution; zero means no limit.
+	// Context-aware handlers see the deadline; others keep running in the
+	// background after it passes.
+	Timeout time.Duration
 }
 
-// RunFixtureSuiteWithExecutor runs fixture tests using the provided executor,
-// allowing callers to register custom handlers before running.
-func RunFixtureSuiteWithExecutor(skillDir string, exec *Executor) ([]FixtureResult, error) {
+// fixtureCase is one fixture input and the expected file paired with it.
+type fixtureCase struct {
+	name         string
+	fixturePath  string
+	expectedPath string
+}
+
+// fixtureSuite is a validated skill's artifact and its fixtures.
+type fixtureSuite struct {
+	artifact Artifact
+	testsDir string
+	cases    []fixtureCase
+}
+
+// loadFixtureSuite validates the skill in skillDir for exec, which runs its
+// guardrails, and lists the tests/fixture_*.json files with their
+// expected_*.json counterparts.
+func loadFixtureSuite(skillDir string, exec *Executor) (fixtureSuite, error) {
 	spec, err := LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 	if err != nil {
-		return nil, err
+		return fixtureSuite{}, err
 	}
-	if err := ValidateSkillSpec(skillDir, spec); err != nil {
-		return nil, err
+	if err := ValidateSkillSpecWithGuardrails(skillDir, spec, exec.guardrails); err != nil {
+		return fixtureSuite{}, err
+	}
+	schemas, err := LoadSkillSchemas(skillDir, spec)
+	if err != nil {
+		return fixtureSuite{}, err
+	}
+	timeout, err := spec.ExecutionTimeout()
+	if err != nil {
+		return fixtureSuite{}, err
+	}
+	modelName, err := model.NewRouter().Select(model.RouteRequest{
+		UseCase:    spec.ID,
+		Budget:     spec.Model.Budget,
+		PolicyPack: spec.Model.PolicyPack,
+	})
+	if err != nil {
+		return fixtureSuite{}, err
 	}
 
 	testsDir := filepath.Join(skillDir, "tests")
 	entries, err := os.ReadDir(testsDir)
 	if err != nil {
-		return nil, fmt.Errorf("read tests dir: %w", err)
+		return fixtureSuite{}, fmt.Errorf("read tests dir: %w", err)
 	}
-
-	var results []FixtureResult
-	for _, e := range entries {
-		name := e.Name()
-		if filepath.Ext(name) != ".json" || filepath.Base(name) == "" {
-			continue
-		}
-		if len(name) < len("fixture_") || name[:8] != "fixture_" {
-			continue
-		}
-		fixturePath := filepath.Join(testsDir, name)
-		expectedPath := filepath.Join(testsDir, "expected_"+name[len("fixture_"):])
-
-		input, err := readJSONMap(fixturePath)
-		if err != nil {
-			results = append(results, FixtureResult{Name: name, Passed: false, Error: err.Error()})
-			continue
-		}
-		expected, err := readJSONMap(expectedPath)
-		if err != nil {
-			results = append(results, FixtureResult{Name: name, Passed: false, Error: err.Error()})
-			continue
-		}
-
-		out, err := exec.Execute(Artifact{
+	suite := fixtureSuite{
+		artifact: Artifact{
 			ID:           spec.ID,
+			Name:         spec.Name,
 			Version:      spec.Version,
+			PromptPath:   filepath.Join(skillDir, "prompt.md"),
+			Model:        modelName,
 			InputSchema:  spec.Inputs.Schema,
 			OutputSchema: spec.Outputs.Schema,
-		}, input)
-		if err != nil {
-			results = append(results, FixtureResult{Name: name, Passed: false, Error: err.Error()})
+			Schemas:      schemas,
+			Timeout:      timeout,
+			Guardrails:   spec.Guardrails,
+		},
+		testsDir: testsDir,
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if filepath.Ext(name) != ".json" || !strings.HasPrefix(name, "fixture_") {
 			continue
 		}
+		suite.cases = append(suite.cases, fixtureCase{
+			name:         name,
+			fixturePath:  filepath.Join(testsDir, name),
+			expectedPath: filepath.Join(testsDir, "expected_"+strings.TrimPrefix(name, "fixture_")),
+		})
+	}
+	if len(suite.cases) == 0 {
+		return fixtureSuite{}, fmt.Errorf("no fixture files found in %s", testsDir)
+	}
+	return suite, nil
+}
 
-		passed := true
-		for k, v := range expected {
-			if out[k] != v {
-				passed = false
-				break
-			}
-		}
-		res := FixtureResult{Name: name, Passed: passed}
-		if !passed {
-			res.Error = "output mismatch"
-		}
-		results = append(results, res)
+// RunFixtureSuite runs fixture tests using a default executor (stub fallback).
+func RunFixtureSuite(skillDir string) ([]FixtureResult, error) {
+	return RunFixtureSuiteWithExecutor(skillDir, NewExecutor())
+}
+
+// RunFixtureSuiteWithExecutor runs fixture tests using the provided executor,
+// allowing callers to register custom handlers before running. Fixture
+// inputs and handler outputs are validated against the skill's schemas, and
+// outputs are compared with expected files by MatchExpected.
+func RunFixtureSuiteWithExecutor(skillDir string, exec *Executor) ([]FixtureResult, error) {
+	return RunFixtureSuiteWithOptions(skillDir, exec, FixtureOptions{})
+}
+
+// RunFixtureSuiteWithOptions runs fixture tests like
+// RunFixtureSuiteWithExecutor, concurrently and with a per-fixture timeout
+// as opts selects. Results follow the fixture file order whatever order the
+// fixtures finished in.
+func RunFixtureSuiteWithOptions(skillDir string, exec *Executor, opts FixtureOptions) ([]FixtureResult, error) {
+	suite, err := loadFixtureSuite(skillDir, exec)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no fixture files found in %s", testsDir)
+	results := make([]FixtureResult, len(suite.cases))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(opts.Parallel, 1), len(suite.cases)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runFixtureCase(suite.artifact, exec, suite.cases[i], opts.Timeout)
+			}
+		}()
+	}
+	for i := range suite.cases {
+		jobs <- i
 	}
+	close(jobs)
+	wg.Wait()
 	return results, nil
 }
 
+func runFixtureCase(artifact Artifact, exec *Executor, c fixtureCase, timeout time.Duration) FixtureResult {
+	input, err := readJSONMap(c.fixturePath)
+	if err != nil {
+		return FixtureResult{Name: c.name, Passed: false, Error: err.Error()}
+	}
+	expected, err := readJSONMap(c.expectedPath)
+	if err != nil {
+		return FixtureResult{Name: c.name, Passed: false, Error: err.Error()}
+	}
+
+	start := time.Now()
+	out, err := executeWithTimeout(exec, artifact, input, timeout)
+	duration := time.Since(start).Milliseconds()
+	if err != nil {
+		return FixtureResult{Name: c.name, Passed: false, Error: err.Error(), DurationMS: duration}
+	}
+
+	res := FixtureResult{Name: c.name, Passed: true, DurationMS: duration}
+	if mismatches := MatchExpected(expected, out); len(mismatches) > 0 {
+		res.Passed = false
+		res.Error = "output mismatch"
+		res.Mismatches = mismatches
+	}
+	return res
+}
+
+// executeWithTimeout runs exec.ExecuteContext, giving up after timeout when
+// it is positive.
+func executeWithTimeout(exec *Executor, a Artifact, input map[string]any, timeout time.Duration) (map[string]any, error) {
+	if timeout <= 0 {
+		return exec.Execute(a, input)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := exec.ExecuteContext(ctx, a, input)
+	if err != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("%w after %s", ErrFixtureTimeout, timeout)
+	}
+	return out, err
+}
+
+// FindSkillDirs returns every directory under root, root included, that
+// holds a skill.yaml, sorted by path. The search does not descend into a
+// skill it found, nor into hidden directories.
+func FindSkillDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "skill.yaml")); err == nil {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
 func readJSONMap(path string) (map[string]any, error) {
 	path = filepath.Clean(path)
 	// #nosec G304 -- path is derived from validated tests directory entries.
@@ -1,9 +1,15 @@
 package skill
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/policy"
 )
 
 func TestRunFixtureSuite(t *testing.T) {
@@ -76,6 +82,92 @@ func TestRunFixtureSuiteReportsMismatch(t *testing.T) {
 	}
 }
 
+func TestRunFixtureSuiteValidatesFixtureInput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skill.yaml"), []byte("id: test-skill\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.input.json"), []byte(`{"type":"object","required":["q"],"properties":{"q":{"type":"string"},"limit":{"type":"integer"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.output.json"), []byte(`{"type":"object","properties":{"status":{"type":"string"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tests", "fixture_01.json"), []byte(`{"limit":"ten"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tests", "expected_01.json"), []byte(`{"status":"ok"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := RunFixtureSuite(dir)
+	if err != nil {
+		t.Fatalf("run suite: %v", err)
+	}
+	if len(results) != 1 || results[0].Passed {
+		t.Fatalf("expected fixture to fail input validation: %#v", results)
+	}
+	want := "input: /limit: expected integer, got string; /q: required property is missing"
+	if results[0].Error != want {
+		t.Fatalf("expected %q, got %q", want, results[0].Error)
+	}
+}
+
+func TestRunFixtureSuiteReportsNestedMismatches(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skill.yaml"), []byte("id: nested-skill\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.input.json"), []byte(`{"type":"object","properties":{"q":{"type":"string"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.output.json"), []byte(`{"type":"object","properties":{"result":{"type":"object"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{
+		"fixture_01.json":  `{"q":"a"}`,
+		"expected_01.json": `{"result":{"items":[1,2],"label":{"$regex":"^ok"}}}`,
+		"fixture_02.json":  `{"q":"b"}`,
+		"expected_02.json": `{"result":{"items":[1,3]},"$.result.label":"fine"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "tests", name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exec := NewExecutor()
+	exec.RegisterHandler("nested-skill", func(_ Artifact, _ map[string]any) (map[string]any, error) {
+		return map[string]any{"result": map[string]any{"items": []int{1, 2}, "label": "ok then"}}, nil
+	})
+
+	results, err := RunFixtureSuiteWithExecutor(dir, exec)
+	if err != nil {
+		t.Fatalf("run suite: %v", err)
+	}
+	if len(results) != 2 || !results[0].Passed || results[1].Passed {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+	want := []Mismatch{
+		{Path: "/result/items/1", Message: "not equal", Expected: "3", Actual: "2"},
+		{Path: "/result/label", Message: "not equal", Expected: `"fine"`, Actual: `"ok then"`},
+		{Path: "/result/label", Message: "unexpected property", Actual: `"ok then"`},
+	}
+	got := results[1].Mismatches
+	if results[1].Error != "output mismatch" || len(got) != len(want) {
+		t.Fatalf("unexpected mismatches: %#v", results[1])
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mismatch %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
 // AC: fixture runner must report each fixture independently when multiple
 // fixtures are present — some pass, some fail.
 func TestRunFixtureSuiteMultipleFixturesIndependent(t *testing.T) {
@@ -175,3 +267,111 @@ func TestFixtureResultIncludesName(t *testing.T) {
 		t.Fatalf("expected fixture name 'fixture_alpha.json', got %q", results[0].Name)
 	}
 }
+
+func TestRunFixtureSuiteWithOptionsParallelAndTimeout(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"tests/fixture_01.json":  `{"q":"a"}`,
+		"tests/expected_01.json": `{"answer":"a"}`,
+		"tests/fixture_02.json":  `{"q":"b"}`,
+		"tests/expected_02.json": `{"answer":"b"}`,
+		"tests/fixture_03.json":  `{"q":"slow"}`,
+		"tests/expected_03.json": `{"answer":"slow"}`,
+		"tests/fixture_04.json":  `{"q":"d"}`,
+		"tests/expected_04.json": `{"answer":"d"}`,
+	})
+	var active, peak atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	exec := NewExecutor()
+	exec.RegisterHandler("snap-skill", func(_ Artifact, input map[string]any) (map[string]any, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		q := input["q"].(string)
+		if q == "slow" {
+			<-release
+		} else {
+			time.Sleep(20 * time.Millisecond)
+		}
+		return map[string]any{"answer": q}, nil
+	})
+
+	results, err := RunFixtureSuiteWithOptions(dir, exec, FixtureOptions{Parallel: 4, Timeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("run suite: %v", err)
+	}
+	if peak.Load() < 2 {
+		t.Fatalf("expected fixtures to run concurrently, peak was %d", peak.Load())
+	}
+	for i, r := range results {
+		if want := fmt.Sprintf("fixture_%02d.json", i+1); r.Name != want {
+			t.Fatalf("results out of order: %#v", results)
+		}
+		if r.Name == "fixture_03.json" {
+			if r.Passed || !strings.Contains(r.Error, ErrFixtureTimeout.Error()) {
+				t.Fatalf("expected timeout, got %#v", r)
+			}
+			continue
+		}
+		if !r.Passed || r.DurationMS < 10 {
+			t.Fatalf("unexpected result: %#v", r)
+		}
+	}
+}
+
+func TestFindSkillDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "group/b", "group/b/nested", ".hidden/c", "empty"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dir := range []string{"a", "group/b", "group/b/nested", ".hidden/c"} {
+		if err := os.WriteFile(filepath.Join(root, dir, "skill.yaml"), []byte("id: x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs, err := FindSkillDirs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(root, "a"), filepath.Join(root, "group/b")}
+	if strings.Join(dirs, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, dirs)
+	}
+}
+
+func TestFixtureSuiteAcceptsExecutorGuardrails(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"skill.yaml":             "id: snap-skill\nversion: 0.1.0\nguardrails: [no-swearing]\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"tests/fixture_01.json":  `{"q":"darn"}`,
+		"tests/expected_01.json": `{"answer":"[censored]"}`,
+	})
+	if _, err := RunFixtureSuite(dir); err == nil || !strings.Contains(err.Error(), `unknown guardrail "no-swearing"`) {
+		t.Fatalf("expected the default executor to reject the guardrail, got %v", err)
+	}
+
+	registry := policy.NewGuardrailRegistry()
+	censor := func(map[string]any) policy.GuardrailResult {
+		return policy.GuardrailResult{Payload: map[string]any{"answer": "[censored]"}, Violations: []string{"swearing"}, Redactions: 1}
+	}
+	if err := registry.Register(policy.Guardrail{Name: "no-swearing", Output: censor}); err != nil {
+		t.Fatal(err)
+	}
+	exec := NewExecutorWithOptions(ExecutorOptions{Guardrails: registry})
+	exec.RegisterHandler("snap-skill", func(_ Artifact, input map[string]any) (map[string]any, error) {
+		return map[string]any{"answer": input["q"]}, nil
+	})
+	results, err := RunFixtureSuiteWithExecutor(dir, exec)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(results) != 1 || !results[0].Passed {
+		t.Fatalf("expected the guarded fixture to pass, got %+v", results)
+	}
+}
@@ -0,0 +1,123 @@
+package skill
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// authoringFiles are never installed: they describe, test or generate the
+// skill rather than being read by an agent. SKILL.md is generated from
+// skill.yaml and prompt.md at install time.
+var authoringFiles = []string{"skill.yaml", "prompt.md", "SKILL.md", "tests"}
+
+// InstallableFiles returns the supporting files under skillDir that sync
+// copies next to the generated SKILL.md, as sorted slash-separated paths
+// relative to skillDir. Authoring files (skill.yaml, prompt.md, the declared
+// schemas, tests/ and dot files) are always left out. spec.Install.Include
+// narrows the selection when set and spec.Install.Exclude removes from it.
+func InstallableFiles(skillDir string, spec SkillSpec) ([]string, error) {
+	excluded := append([]string{}, authoringFiles...)
+	for _, schema := range []string{spec.Inputs.Schema, spec.Outputs.Schema} {
+		if schema != "" {
+			excluded = append(excluded, path.Clean(filepath.ToSlash(schema)))
+		}
+	}
+	if err := validateInstallPatterns(spec); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err := filepath.WalkDir(skillDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == skillDir {
+			return nil
+		}
+		rel, err := filepath.Rel(skillDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") || matchAny(excluded, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if len(spec.Install.Include) > 0 && !matchAny(spec.Install.Include, rel) {
+			return nil
+		}
+		if matchAny(spec.Install.Exclude, rel) {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing skill files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func validateInstallPatterns(spec SkillSpec) error {
+	for _, p := range append(append([]string{}, spec.Install.Include...), spec.Install.Exclude...) {
+		if p == "" || path.IsAbs(p) || strings.HasPrefix(path.Clean(p), "..") {
+			return fmt.Errorf("install pattern %q must be a relative path inside the skill", p)
+		}
+		for _, seg := range strings.Split(p, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return fmt.Errorf("install pattern %q: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchInstallPattern(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchInstallPattern matches a slash-separated path against a pattern in
+// which * matches within one segment and ** matches any number of
+// segments. A pattern that matches a directory matches everything under it.
+func matchInstallPattern(pattern, rel string) bool {
+	pat := strings.Split(strings.Trim(path.Clean(pattern), "/"), "/")
+	segs := strings.Split(rel, "/")
+	return matchSegments(pat, segs)
+}
+
+func matchSegments(pat, segs []string) bool {
+	if len(pat) == 0 {
+		// Everything left lives under the matched directory.
+		return true
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pat[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pat[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pat[1:], segs[1:])
+}
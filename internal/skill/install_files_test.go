@@ -0,0 +1,93 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeInstallTree writes files, keyed by slash-separated path, under dir.
+func writeInstallTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInstallableFilesSkipsAuthoringFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeInstallTree(t, dir, map[string]string{
+		"skill.yaml": "", "prompt.md": "", "SKILL.md": "", "schema.input.json": "", "schema.output.json": "",
+		"tests/fixture_01.json": "", ".env": "", ".cache/x": "",
+		"scripts/run.sh": "", "references/api.md": "", "assets/logo.svg": "",
+	})
+	spec := SkillSpec{}
+	spec.Inputs.Schema = "schema.input.json"
+	spec.Outputs.Schema = "./schema.output.json"
+
+	got, err := InstallableFiles(dir, spec)
+	if err != nil {
+		t.Fatalf("InstallableFiles: %v", err)
+	}
+	want := []string{"assets/logo.svg", "references/api.md", "scripts/run.sh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestInstallableFilesIncludeAndExclude(t *testing.T) {
+	dir := t.TempDir()
+	writeInstallTree(t, dir, map[string]string{
+		"scripts/run.sh": "", "scripts/dev/debug.sh": "", "references/api.md": "", "notes.txt": "",
+	})
+	spec := SkillSpec{}
+	spec.Install.Include = []string{"scripts", "references/*.md"}
+	spec.Install.Exclude = []string{"**/debug.sh"}
+
+	got, err := InstallableFiles(dir, spec)
+	if err != nil {
+		t.Fatalf("InstallableFiles: %v", err)
+	}
+	want := []string{"references/api.md", "scripts/run.sh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMatchInstallPattern(t *testing.T) {
+	cases := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"scripts", "scripts/run.sh", true},
+		{"scripts/*.sh", "scripts/run.sh", true},
+		{"scripts/*.sh", "scripts/dev/run.sh", false},
+		{"**/*.sh", "run.sh", true},
+		{"**/*.sh", "a/b/run.sh", true},
+		{"a/**/c", "a/c", true},
+		{"a/**/c", "a/b/b/c/d", true},
+		{"*.md", "references/api.md", false},
+	}
+	for _, tc := range cases {
+		if got := matchInstallPattern(tc.pattern, tc.rel); got != tc.want {
+			t.Errorf("matchInstallPattern(%q, %q) = %v, want %v", tc.pattern, tc.rel, got, tc.want)
+		}
+	}
+}
+
+func TestValidateSkillSpecRejectsBadInstallPatterns(t *testing.T) {
+	for _, pattern := range []string{"", "/etc/passwd", "../outside", "scripts/[", "a/../../b"} {
+		spec := SkillSpec{}
+		spec.Install.Exclude = []string{pattern}
+		if err := validateInstallPatterns(spec); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
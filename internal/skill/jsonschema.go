@@ -0,0 +1,708 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSchemaDepth bounds how deeply $ref and applicator keywords may nest
+// while validating one instance, so a self-referencing schema cannot loop.
+const maxSchemaDepth = 64
+
+// Schema is a compiled JSON Schema. It implements the subset of draft
+// 2020-12 that skill schemas use: type, enum, const, required, properties,
+// patternProperties, additionalProperties, items, prefixItems, minItems,
+// maxItems, uniqueItems, minLength, maxLength, pattern, format, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minProperties,
+// maxProperties, allOf, anyOf, oneOf, not, $ref and $defs. Other keywords
+// are ignored. A Schema is safe for concurrent use.
+type Schema struct {
+	root any
+	doc  string
+	set  *schemaSet
+}
+
+// schemaSet holds every document reachable from a root schema through
+// $ref, keyed by absolute path, and the regular expressions they use. The
+// documents are all loaded while compiling and only read afterwards.
+type schemaSet struct {
+	// dir confines file references; empty allows local references only.
+	dir  string
+	docs map[string]any
+	// checked records the $ref targets compile has already checked.
+	checked  map[string]bool
+	patterns sync.Map
+}
+
+// SchemaViolation is one way an instance fails its schema. Path is the
+// JSON pointer of the offending value; "" is the instance itself.
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + v.Message
+}
+
+// SchemaValidationError lists every violation found in an instance, sorted
+// by path.
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// LoadSchema reads and compiles the schema at rel inside skillDir. File
+// references in $ref resolve relative to the referring document and must
+// stay inside skillDir.
+func LoadSchema(skillDir, rel string) (*Schema, error) {
+	dir, err := filepath.Abs(skillDir)
+	if err != nil {
+		return nil, err
+	}
+	set := &schemaSet{dir: dir, docs: make(map[string]any)}
+	path, err := set.resolveFile(filepath.Join(dir, "schema.json"), rel)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := set.load(path); err != nil {
+		return nil, err
+	}
+	return set.compile(path)
+}
+
+// CompileSchema compiles an in-memory schema. Its $refs may only point
+// within the document itself.
+func CompileSchema(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	set := &schemaSet{docs: map[string]any{"": root}}
+	return set.compile("")
+}
+
+func (set *schemaSet) compile(doc string) (*Schema, error) {
+	s := &Schema{root: set.docs[doc], doc: doc, set: set}
+	if err := set.check(doc, s.root, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (set *schemaSet) load(path string) (any, error) {
+	if doc, ok := set.docs[path]; ok {
+		return doc, nil
+	}
+	// #nosec G304 -- path is confined to the skill directory by resolveFile.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse schema %s: %w", filepath.Base(path), err)
+	}
+	set.docs[path] = doc
+	return doc, nil
+}
+
+// resolveFile resolves a file reference made from the document at from.
+func (set *schemaSet) resolveFile(from, ref string) (string, error) {
+	if set.dir == "" {
+		return "", fmt.Errorf("$ref %q: only references within the schema are supported", ref)
+	}
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" {
+		return "", fmt.Errorf("$ref %q: remote references are not supported", ref)
+	}
+	path := filepath.Clean(filepath.Join(filepath.Dir(from), filepath.FromSlash(ref)))
+	rel, err := filepath.Rel(set.dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("$ref %q points outside the skill directory", ref)
+	}
+	return path, nil
+}
+
+// resolveRef returns the document and schema a $ref made from doc points
+// to. The document must already be loaded.
+func (set *schemaSet) resolveRef(doc, ref string) (string, any, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	target := doc
+	if file != "" {
+		path, err := set.resolveFile(doc, file)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := set.docs[path]; !ok {
+			return "", nil, fmt.Errorf("$ref %q: schema %s is not loaded", ref, filepath.Base(path))
+		}
+		target = path
+	}
+	node, err := resolvePointer(set.docs[target], fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("$ref %q: %w", ref, err)
+	}
+	return target, node, nil
+}
+
+// resolvePointer follows a JSON pointer fragment through doc.
+func resolvePointer(doc any, fragment string) (any, error) {
+	if fragment == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("only JSON pointer fragments are supported")
+	}
+	node := doc
+	for _, token := range strings.Split(fragment[1:], "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			next, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", token)
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("index %q out of range", token)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%q not found", token)
+		}
+	}
+	return node, nil
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+// check walks a schema once at compile time, rejecting unknown types,
+// invalid patterns and unresolvable references. at is the schema location
+// used in errors.
+func (set *schemaSet) check(doc string, node any, at string) error {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		if _, isBool := node.(bool); isBool {
+			return nil
+		}
+		return fmt.Errorf("%s: schema must be an object or boolean", at)
+	}
+	for _, t := range typeNames(schema["type"]) {
+		if !schemaTypes[t] {
+			return fmt.Errorf("%s/type: unknown type %q", at, t)
+		}
+	}
+	if p, ok := schema["pattern"].(string); ok {
+		if _, err := set.pattern(p); err != nil {
+			return fmt.Errorf("%s/pattern: %w", at, err)
+		}
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		if err := set.checkRef(doc, ref); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+	}
+	for _, key := range []string{"properties", "patternProperties", "$defs", "definitions"} {
+		children, _ := schema[key].(map[string]any)
+		for _, name := range sortedKeys(children) {
+			if key == "patternProperties" {
+				if _, err := set.pattern(name); err != nil {
+					return fmt.Errorf("%s/%s: %w", at, key, err)
+				}
+			}
+			if err := set.check(doc, children[name], at+"/"+key+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"additionalProperties", "items", "not"} {
+		if child, ok := schema[key]; ok {
+			if err := set.check(doc, child, at+"/"+key); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"prefixItems", "allOf", "anyOf", "oneOf"} {
+		children, _ := schema[key].([]any)
+		for i, child := range children {
+			if err := set.check(doc, child, fmt.Sprintf("%s/%s/%d", at, key, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRef loads the document a $ref made from doc points to and checks the
+// schema there, once per target, so every schema Validate can reach through
+// $ref is loaded and checked before it runs.
+func (set *schemaSet) checkRef(doc, ref string) error {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if file != "" {
+		path, err := set.resolveFile(doc, file)
+		if err != nil {
+			return err
+		}
+		if _, err := set.load(path); err != nil {
+			return err
+		}
+	}
+	target, node, err := set.resolveRef(doc, ref)
+	if err != nil {
+		return err
+	}
+	key := target + "#" + fragment
+	if set.checked[key] {
+		return nil
+	}
+	if set.checked == nil {
+		set.checked = make(map[string]bool)
+	}
+	set.checked[key] = true
+	at := "#" + fragment
+	if rel, err := filepath.Rel(set.dir, target); err == nil && target != doc {
+		at = filepath.ToSlash(rel) + at
+	}
+	return set.check(target, node, at)
+}
+
+func (set *schemaSet) pattern(p string) (*regexp.Regexp, error) {
+	if re, ok := set.patterns.Load(p); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+	set.patterns.Store(p, re)
+	return re, nil
+}
+
+// Validate checks instance against the schema and returns a
+// *SchemaValidationError listing every violation, or nil. Go values are
+// compared by their JSON encoding, so structs and typed maps work too.
+func (s *Schema) Validate(instance any) error {
+	normalized, err := normalizeJSON(instance)
+	if err != nil {
+		return err
+	}
+	v := &validator{set: s.set}
+	v.validate(s.doc, s.root, normalized, "", 0)
+	if len(v.violations) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.violations, func(i, j int) bool { return v.violations[i].Path < v.violations[j].Path })
+	return &SchemaValidationError{Violations: v.violations}
+}
+
+// Document returns the schema's root document as JSON. Documents it
+// references through $ref are not inlined.
+func (s *Schema) Document() ([]byte, error) {
+	return json.Marshal(s.root)
+}
+
+func normalizeJSON(instance any) (any, error) {
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return nil, fmt.Errorf("encode instance: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode instance: %w", err)
+	}
+	return out, nil
+}
+
+type validator struct {
+	set        *schemaSet
+	violations []SchemaViolation
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether instance satisfies node without recording
+// violations, for the anyOf, oneOf and not applicators.
+func (v *validator) matches(doc string, node, instance any, path string, depth int) bool {
+	sub := &validator{set: v.set}
+	sub.validate(doc, node, instance, path, depth)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(doc string, node, instance any, path string, depth int) {
+	if depth > maxSchemaDepth {
+		v.fail(path, "schema nesting exceeds %d levels", maxSchemaDepth)
+		return
+	}
+	schema, ok := node.(map[string]any)
+	if !ok {
+		if b, isBool := node.(bool); isBool && !b {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, resolved, err := v.set.resolveRef(doc, ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+		} else {
+			v.validate(target, resolved, instance, path, depth+1)
+		}
+	}
+
+	if types := typeNames(schema["type"]); len(types) > 0 && !hasType(instance, types) {
+		v.fail(path, "expected %s, got %s", strings.Join(types, " or "), jsonType(instance))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, instance) {
+		v.fail(path, "must be one of %s", encodeValues(enum))
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, instance) {
+		v.fail(path, "must be %s", encodeValue(c))
+	}
+
+	switch x := instance.(type) {
+	case map[string]any:
+		v.validateObject(doc, schema, x, path, depth)
+	case []any:
+		v.validateArray(doc, schema, x, path, depth)
+	case string:
+		v.validateString(schema, x, path)
+	case float64:
+		validateNumber(v, schema, x, path)
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(doc, sub, instance, path, depth+1)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.matches(doc, sub, instance, path, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any schema in anyOf")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range oneOf {
+			if v.matches(doc, sub, instance, path, depth+1) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(path, "must match exactly one schema in oneOf, matched %d", n)
+		}
+	}
+	if not, ok := schema["not"]; ok && v.matches(doc, not, instance, path, depth+1) {
+		v.fail(path, "must not match the schema in not")
+	}
+}
+
+func (v *validator) validateObject(doc string, schema, obj map[string]any, path string, depth int) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				v.fail(path+"/"+escapePointer(name), "required property is missing")
+			}
+		}
+	}
+	if n, ok := schemaInt(schema, "minProperties"); ok && len(obj) < n {
+		v.fail(path, "must have at least %d properties, has %d", n, len(obj))
+	}
+	if n, ok := schemaInt(schema, "maxProperties"); ok && len(obj) > n {
+		v.fail(path, "must have at most %d properties, has %d", n, len(obj))
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	patterns, _ := schema["patternProperties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, name := range sortedKeys(obj) {
+		childPath := path + "/" + escapePointer(name)
+		matched := false
+		if sub, ok := properties[name]; ok {
+			matched = true
+			v.validate(doc, sub, obj[name], childPath, depth+1)
+		}
+		for _, p := range sortedKeys(patterns) {
+			if re, err := v.set.pattern(p); err == nil && re.MatchString(name) {
+				matched = true
+				v.validate(doc, patterns[p], obj[name], childPath, depth+1)
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if b, ok := additional.(bool); ok && !b {
+			v.fail(childPath, "additional property is not allowed")
+			continue
+		}
+		v.validate(doc, additional, obj[name], childPath, depth+1)
+	}
+}
+
+func (v *validator) validateArray(doc string, schema map[string]any, arr []any, path string, depth int) {
+	if n, ok := schemaInt(schema, "minItems"); ok && len(arr) < n {
+		v.fail(path, "must have at least %d items, has %d", n, len(arr))
+	}
+	if n, ok := schemaInt(schema, "maxItems"); ok && len(arr) > n {
+		v.fail(path, "must have at most %d items, has %d", n, len(arr))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					v.fail(fmt.Sprintf("%s/%d", path, i), "duplicates item %d", j)
+				}
+			}
+		}
+	}
+	prefix, _ := schema["prefixItems"].([]any)
+	for i, item := range arr {
+		itemPath := fmt.Sprintf("%s/%d", path, i)
+		if i < len(prefix) {
+			v.validate(doc, prefix[i], item, itemPath, depth+1)
+			continue
+		}
+		if items, ok := schema["items"]; ok {
+			v.validate(doc, items, item, itemPath, depth+1)
+		}
+	}
+}
+
+func (v *validator) validateString(schema map[string]any, s, path string) {
+	length := utf8.RuneCountInString(s)
+	if n, ok := schemaInt(schema, "minLength"); ok && length < n {
+		v.fail(path, "must be at least %d characters, is %d", n, length)
+	}
+	if n, ok := schemaInt(schema, "maxLength"); ok && length > n {
+		v.fail(path, "must be at most %d characters, is %d", n, length)
+	}
+	if p, ok := schema["pattern"].(string); ok {
+		if re, err := v.set.pattern(p); err == nil && !re.MatchString(s) {
+			v.fail(path, "does not match pattern %q", p)
+		}
+	}
+	if format, ok := schema["format"].(string); ok {
+		if check, known := formatCheckers[format]; known && !check(s) {
+			v.fail(path, "is not a valid %s", format)
+		}
+	}
+}
+
+func validateNumber(v *validator, schema map[string]any, n float64, path string) {
+	if min, ok := schema["minimum"].(float64); ok && n < min {
+		v.fail(path, "must be >= %v", min)
+	}
+	if max, ok := schema["maximum"].(float64); ok && n > max {
+		v.fail(path, "must be <= %v", max)
+	}
+	if min, ok := schema["exclusiveMinimum"].(float64); ok && n <= min {
+		v.fail(path, "must be > %v", min)
+	}
+	if max, ok := schema["exclusiveMaximum"].(float64); ok && n >= max {
+		v.fail(path, "must be < %v", max)
+	}
+	if m, ok := schema["multipleOf"].(float64); ok && m > 0 {
+		q := n / m
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "must be a multiple of %v", m)
+		}
+	}
+}
+
+var (
+	uuidRe     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameRe = regexp.MustCompile(`^(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)(?:\.(?i:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?))*$`)
+)
+
+// formatCheckers validate the string formats skills commonly declare.
+// Unknown formats are annotations only and always pass.
+var formatCheckers = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+	"time": func(s string) bool {
+		_, err := time.Parse("15:04:05.999999999Z07:00", s)
+		return err == nil
+	},
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	},
+	"uri-reference": func(s string) bool {
+		_, err := url.Parse(s)
+		return err == nil
+	},
+	"uuid": uuidRe.MatchString,
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	},
+	"hostname": func(s string) bool {
+		return len(s) <= 253 && hostnameRe.MatchString(s)
+	},
+}
+
+func typeNames(t any) []string {
+	switch x := t.(type) {
+	case string:
+		return []string{x}
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, v := range x {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func hasType(instance any, types []string) bool {
+	actual := jsonType(instance)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(instance any) string {
+	switch x := instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) && !math.IsInf(x, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func schemaInt(schema map[string]any, key string) (int, bool) {
+	f, ok := schema[key].(float64)
+	return int(f), ok
+}
+
+func containsValue(values []any, instance any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func encodeValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = encodeValue(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SkillSchemas are the compiled input and output schemas of a skill.
+type SkillSchemas struct {
+	Input  *Schema
+	Output *Schema
+}
+
+// LoadSkillSchemas compiles the input and output schemas spec declares.
+func LoadSkillSchemas(skillDir string, spec SkillSpec) (*SkillSchemas, error) {
+	input, err := LoadSchema(skillDir, spec.Inputs.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema: %w", err)
+	}
+	output, err := LoadSchema(skillDir, spec.Outputs.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output schema: %w", err)
+	}
+	return &SkillSchemas{Input: input, Output: output}, nil
+}
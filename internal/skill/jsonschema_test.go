@@ -0,0 +1,312 @@
+package skill
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func mustCompileSchema(t *testing.T, schema string) *Schema {
+	t.Helper()
+	s, err := CompileSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+	return s
+}
+
+func violationPaths(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var verr *SchemaValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *SchemaValidationError, got %v", err)
+	}
+	out := make(map[string]string, len(verr.Violations))
+	for _, v := range verr.Violations {
+		out[v.Path] = v.Message
+	}
+	return out
+}
+
+func TestSchemaValidateReportsPointerPaths(t *testing.T) {
+	s := mustCompileSchema(t, `{
+		"type": "object",
+		"required": ["query", "options"],
+		"additionalProperties": false,
+		"properties": {
+			"query": {"type": "string", "minLength": 3},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 50},
+			"mode": {"enum": ["fast", "thorough"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"filter": {
+				"type": "object",
+				"required": ["field"],
+				"properties": {"field": {"type": "string"}, "a/b": {"type": "boolean"}}
+			}
+		}
+	}`)
+	err := s.Validate(map[string]any{
+		"query":  "hi",
+		"limit":  2.5,
+		"mode":   "slow",
+		"tags":   []any{"x", 3, "x"},
+		"filter": map[string]any{"a/b": "yes"},
+		"extra":  true,
+	})
+	got := violationPaths(t, err)
+	want := []string{
+		"/options", "/query", "/limit", "/mode", "/tags/1", "/tags/2",
+		"/filter/field", "/filter/a~1b", "/extra",
+	}
+	for _, path := range want {
+		if _, ok := got[path]; !ok {
+			t.Errorf("missing violation at %s; got %v", path, got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d violations, got %v", len(want), got)
+	}
+	if !strings.Contains(err.Error(), "/options: required property is missing") {
+		t.Errorf("unexpected error text: %v", err)
+	}
+}
+
+func TestSchemaValidateAcceptsValidInstance(t *testing.T) {
+	s := mustCompileSchema(t, `{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"count": {"type": ["integer", "null"], "multipleOf": 2},
+			"items": {"type": "array", "minItems": 1, "prefixItems": [{"const": "head"}], "items": {"type": "number"}}
+		}
+	}`)
+	err := s.Validate(map[string]any{
+		"id":    "123e4567-e89b-12d3-a456-426614174000",
+		"count": 4,
+		"items": []any{"head", 1.5, 2},
+	})
+	if err != nil {
+		t.Fatalf("expected valid instance, got %v", err)
+	}
+	if err := s.Validate(map[string]any{"id": "123e4567-e89b-12d3-a456-426614174000", "count": nil}); err != nil {
+		t.Fatalf("expected null count to be valid, got %v", err)
+	}
+}
+
+func TestSchemaValidateRootTypeMismatch(t *testing.T) {
+	s := mustCompileSchema(t, `{"type": "object"}`)
+	err := s.Validate([]any{1})
+	got := violationPaths(t, err)
+	if got[""] != "expected object, got array" {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+	if !strings.HasPrefix(err.Error(), "(root): ") {
+		t.Fatalf("expected root path in error, got %v", err)
+	}
+}
+
+func TestSchemaValidateFormats(t *testing.T) {
+	cases := []struct {
+		format string
+		valid  string
+		bad    string
+	}{
+		{"date-time", "2026-01-02T15:04:05Z", "2026-01-02 15:04"},
+		{"date", "2026-01-02", "2026-13-02"},
+		{"time", "15:04:05Z", "25:00:00Z"},
+		{"email", "dev@example.com", "not-an-email"},
+		{"uri", "https://example.com/a", "relative/path"},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", "123"},
+		{"ipv4", "192.168.0.1", "::1"},
+		{"ipv6", "::1", "192.168.0.1"},
+		{"hostname", "api.example.com", "-bad-.com"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			s := mustCompileSchema(t, `{"type": "string", "format": "`+tc.format+`"}`)
+			if err := s.Validate(tc.valid); err != nil {
+				t.Errorf("%q should be a valid %s: %v", tc.valid, tc.format, err)
+			}
+			if err := s.Validate(tc.bad); err == nil {
+				t.Errorf("%q should not be a valid %s", tc.bad, tc.format)
+			}
+		})
+	}
+	s := mustCompileSchema(t, `{"type": "string", "format": "custom-thing"}`)
+	if err := s.Validate("anything"); err != nil {
+		t.Fatalf("unknown formats should be ignored, got %v", err)
+	}
+}
+
+func TestSchemaValidateCombinators(t *testing.T) {
+	s := mustCompileSchema(t, `{
+		"properties": {
+			"any": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"one": {"oneOf": [{"type": "number"}, {"type": "integer"}]},
+			"all": {"allOf": [{"minLength": 2}, {"pattern": "^a"}]},
+			"not": {"not": {"type": "null"}}
+		}
+	}`)
+	if err := s.Validate(map[string]any{"any": 1, "one": 1.5, "all": "ab", "not": 1}); err != nil {
+		t.Fatalf("expected valid instance, got %v", err)
+	}
+	got := violationPaths(t, s.Validate(map[string]any{"any": true, "one": 2, "all": "b", "not": nil}))
+	for _, path := range []string{"/any", "/one", "/all", "/not"} {
+		if _, ok := got[path]; !ok {
+			t.Errorf("missing violation at %s; got %v", path, got)
+		}
+	}
+}
+
+func TestSchemaLocalRefs(t *testing.T) {
+	s := mustCompileSchema(t, `{
+		"type": "object",
+		"properties": {"node": {"$ref": "#/$defs/node"}},
+		"$defs": {
+			"node": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`)
+	err := s.Validate(map[string]any{
+		"node": map[string]any{
+			"name":     "root",
+			"children": []any{map[string]any{"name": "a"}, map[string]any{"children": []any{}}},
+		},
+	})
+	got := violationPaths(t, err)
+	if _, ok := got["/node/children/1/name"]; !ok || len(got) != 1 {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+}
+
+func TestCompileSchemaRejectsInvalidSchemas(t *testing.T) {
+	cases := map[string]string{
+		"unknown type":   `{"type": "thing"}`,
+		"bad pattern":    `{"properties": {"a": {"pattern": "("}}}`,
+		"missing ref":    `{"$ref": "#/$defs/missing"}`,
+		"file ref":       `{"$ref": "other.json"}`,
+		"non-object":     `{"properties": {"a": 1}}`,
+		"malformed json": `{`,
+	}
+	for name, schema := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := CompileSchema([]byte(schema)); err == nil {
+				t.Fatalf("expected %s to be rejected", name)
+			}
+		})
+	}
+}
+
+func TestLoadSchemaResolvesFileRefs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(rel, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("schema.input.json", `{"type": "object", "properties": {"author": {"$ref": "schemas/common.json#/$defs/person"}}}`)
+	writeFile("schemas/common.json", `{"$defs": {"person": {"type": "object", "required": ["email"], "properties": {"email": {"$ref": "email.json"}}}}}`)
+	writeFile("schemas/email.json", `{"type": "string", "format": "email"}`)
+
+	s, err := LoadSchema(dir, "schema.input.json")
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	if err := s.Validate(map[string]any{"author": map[string]any{"email": "dev@example.com"}}); err != nil {
+		t.Fatalf("expected valid instance, got %v", err)
+	}
+	got := violationPaths(t, s.Validate(map[string]any{"author": map[string]any{"email": "nope"}}))
+	if _, ok := got["/author/email"]; !ok {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+}
+
+func TestLoadSchemaChecksReferencedDocuments(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.input.json": `{"properties": {"a": {"$ref": "common.json#/$defs/a"}}}`,
+		"common.json":       `{"$defs": {"a": {"$ref": "leaf.json"}}}`,
+		"leaf.json":         `{"type": "thing"}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := LoadSchema(dir, "schema.input.json")
+	if err == nil || !strings.Contains(err.Error(), `leaf.json#/type: unknown type "thing"`) {
+		t.Fatalf("expected the transitively referenced schema to be checked, got %v", err)
+	}
+}
+
+func TestSchemaValidateConcurrentFileRefs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.input.json": `{"properties": {"a": {"$ref": "common.json#/$defs/a"}}}`,
+		"common.json":       `{"$defs": {"a": {"$ref": "leaf.json"}}}`,
+		"leaf.json":         `{"type": "string", "pattern": "^x"}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := LoadSchema(dir, "schema.input.json")
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := s.Validate(map[string]any{"a": "xy"}); err != nil {
+				errs <- err
+			}
+			if err := s.Validate(map[string]any{"a": "y"}); err == nil {
+				errs <- errors.New("expected pattern violation")
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestLoadSchemaRejectsRefsOutsideSkillDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "skill")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "outside.json"), []byte(`{"type": "string"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.input.json"), []byte(`{"properties": {"a": {"$ref": "../outside.json"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadSchema(dir, "schema.input.json")
+	if err == nil || !strings.Contains(err.Error(), "outside the skill directory") {
+		t.Fatalf("expected escape to be rejected, got %v", err)
+	}
+}
@@ -2,139 +2,278 @@ package skill
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 )
 
+// Severity is how much a lint finding matters. Only errors make a skill
+// fail lint.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	// SeverityOff disables a rule in .aioslint.yaml.
+	SeverityOff Severity = "off"
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+	return false
+}
+
+// LintFinding is one problem a lint rule found.
+type LintFinding struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// File is the slash-separated path of the offending file relative to
+	// the skill directory, and Line its 1-based line; either is empty when
+	// the rule cannot tell.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	// Fixable reports whether --fix can repair the finding.
+	Fixable bool `json:"fixable,omitempty"`
+	// Fix repairs the finding, when the rule knows how.
+	Fix func() error `json:"-"`
+}
+
 type LintResult struct {
-	Valid  bool
+	Valid bool
+	// Issues holds the messages of the error findings.
 	Issues []string
+	// Findings holds what every enabled rule found, in rule order.
+	Findings []LintFinding
+	// Fixed holds the findings --fix repaired; Findings is what remained
+	// after the fixes.
+	Fixed []LintFinding
 }
 
-// credentialPatterns defines patterns that indicate embedded credentials
-// or secrets in skill files. Skills must declare required connectors and
-// rely on the runtime token store — never embed raw credentials.
-var credentialPatterns = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\bapi[_-]?key\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\bsecret[_-]?key\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\bclient[_-]?secret\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\baccess[_-]?token\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\bprivate[_-]?key\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\bpassword\s*[:=]\s*\S+`),
-	regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9\-._~+/]+=*`),
-	regexp.MustCompile(`(?i)\bsk-[A-Za-z0-9]{20,}`),
-	regexp.MustCompile(`(?i)\bghp_[A-Za-z0-9]{36,}`),
-	regexp.MustCompile(`(?i)\bAIza[A-Za-z0-9\-_]{30,}`),
+// LintContext is what a lint rule inspects.
+type LintContext struct {
+	Dir  string
+	Spec SkillSpec
+	// Executor is LintOptions.Executor.
+	Executor *Executor
 }
 
-func LintSkillDir(skillDir string) (LintResult, error) {
-	spec, err := LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
-	if err != nil {
-		return LintResult{}, err
+// LintRule is one check of LintSkillDir.
+type LintRule struct {
+	// ID names the rule in findings and in .aioslint.yaml, such as
+	// "fixture-missing-expected".
+	ID          string
+	Description string
+	// Severity applies to the rule's findings unless .aioslint.yaml
+	// overrides it.
+	Severity Severity
+	Check    func(lc LintContext) []LintFinding
+}
+
+// LintOptions tunes Linter.Lint.
+type LintOptions struct {
+	// Fix applies the fixes of fixable findings and lints again.
+	Fix bool
+	// Config overrides rule severities; nil loads the .aioslint.yaml
+	// nearest the skill directory.
+	Config *LintConfig
+	// Executor runs fixtures for fixes that record a skill's output. Without
+	// one, or when it has no handler for the skill, those findings are not
+	// fixable.
+	Executor *Executor
+}
+
+// Linter runs lint rules over skill directories. It is safe for concurrent
+// use.
+type Linter struct {
+	mu    sync.RWMutex
+	rules []LintRule
+}
+
+// NewLinter returns a Linter holding the built-in rules.
+func NewLinter() *Linter {
+	return &Linter{rules: builtinLintRules()}
+}
+
+// Register adds a rule, or replaces the rule with the same ID in place.
+func (l *Linter) Register(rule LintRule) error {
+	if !nameRe.MatchString(rule.ID) {
+		return fmt.Errorf("invalid lint rule id %q", rule.ID)
 	}
-	issues := []string{}
-	if err := ValidateSkillSpec(skillDir, spec); err != nil {
-		issues = append(issues, err.Error())
+	if rule.Check == nil {
+		return fmt.Errorf("lint rule %s has no check", rule.ID)
 	}
-	if _, err := os.Stat(filepath.Join(skillDir, "prompt.md")); err != nil {
-		issues = append(issues, "missing prompt.md")
+	if rule.Severity == "" {
+		rule.Severity = SeverityError
 	}
-	if _, err := os.Stat(filepath.Join(skillDir, "tests")); err != nil {
-		issues = append(issues, "missing tests directory")
-	} else {
-		entries, err := os.ReadDir(filepath.Join(skillDir, "tests"))
+	if !rule.Severity.valid() {
+		return fmt.Errorf("lint rule %s has invalid severity %q", rule.ID, rule.Severity)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.rules {
+		if l.rules[i].ID == rule.ID {
+			l.rules[i] = rule
+			return nil
+		}
+	}
+	l.rules = append(l.rules, rule)
+	return nil
+}
+
+// Rules returns the registered rules in the order they run.
+func (l *Linter) Rules() []LintRule {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]LintRule(nil), l.rules...)
+}
+
+// LintSkillDir lints skillDir with the built-in rules and the nearest
+// .aioslint.yaml.
+func LintSkillDir(skillDir string) (LintResult, error) {
+	return NewLinter().Lint(skillDir, LintOptions{})
+}
+
+// Lint runs every enabled rule over skillDir. With opts.Fix it applies the
+// fixes of the fixable findings, then lints again so the result reflects
+// the repaired skill.
+func (l *Linter) Lint(skillDir string, opts LintOptions) (LintResult, error) {
+	config := opts.Config
+	if config == nil {
+		loaded, err := LoadLintConfig(skillDir)
 		if err != nil {
-			issues = append(issues, "cannot read tests directory")
-		} else {
-			fixtures := map[string]bool{}
-			expecteds := map[string]bool{}
-			for _, e := range entries {
-				name := e.Name()
-				switch {
-				case strings.HasPrefix(name, "fixture_") && strings.HasSuffix(name, ".json"):
-					fixtures[strings.TrimPrefix(name, "fixture_")] = true
-				case strings.HasPrefix(name, "expected_") && strings.HasSuffix(name, ".json"):
-					expecteds[strings.TrimPrefix(name, "expected_")] = true
-				}
+			return LintResult{}, err
+		}
+		config = &loaded
+	}
+	rules := l.Rules()
+	if err := config.check(rules); err != nil {
+		return LintResult{}, err
+	}
+
+	findings, err := runLintRules(skillDir, rules, config, opts.Executor)
+	if err != nil {
+		return LintResult{}, err
+	}
+	fixed := []LintFinding{}
+	if opts.Fix {
+		for _, f := range findings {
+			if f.Fix == nil {
+				continue
 			}
-			for suffix := range fixtures {
-				if !expecteds[suffix] {
-					issues = append(issues, "missing expected_"+suffix)
-				}
+			if err := f.Fix(); err != nil {
+				return LintResult{}, fmt.Errorf("fix %s: %w", f.RuleID, err)
 			}
-			for suffix := range expecteds {
-				if !fixtures[suffix] {
-					issues = append(issues, "missing fixture_"+suffix)
-				}
+			fixed = append(fixed, f)
+		}
+		if len(fixed) > 0 {
+			if findings, err = runLintRules(skillDir, rules, config, opts.Executor); err != nil {
+				return LintResult{}, err
 			}
 		}
 	}
 
-	// Scan all skill files for embedded credentials.
-	issues = append(issues, scanForCredentials(skillDir)...)
-
-	return LintResult{Valid: len(issues) == 0, Issues: issues}, nil
+	res := LintResult{Issues: []string{}, Findings: append([]LintFinding{}, findings...), Fixed: fixed}
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			res.Issues = append(res.Issues, f.Message)
+		}
+	}
+	res.Valid = len(res.Issues) == 0
+	return res, nil
 }
 
-// scanForCredentials inspects all text files in the skill directory for
-// credential patterns. Skills must never embed raw tokens, OAuth client
-// secrets, or private keys — connectors provide credentials at runtime.
-func scanForCredentials(skillDir string) []string {
-	var issues []string
-	scanFiles := []string{
-		"skill.yaml",
-		"prompt.md",
-	}
-	// Include schema files and any other yaml/json/md files at root level.
-	entries, err := os.ReadDir(skillDir)
+func runLintRules(skillDir string, rules []LintRule, config *LintConfig, exec *Executor) ([]LintFinding, error) {
+	spec, err := LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 	if err != nil {
-		return issues
+		return nil, err
 	}
-	for _, e := range entries {
-		if e.IsDir() {
+	lc := LintContext{Dir: skillDir, Spec: spec, Executor: exec}
+	var findings []LintFinding
+	for _, rule := range rules {
+		severity := config.severity(rule)
+		if severity == SeverityOff {
 			continue
 		}
-		name := e.Name()
-		if strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".md") {
-			found := false
-			for _, f := range scanFiles {
-				if f == name {
-					found = true
-					break
-				}
-			}
-			if !found {
-				scanFiles = append(scanFiles, name)
-			}
+		for _, f := range rule.Check(lc) {
+			f.RuleID = rule.ID
+			f.Severity = severity
+			f.Fixable = f.Fix != nil
+			findings = append(findings, f)
 		}
 	}
-	// Also scan test fixtures.
-	testsDir := filepath.Join(skillDir, "tests")
-	if testEntries, err := os.ReadDir(testsDir); err == nil {
-		for _, e := range testEntries {
-			if !e.IsDir() {
-				scanFiles = append(scanFiles, filepath.Join("tests", e.Name()))
-			}
+	return findings, nil
+}
+
+// schemaDeclares reports whether node, a schema within root, describes the
+// property path. Properties may come from properties, patternProperties,
+// local $refs or the branches of allOf, anyOf and oneOf. A schema that does
+// not describe an object's shape at all, or refers to another file, is given
+// the benefit of the doubt.
+func schemaDeclares(root, node any, path []string, depth int) bool {
+	if len(path) == 0 || depth > maxSchemaDepth {
+		return true
+	}
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return node == true
+	}
+	described := false
+	if ref, ok := schema["$ref"].(string); ok {
+		if !strings.HasPrefix(ref, "#") {
+			return true
+		}
+		described = true
+		if target, err := resolvePointer(root, ref[1:]); err == nil && schemaDeclares(root, target, path, depth+1) {
+			return true
 		}
 	}
-
-	for _, relPath := range scanFiles {
-		absPath := filepath.Join(skillDir, relPath)
-		data, err := os.ReadFile(absPath)
-		if err != nil {
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, ok := schema[key].([]any)
+		if !ok {
 			continue
 		}
-		content := string(data)
-		for _, pattern := range credentialPatterns {
-			if pattern.MatchString(content) {
-				issues = append(issues, fmt.Sprintf("embedded credential detected in %s: pattern %s", relPath, pattern.String()))
-				break // One issue per file is sufficient.
+		described = true
+		for _, branch := range branches {
+			if schemaDeclares(root, branch, path, depth+1) {
+				return true
+			}
+		}
+	}
+	if props, ok := schema["properties"].(map[string]any); ok {
+		described = true
+		if child, ok := props[path[0]]; ok {
+			return schemaDeclares(root, child, path[1:], depth+1)
+		}
+	}
+	if patterns, ok := schema["patternProperties"].(map[string]any); ok {
+		described = true
+		for pattern, child := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(path[0]) {
+				return schemaDeclares(root, child, path[1:], depth+1)
+			}
+		}
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		return schemaDeclares(root, additional, path[1:], depth+1)
+	}
+	if described {
+		return false
+	}
+	if t, ok := schema["type"]; ok {
+		for _, name := range typeNames(t) {
+			if name == "object" {
+				return true
 			}
 		}
+		return false
 	}
-	return issues
+	return true
 }
 
 func (r LintResult) Err() error {
@@ -0,0 +1,93 @@
+package skill
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintConfigFile is the file lint reads rule settings from. The nearest one
+// in the skill directory or its parents applies, up to the repository root.
+const LintConfigFile = ".aioslint.yaml"
+
+// LintConfig is the content of a .aioslint.yaml:
+//
+//	rules:
+//	  fixture-missing-expected: warning
+//	  embedded-credential: off
+type LintConfig struct {
+	// Rules overrides the severity of rules by ID; "off" disables a rule.
+	Rules map[string]Severity `yaml:"rules"`
+	// Path is the file the config came from; empty when none was found.
+	Path string `yaml:"-"`
+}
+
+// LoadLintConfig reads the .aioslint.yaml nearest skillDir, looking in
+// skillDir and then its parents, stopping after a directory holding .git.
+// It returns an empty config when there is none.
+func LoadLintConfig(skillDir string) (LintConfig, error) {
+	dir, err := filepath.Abs(skillDir)
+	if err != nil {
+		return LintConfig{}, err
+	}
+	for {
+		path := filepath.Join(dir, LintConfigFile)
+		// #nosec G304 -- path is a fixed file name in an ancestor of the skill.
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var config LintConfig
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return LintConfig{}, fmt.Errorf("parse %s: %w", path, err)
+			}
+			config.Path = path
+			return config, nil
+		}
+		if !os.IsNotExist(err) {
+			return LintConfig{}, fmt.Errorf("read %s: %w", path, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return LintConfig{}, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return LintConfig{}, nil
+		}
+		dir = parent
+	}
+}
+
+// check rejects settings for unknown rules and invalid severities.
+func (c *LintConfig) check(rules []LintRule) error {
+	source := c.Path
+	if source == "" {
+		source = "lint config"
+	}
+	known := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+	ids := make([]string, 0, len(c.Rules))
+	for id := range c.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			return fmt.Errorf("%s: unknown lint rule %q", source, id)
+		}
+		if severity := c.Rules[id]; !severity.valid() {
+			return fmt.Errorf("%s: rule %s has invalid severity %q (want error, warning, info or off)", source, id, severity)
+		}
+	}
+	return nil
+}
+
+func (c *LintConfig) severity(rule LintRule) Severity {
+	if severity, ok := c.Rules[rule.ID]; ok {
+		return severity
+	}
+	return rule.Severity
+}
@@ -0,0 +1,373 @@
+package skill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/secrets"
+)
+
+// looseVersionRe matches versions the version-semver fix can normalize,
+// such as "v1.2" or "01.2.3-rc.1".
+var looseVersionRe = regexp.MustCompile(`^[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?([-+].*)?$`)
+
+func builtinLintRules() []LintRule {
+	return []LintRule{
+		{
+			ID:          "spec-invalid",
+			Description: "skill.yaml must describe a valid skill with readable input and output schemas.",
+			Severity:    SeverityError,
+			Check:       checkSpec,
+		},
+		{
+			ID:          "version-semver",
+			Description: "version must be a semantic version such as 1.2.0.",
+			Severity:    SeverityError,
+			Check:       checkVersion,
+		},
+		{
+			ID:          "prompt-missing",
+			Description: "A skill needs a prompt.md.",
+			Severity:    SeverityError,
+			Check:       checkPromptExists,
+		},
+		{
+			ID:          "prompt-template",
+			Description: "prompt.md and the partials it includes must be valid templates.",
+			Severity:    SeverityError,
+			Check:       checkPromptTemplate,
+		},
+		{
+			ID:          "prompt-undeclared-input",
+			Description: "Every input the prompt references must be declared by the input schema.",
+			Severity:    SeverityError,
+			Check:       checkPromptInputs,
+		},
+		{
+			ID:          "tests-missing",
+			Description: "A skill needs a tests directory.",
+			Severity:    SeverityError,
+			Check:       checkTestsDir,
+		},
+		{
+			ID:          "fixture-missing-expected",
+			Description: "Every tests/fixture_<name>.json needs a tests/expected_<name>.json.",
+			Severity:    SeverityError,
+			Check:       checkFixtureExpected,
+		},
+		{
+			ID:          "expected-missing-fixture",
+			Description: "Every tests/expected_<name>.json needs a tests/fixture_<name>.json.",
+			Severity:    SeverityError,
+			Check:       checkExpectedFixture,
+		},
+		{
+			ID:          "embedded-credential",
+			Description: "Skill files must not embed credentials; connectors provide them at runtime. Accepted findings are listed in .aios-secrets-baseline.json.",
+			Severity:    SeverityError,
+			Check:       checkCredentials,
+		},
+	}
+}
+
+func checkSpec(lc LintContext) []LintFinding {
+	spec := lc.Spec
+	if spec.Version != "" && !semverRe.MatchString(spec.Version) {
+		// version-semver reports the version; validate the rest.
+		spec.Version = "0.0.0"
+	}
+	if err := ValidateSkillSpec(lc.Dir, spec); err != nil {
+		return []LintFinding{{Message: err.Error(), File: "skill.yaml"}}
+	}
+	return nil
+}
+
+func checkVersion(lc LintContext) []LintFinding {
+	version := lc.Spec.Version
+	if version == "" || semverRe.MatchString(version) {
+		return nil
+	}
+	specPath := filepath.Join(lc.Dir, "skill.yaml")
+	// #nosec G304 -- path is the skill.yaml of the skill being linted.
+	data, _ := os.ReadFile(specPath)
+	f := LintFinding{
+		Message: fmt.Sprintf("version %q is not valid semver", version),
+		File:    "skill.yaml",
+		Line:    yamlKeyLine(string(data), "version"),
+	}
+	if normalized, ok := normalizeVersion(version); ok {
+		f.Message += fmt.Sprintf("; use %q", normalized)
+		f.Fix = func() error {
+			return setYAMLKey(specPath, "version", normalized)
+		}
+	}
+	return []LintFinding{f}
+}
+
+func checkPromptExists(lc LintContext) []LintFinding {
+	if _, err := os.Stat(filepath.Join(lc.Dir, "prompt.md")); err != nil {
+		return []LintFinding{{Message: "missing prompt.md"}}
+	}
+	return nil
+}
+
+// checkPromptTemplate checks that prompt.md and the partials it includes
+// parse.
+func checkPromptTemplate(lc LintContext) []LintFinding {
+	prompt, err := LoadProgressivePrompt(filepath.Join(lc.Dir, "prompt.md"))
+	if err != nil {
+		return nil
+	}
+	if _, err := prompt.InputReferences(); err != nil {
+		f := LintFinding{Message: err.Error(), File: "prompt.md"}
+		var terr *TemplateError
+		if errors.As(err, &terr) {
+			f.File = lintRelPath(lc.Dir, terr.Source)
+			f.Line = terr.Line
+		}
+		return []LintFinding{f}
+	}
+	return nil
+}
+
+// checkPromptInputs checks that every input value prompt.md and its partials
+// reference is declared by the input schema.
+func checkPromptInputs(lc LintContext) []LintFinding {
+	promptPath := filepath.Join(lc.Dir, "prompt.md")
+	prompt, err := LoadProgressivePrompt(promptPath)
+	if err != nil {
+		return nil
+	}
+	refs, err := prompt.InputReferences()
+	if err != nil || len(refs) == 0 || lc.Spec.Inputs.Schema == "" {
+		return nil
+	}
+	// #nosec G304 -- path is resolved from the validated skill spec.
+	data, err := os.ReadFile(filepath.Join(lc.Dir, lc.Spec.Inputs.Schema))
+	if err != nil {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	// #nosec G304 -- path is the prompt.md of the skill being linted.
+	text, _ := os.ReadFile(promptPath)
+	var findings []LintFinding
+	for _, ref := range refs {
+		if !schemaDeclares(doc, doc, strings.Split(ref, ".")[1:], 0) {
+			findings = append(findings, LintFinding{
+				Message: fmt.Sprintf("prompt.md references %s, which %s does not declare", ref, lc.Spec.Inputs.Schema),
+				File:    "prompt.md",
+				Line:    refLine(string(text), ref),
+			})
+		}
+	}
+	return findings
+}
+
+func checkTestsDir(lc LintContext) []LintFinding {
+	testsDir := filepath.Join(lc.Dir, "tests")
+	if _, err := os.Stat(testsDir); err != nil {
+		return []LintFinding{{
+			Message: "missing tests directory",
+			Fix: func() error {
+				return os.MkdirAll(testsDir, 0o750)
+			},
+		}}
+	}
+	if _, err := os.ReadDir(testsDir); err != nil {
+		return []LintFinding{{Message: "cannot read tests directory", File: "tests"}}
+	}
+	return nil
+}
+
+// fixturePairs returns the suffixes of the tests/fixture_*.json and
+// tests/expected_*.json files, such as "01.json".
+func fixturePairs(skillDir string) (fixtures, expecteds map[string]bool) {
+	fixtures = map[string]bool{}
+	expecteds = map[string]bool{}
+	entries, err := os.ReadDir(filepath.Join(skillDir, "tests"))
+	if err != nil {
+		return fixtures, expecteds
+	}
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasPrefix(name, "fixture_") && strings.HasSuffix(name, ".json"):
+			fixtures[strings.TrimPrefix(name, "fixture_")] = true
+		case strings.HasPrefix(name, "expected_") && strings.HasSuffix(name, ".json"):
+			expecteds[strings.TrimPrefix(name, "expected_")] = true
+		}
+	}
+	return fixtures, expecteds
+}
+
+// checkFixtureExpected reports fixtures without an expected file. When the
+// lint executor has a handler for the skill, the fix records its output for
+// the fixture, as skills test --update would.
+func checkFixtureExpected(lc LintContext) []LintFinding {
+	fixtures, expecteds := fixturePairs(lc.Dir)
+	canRecord := lc.Executor != nil && lc.Executor.handler(lc.Spec.ID) != nil
+	var findings []LintFinding
+	for _, suffix := range sortedKeys(fixtures) {
+		if expecteds[suffix] {
+			continue
+		}
+		name := "fixture_" + suffix
+		finding := LintFinding{
+			Message: "missing expected_" + suffix,
+			File:    "tests/" + name,
+		}
+		if canRecord {
+			finding.Fix = func() error {
+				return snapshotMissingExpected(lc.Dir, lc.Executor, name)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func checkExpectedFixture(lc LintContext) []LintFinding {
+	fixtures, expecteds := fixturePairs(lc.Dir)
+	var findings []LintFinding
+	for _, suffix := range sortedKeys(expecteds) {
+		if !fixtures[suffix] {
+			findings = append(findings, LintFinding{
+				Message: "missing fixture_" + suffix,
+				File:    "tests/expected_" + suffix,
+			})
+		}
+	}
+	return findings
+}
+
+// snapshotMissingExpected writes the expected file of the fixture file name
+// from the skill's output on exec.
+func snapshotMissingExpected(skillDir string, exec *Executor, name string) error {
+	suite, err := loadFixtureSuite(skillDir, exec)
+	if err != nil {
+		return err
+	}
+	for _, c := range suite.cases {
+		if c.name != name {
+			continue
+		}
+		if res := snapshotFixture(suite.artifact, exec, c, true); res.Error != "" {
+			return fmt.Errorf("%s: %s", name, res.Error)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s not found", name)
+}
+
+// checkCredentials scans every file of the skill for secrets the
+// .aios-secrets-baseline.json does not accept. Skills must never embed raw
+// tokens, OAuth client secrets, or private keys — connectors provide
+// credentials at runtime.
+func checkCredentials(lc LintContext) []LintFinding {
+	report, err := secrets.Scan(lc.Dir, "")
+	if err != nil {
+		return []LintFinding{{Message: err.Error()}}
+	}
+	findings := make([]LintFinding, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		findings = append(findings, LintFinding{
+			Message: fmt.Sprintf("embedded credential detected in %s: %s (%s)", f.File, f.Detector, f.Excerpt),
+			File:    f.File,
+			Line:    f.Line,
+		})
+	}
+	return findings
+}
+
+// normalizeVersion turns a loose version such as "v1.2" into semver,
+// dropping leading zeros and filling in missing components.
+func normalizeVersion(v string) (string, bool) {
+	m := looseVersionRe.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return "", false
+	}
+	parts := make([]string, 3)
+	for i, p := range m[1:4] {
+		n, err := strconv.Atoi(p)
+		if p != "" && err != nil {
+			return "", false
+		}
+		parts[i] = strconv.Itoa(n)
+	}
+	out := strings.Join(parts, ".") + m[4]
+	if !semverRe.MatchString(out) {
+		return "", false
+	}
+	return out, true
+}
+
+// yamlKeyLine returns the 1-based line of a top-level key in a YAML
+// document, or 0 when it is absent.
+func yamlKeyLine(doc, key string) int {
+	for i, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// setYAMLKey rewrites the value of a top-level scalar key in the YAML file
+// at path, keeping the rest of the file, including comments, as it is.
+func setYAMLKey(path, key, value string) error {
+	// #nosec G304 -- path is the skill.yaml of the skill being linted.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	n := yamlKeyLine(string(data), key)
+	if n == 0 {
+		return fmt.Errorf("%s has no %s", filepath.Base(path), key)
+	}
+	comment := ""
+	if i := strings.Index(lines[n-1], " #"); i >= 0 {
+		comment = lines[n-1][i:]
+	}
+	lines[n-1] = key + ": " + value + comment
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600)
+}
+
+// refLine returns the 1-based line of the first use of an input reference
+// in a template, or 0 when it is not found.
+func refLine(text, ref string) int {
+	re := regexp.MustCompile(regexp.QuoteMeta(ref) + `(?:[^\w.]|$)`)
+	loc := re.FindStringIndex(text)
+	if loc == nil {
+		return 0
+	}
+	return strings.Count(text[:loc[0]], "\n") + 1
+}
+
+// lintRelPath returns path relative to skillDir in slash form, or the base
+// name of path when it lies elsewhere. A bare file name is taken to be in
+// skillDir.
+func lintRelPath(skillDir, path string) string {
+	if filepath.Base(path) == path {
+		return path
+	}
+	absDir, errDir := filepath.Abs(skillDir)
+	absPath, errPath := filepath.Abs(path)
+	if errDir != nil || errPath != nil {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
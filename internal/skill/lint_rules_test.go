@@ -0,0 +1,106 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLintFindingsCarryRulesAndLocations(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"prompt.md":              "# prompt\n\nAnswer {{ input.q }}.\nMention {{ input.topic }} too.\n",
+		"tests/expected_02.json": `{}`,
+		"tests/fixture_01.json":  "{\n  \"q\": \"x\",\n  \"note\": \"api_key=abc123\"\n}\n",
+	})
+
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	var got []string
+	for _, f := range res.Findings {
+		got = append(got, f.RuleID+" "+f.File+":"+strconv.Itoa(f.Line)+" "+string(f.Severity))
+	}
+	want := []string{
+		"prompt-undeclared-input prompt.md:4 error",
+		"fixture-missing-expected tests/fixture_01.json:0 error",
+		"expected-missing-fixture tests/expected_02.json:0 error",
+		"embedded-credential tests/fixture_01.json:3 error",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	// Without an executor nothing can record the missing expected output.
+	if res.Valid || len(res.Issues) != 4 || res.Findings[1].Fixable || res.Findings[2].Fixable {
+		t.Fatalf("unexpected result %#v", res)
+	}
+}
+
+func TestLintFixRepairsFixableFindings(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"skill.yaml":            "id: snap-skill\nversion: v1.2 # bumped by hand\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":             "# prompt",
+		"tests/fixture_01.json": `{"q":"x"}`,
+	})
+
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if res.Valid || len(res.Findings) != 2 || res.Findings[0].Message != `version "v1.2" is not valid semver; use "1.2.0"` || res.Findings[0].Line != 2 {
+		t.Fatalf("unexpected findings %#v", res.Findings)
+	}
+
+	res, err = NewLinter().Lint(dir, LintOptions{Fix: true, Executor: snapshotExecutor()})
+	if err != nil {
+		t.Fatalf("lint --fix: %v", err)
+	}
+	if !res.Valid || len(res.Findings) != 0 || len(res.Fixed) != 2 {
+		t.Fatalf("expected both findings fixed, got %#v", res)
+	}
+	spec, _ := os.ReadFile(filepath.Join(dir, "skill.yaml"))
+	if !strings.Contains(string(spec), "version: 1.2.0 # bumped by hand\n") {
+		t.Fatalf("unexpected skill.yaml:\n%s", spec)
+	}
+	expected, err := os.ReadFile(filepath.Join(dir, "tests", "expected_01.json"))
+	if err != nil || !strings.Contains(string(expected), `"answer": "re: x"`) {
+		t.Fatalf("expected recorded output, got %q (%v)", expected, err)
+	}
+}
+
+func TestLintFixCreatesTestsDir(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{"prompt.md": "# prompt"})
+	if err := os.Remove(filepath.Join(dir, "tests")); err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewLinter().Lint(dir, LintOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("lint --fix: %v", err)
+	}
+	if !res.Valid || len(res.Fixed) != 1 || res.Fixed[0].RuleID != "tests-missing" {
+		t.Fatalf("unexpected result %#v", res)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "tests")); err != nil || !info.IsDir() {
+		t.Fatalf("expected tests directory, got %v", err)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	for in, want := range map[string]string{
+		"v1.2.3":       "1.2.3",
+		"1.2":          "1.2.0",
+		"V2":           "2.0.0",
+		"01.02.3-rc.1": "1.2.3-rc.1",
+		"1.0+build.5":  "1.0.0+build.5",
+		"latest":       "",
+		"1.2.3.4":      "",
+		"1.0-":         "",
+	} {
+		got, ok := normalizeVersion(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("normalizeVersion(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
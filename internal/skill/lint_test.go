@@ -287,3 +287,135 @@ func setupValidSkillDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestLintSkillDirRejectsUnknownGuardrails(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"skill.yaml": "id: snap-skill\nversion: 0.1.0\nguardrails: [no-pii, no-swearing]\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"prompt.md":  "# prompt",
+	})
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if res.Valid || len(res.Issues) != 1 || res.Issues[0] != `unknown guardrail "no-swearing"` {
+		t.Fatalf("expected unknown guardrail issue, got %#v", res.Issues)
+	}
+}
+
+func TestLintSkillDirChecksPromptTemplate(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"schema.input.json": `{"type":"object","properties":{"q":{"type":"string"},"user":{"$ref":"#/$defs/user"},"meta":{"type":"object"}},"$defs":{"user":{"type":"object","properties":{"name":{"type":"string"}}}}}`,
+		"prompt.md":         "{{ input.q }} {{ input.user.name }} {{ input.meta.anything }}\n{{#if input.limit}}{{ input.user.email }}{{/if}}\n",
+	})
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	want := []string{
+		"prompt.md references input.limit, which schema.input.json does not declare",
+		"prompt.md references input.user.email, which schema.input.json does not declare",
+	}
+	if res.Valid || strings.Join(res.Issues, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected undeclared input issues, got %#v", res.Issues)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "prompt.md"), []byte("{{#if input.q}}\n{{> missing }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if res.Valid || len(res.Issues) != 1 || !strings.Contains(res.Issues[0], "prompt.md:1: {{#if}} is not closed") {
+		t.Fatalf("expected template syntax issue, got %#v", res.Issues)
+	}
+}
+
+func TestLintConfigOverridesSeverities(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"prompt.md":             "# prompt",
+		"tests/fixture_01.json": `{"q":"x"}`,
+	})
+	if err := os.WriteFile(filepath.Join(dir, LintConfigFile), []byte("rules:\n  fixture-missing-expected: warning\n  embedded-credential: off\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("password: hunter2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if !res.Valid || len(res.Issues) != 0 {
+		t.Fatalf("expected warnings only, got %#v", res.Issues)
+	}
+	if len(res.Findings) != 1 || res.Findings[0].RuleID != "fixture-missing-expected" || res.Findings[0].Severity != SeverityWarning {
+		t.Fatalf("unexpected findings %#v", res.Findings)
+	}
+
+	// The nearest config applies, so a skill in a subdirectory inherits it.
+	nested := filepath.Join(dir, "nested")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadLintConfig(nested)
+	if err != nil || config.Path != filepath.Join(dir, LintConfigFile) {
+		t.Fatalf("expected parent config, got %+v (%v)", config, err)
+	}
+}
+
+func TestLintConfigRejectsUnknownRulesAndSeverities(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{"prompt.md": "# prompt"})
+	for body, want := range map[string]string{
+		"rules:\n  no-such-rule: warning\n": `unknown lint rule "no-such-rule"`,
+		"rules:\n  tests-missing: loud\n":   `rule tests-missing has invalid severity "loud"`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, LintConfigFile), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LintSkillDir(dir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestLinterRegister(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{"prompt.md": "# prompt"})
+	linter := NewLinter()
+	err := linter.Register(LintRule{
+		ID:          "description-required",
+		Description: "skill.yaml should describe the skill.",
+		Severity:    SeverityInfo,
+		Check: func(lc LintContext) []LintFinding {
+			if lc.Spec.Description == "" {
+				return []LintFinding{{Message: "skill has no description", File: "skill.yaml"}}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	res, err := linter.Lint(dir, LintOptions{Config: &LintConfig{}})
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	if !res.Valid || len(res.Findings) != 1 || res.Findings[0].RuleID != "description-required" || res.Findings[0].Severity != SeverityInfo {
+		t.Fatalf("unexpected result %#v", res)
+	}
+
+	if err := linter.Register(LintRule{ID: "Bad Rule", Check: func(LintContext) []LintFinding { return nil }}); err == nil {
+		t.Error("expected invalid rule id to be rejected")
+	}
+	if err := linter.Register(LintRule{ID: "no-check"}); err == nil {
+		t.Error("expected rule without check to be rejected")
+	}
+	before := len(linter.Rules())
+	if err := linter.Register(LintRule{ID: "tests-missing", Severity: SeverityWarning, Check: func(LintContext) []LintFinding { return nil }}); err != nil {
+		t.Fatal(err)
+	}
+	if rules := linter.Rules(); len(rules) != before || rules[5].ID != "tests-missing" || rules[5].Severity != SeverityWarning {
+		t.Fatalf("expected tests-missing to be replaced in place, got %#v", rules)
+	}
+}
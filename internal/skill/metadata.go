@@ -0,0 +1,209 @@
+package skill
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/model"
+	"github.com/felixgeelhaar/aios/internal/policy"
+)
+
+// DefaultAllowedTools are the tools SKILL.md allows when a skill declares
+// none.
+var DefaultAllowedTools = []string{"Read", "Grep", "Glob"}
+
+// ModelPreferences steer model.Router for a skill's executions. Callers that
+// name a policy pack or budget themselves override them.
+type ModelPreferences struct {
+	// PolicyPack is "cost-first", "quality-first" or "balanced".
+	PolicyPack string `yaml:"policy_pack"`
+	// Budget is "low", "normal" or "high".
+	Budget string `yaml:"budget"`
+}
+
+var (
+	// nameRe matches tags and connector names: lowercase words joined by
+	// hyphens.
+	nameRe = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	// spdxIDRe matches one SPDX license identifier, such as "Apache-2.0" or
+	// "LicenseRef-Acme".
+	spdxIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]*\+?$`)
+	// toolRe matches an allowed tool, optionally scoped as in "Bash(git:*)".
+	toolRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(?:\([^(),]+\))?$`)
+)
+
+var modelBudgets = []string{"low", "normal", "high"}
+
+// validateMetadata checks the descriptive and routing fields of spec, looking
+// its guardrails up in guardrails.
+func validateMetadata(spec SkillSpec, guardrails *policy.GuardrailRegistry) error {
+	if spec.License != "" && !validLicense(spec.License) {
+		return fmt.Errorf("license %q is not an SPDX identifier or expression", spec.License)
+	}
+	if spec.Homepage != "" {
+		u, err := url.Parse(spec.Homepage)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("homepage %q must be an http or https URL", spec.Homepage)
+		}
+	}
+	if err := validateNames("tag", spec.Tags, nameRe); err != nil {
+		return err
+	}
+	if err := validateNames("connector", spec.Connectors, nameRe); err != nil {
+		return err
+	}
+	if err := validateNames("allowed tool", spec.AllowedTools, toolRe); err != nil {
+		return err
+	}
+	if err := validateNames("client", spec.Clients, nil); err != nil {
+		return err
+	}
+	if err := validateNames("guardrail", spec.Guardrails, nil); err != nil {
+		return err
+	}
+	if unknown := guardrails.Unknown(spec.Guardrails); len(unknown) > 0 {
+		return fmt.Errorf("unknown guardrail %q", unknown[0])
+	}
+	if spec.MinAIOSVersion != "" && !semverRe.MatchString(strings.TrimPrefix(spec.MinAIOSVersion, "v")) {
+		return fmt.Errorf("min_aios_version %q is not valid semver", spec.MinAIOSVersion)
+	}
+	if pack := spec.Model.PolicyPack; pack != "" {
+		known := false
+		var names []string
+		for _, p := range model.NewRouter().Packs() {
+			known = known || p.Name == pack
+			names = append(names, p.Name)
+		}
+		if !known {
+			return fmt.Errorf("model policy_pack %q is not one of %s", pack, strings.Join(names, ", "))
+		}
+	}
+	if budget := spec.Model.Budget; budget != "" && !containsString(modelBudgets, budget) {
+		return fmt.Errorf("model budget %q is not one of %s", budget, strings.Join(modelBudgets, ", "))
+	}
+	return nil
+}
+
+// validateNames rejects empty and duplicate entries of a list, and entries
+// that do not match re when it is set.
+func validateNames(kind string, names []string, re *regexp.Regexp) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%s names must not be empty", kind)
+		}
+		if re != nil && !re.MatchString(name) {
+			return fmt.Errorf("invalid %s %q", kind, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate %s %q", kind, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validLicense reports whether s is an SPDX identifier or a flat expression
+// of identifiers joined by AND, OR and WITH, optionally parenthesized.
+func validLicense(s string) bool {
+	tokens := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(s))
+	if len(tokens)%2 == 0 {
+		return false
+	}
+	for i, tok := range tokens {
+		if i%2 == 1 {
+			if tok != "AND" && tok != "OR" && tok != "WITH" {
+				return false
+			}
+			continue
+		}
+		if !spdxIDRe.MatchString(tok) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckAIOSVersion reports an error when spec needs a newer aios than
+// current. Development builds whose version is not semver pass.
+func CheckAIOSVersion(spec SkillSpec, current string) error {
+	if spec.MinAIOSVersion == "" {
+		return nil
+	}
+	current = strings.TrimPrefix(current, "v")
+	if !semverRe.MatchString(current) {
+		return nil
+	}
+	if compareSemver(current, strings.TrimPrefix(spec.MinAIOSVersion, "v")) < 0 {
+		return fmt.Errorf("skill %s requires aios %s or newer; this is %s", spec.ID, spec.MinAIOSVersion, current)
+	}
+	return nil
+}
+
+// compareSemver orders two versions matching semverRe by precedence,
+// returning -1, 0 or 1. Pre-release identifiers compare as described by the
+// semver spec; build metadata is ignored.
+func compareSemver(a, b string) int {
+	ma := semverRe.FindStringSubmatch(a)
+	mb := semverRe.FindStringSubmatch(b)
+	for i := 1; i <= 3; i++ {
+		x, _ := strconv.Atoi(ma[i])
+		y, _ := strconv.Atoi(mb[i])
+		if x != y {
+			return cmpInt(x, y)
+		}
+	}
+	switch {
+	case ma[4] == mb[4]:
+		return 0
+	case ma[4] == "":
+		return 1
+	case mb[4] == "":
+		return -1
+	}
+	pa := strings.Split(ma[4], ".")
+	pb := strings.Split(mb[4], ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] == pb[i] {
+			continue
+		}
+		x, errX := strconv.Atoi(pa[i])
+		y, errY := strconv.Atoi(pb[i])
+		switch {
+		case errX == nil && errY == nil:
+			return cmpInt(x, y)
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		case pa[i] < pb[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return cmpInt(len(pa), len(pb))
+}
+
+func cmpInt(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/policy"
+)
+
+func TestValidateMetadataAcceptsFullSpec(t *testing.T) {
+	spec := SkillSpec{
+		Author:         "Platform Team <platform@example.com>",
+		License:        "(Apache-2.0 OR MIT)",
+		Tags:           []string{"planning", "ddd"},
+		Homepage:       "https://example.com/skills/roadmap",
+		AllowedTools:   []string{"Read", "Bash(git:*)"},
+		Connectors:     []string{"google-drive"},
+		Clients:        []string{"claude-code", "cursor"},
+		Guardrails:     []string{"no-pii"},
+		MinAIOSVersion: "v0.1.0",
+		Model:          ModelPreferences{PolicyPack: "quality-first", Budget: "high"},
+	}
+	if err := validateMetadata(spec, policy.NewGuardrailRegistry()); err != nil {
+		t.Fatalf("expected valid metadata, got %v", err)
+	}
+}
+
+func TestValidateMetadataRejectsInvalidFields(t *testing.T) {
+	cases := map[string]struct {
+		spec SkillSpec
+		want string
+	}{
+		"license":     {SkillSpec{License: "MIT or whatever"}, "not an SPDX identifier"},
+		"homepage":    {SkillSpec{Homepage: "example.com"}, "must be an http or https URL"},
+		"tag":         {SkillSpec{Tags: []string{"Planning"}}, `invalid tag "Planning"`},
+		"dup tag":     {SkillSpec{Tags: []string{"ddd", "ddd"}}, `duplicate tag "ddd"`},
+		"tool":        {SkillSpec{AllowedTools: []string{"Read, Write"}}, `invalid allowed tool "Read, Write"`},
+		"connector":   {SkillSpec{Connectors: []string{""}}, "connector names must not be empty"},
+		"guardrail":   {SkillSpec{Guardrails: []string{"no-swearing"}}, `unknown guardrail "no-swearing"`},
+		"min version": {SkillSpec{MinAIOSVersion: "1.2"}, "not valid semver"},
+		"pack":        {SkillSpec{Model: ModelPreferences{PolicyPack: "cheapest"}}, `policy_pack "cheapest" is not one of cost-first`},
+		"budget":      {SkillSpec{Model: ModelPreferences{Budget: "huge"}}, `budget "huge" is not one of low, normal, high`},
+	}
+	for name, tc := range cases {
+		err := validateMetadata(tc.spec, policy.NewGuardrailRegistry())
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateMetadataUsesGivenGuardrails(t *testing.T) {
+	registry := policy.NewGuardrailRegistry()
+	pass := func(payload map[string]any) policy.GuardrailResult { return policy.GuardrailResult{Payload: payload} }
+	if err := registry.Register(policy.Guardrail{Name: "no-swearing", Input: pass}); err != nil {
+		t.Fatal(err)
+	}
+	spec := SkillSpec{Guardrails: []string{"no-pii", "no-swearing"}}
+	if err := validateMetadata(spec, registry); err != nil {
+		t.Fatalf("expected the custom guardrail to be known, got %v", err)
+	}
+}
+
+func TestCheckAIOSVersion(t *testing.T) {
+	spec := SkillSpec{ID: "roadmap-reader", MinAIOSVersion: "1.2.0"}
+	for current, ok := range map[string]bool{
+		"1.2.0":        true,
+		"v1.10.0":      true,
+		"1.2.0-beta.1": false,
+		"1.1.9":        false,
+		"dev":          true,
+	} {
+		err := CheckAIOSVersion(spec, current)
+		if (err == nil) != ok {
+			t.Errorf("%s: expected ok=%v, got %v", current, ok, err)
+		}
+	}
+	if err := CheckAIOSVersion(spec, "1.0.0"); err == nil || err.Error() != "skill roadmap-reader requires aios 1.2.0 or newer; this is 1.0.0" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCompareSemverPrerelease(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		if got := compareSemver(ordered[i], ordered[i+1]); got != -1 {
+			t.Errorf("expected %s < %s, got %d", ordered[i], ordered[i+1], got)
+		}
+	}
+}
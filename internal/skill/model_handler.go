@@ -0,0 +1,174 @@
+package skill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/aios/internal/model"
+)
+
+// ErrInvalidModelOutput is returned when every attempt of a model handler
+// produced output that was not a JSON object matching the output schema.
+var ErrInvalidModelOutput = errors.New("model output is invalid")
+
+// DefaultModelAttempts is how many completions a model handler requests
+// before giving up on invalid output.
+const DefaultModelAttempts = 3
+
+// ModelHandlerOptions tunes NewModelHandler.
+type ModelHandlerOptions struct {
+	// MaxAttempts bounds the completions requested per execution; values
+	// below 1 use DefaultModelAttempts.
+	MaxAttempts int
+	// Timeout bounds each completion; zero means no limit beyond the
+	// execution's deadline and the provider's own.
+	Timeout time.Duration
+}
+
+// NewModelExecutor returns an executor with opts' limits that, when provider
+// is set, runs skills without a registered handler on it through
+// NewModelHandler. Artifacts without a PromptPath have no prompt to run and
+// get StubOutput, as do all skills when provider is nil.
+func NewModelExecutor(provider model.Provider, opts ExecutorOptions) *Executor {
+	exec := NewExecutorWithOptions(opts)
+	if provider == nil {
+		return exec
+	}
+	runModel := NewModelHandler(provider, ModelHandlerOptions{})
+	exec.SetFallbackHandler(func(ctx context.Context, a Artifact, input map[string]any) (map[string]any, error) {
+		if a.PromptPath == "" {
+			return StubOutput(a), nil
+		}
+		return runModel(ctx, a, input)
+	})
+	return exec
+}
+
+// NewModelHandler returns a handler that runs a skill's prompt on a model.
+// It renders the prompt at Artifact.PromptPath with the input and
+// Artifact.Triggers, asks provider to complete it with Artifact.Model, and
+// parses the reply as a JSON object.
+// A reply that is not JSON or violates the output schema is sent back to the
+// model with the problems listed, up to MaxAttempts times.
+func NewModelHandler(provider model.Provider, opts ModelHandlerOptions) ContextHandlerFunc {
+	attempts := opts.MaxAttempts
+	if attempts < 1 {
+		attempts = DefaultModelAttempts
+	}
+	return func(ctx context.Context, a Artifact, input map[string]any) (map[string]any, error) {
+		if a.Model == "" {
+			return nil, fmt.Errorf("no model selected for skill %s", a.ID)
+		}
+		messages, err := renderModelPrompt(a, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for range attempts {
+			content, err := completeWithTimeout(ctx, provider, model.CompletionRequest{
+				Model:    a.Model,
+				Messages: messages,
+				JSON:     true,
+			}, opts.Timeout)
+			if err != nil {
+				return nil, err
+			}
+			out, err := parseModelOutput(a, content)
+			if err == nil {
+				return out, nil
+			}
+			lastErr = err
+			messages = append(messages,
+				model.Message{Role: model.RoleAssistant, Content: content},
+				model.Message{Role: model.RoleUser, Content: fmt.Sprintf(
+					"That reply is invalid: %v. Reply again with only a JSON object that matches the output schema.", err)},
+			)
+		}
+		return nil, fmt.Errorf("%w after %d attempt(s): %v", ErrInvalidModelOutput, attempts, lastErr)
+	}
+}
+
+// renderModelPrompt builds the conversation for a skill execution: the
+// rendered prompt and the output contract as the system message, and the
+// input as the user message.
+func renderModelPrompt(a Artifact, input map[string]any) ([]model.Message, error) {
+	if a.PromptPath == "" {
+		return nil, fmt.Errorf("prompt path is required for skill %s", a.ID)
+	}
+	prompt, err := LoadProgressivePrompt(a.PromptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	triggers := a.Triggers
+	if triggers == nil {
+		triggers = prompt.AllSections()
+	}
+	rendered, err := prompt.Render(input, triggers)
+	if err != nil {
+		return nil, err
+	}
+
+	var system strings.Builder
+	system.WriteString(strings.TrimSpace(rendered))
+	system.WriteString("\n\nRespond with a single JSON object and nothing else.")
+	if a.Schemas != nil && a.Schemas.Output != nil {
+		doc, err := a.Schemas.Output.Document()
+		if err != nil {
+			return nil, fmt.Errorf("encode output schema: %w", err)
+		}
+		system.WriteString(" The object must match this JSON Schema:\n")
+		system.Write(doc)
+	}
+
+	body, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode input: %w", err)
+	}
+	return []model.Message{
+		{Role: model.RoleSystem, Content: system.String()},
+		{Role: model.RoleUser, Content: "Input:\n" + string(body)},
+	}, nil
+}
+
+// parseModelOutput decodes a reply as a JSON object, tolerating a Markdown
+// code fence around it, and validates it against the output schema.
+func parseModelOutput(a Artifact, content string) (map[string]any, error) {
+	text := strings.TrimSpace(content)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		return nil, fmt.Errorf("not a JSON object: %v", err)
+	}
+	if out == nil {
+		return nil, fmt.Errorf("not a JSON object: null")
+	}
+	if a.Schemas != nil && a.Schemas.Output != nil {
+		if err := a.Schemas.Output.Validate(out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func completeWithTimeout(ctx context.Context, provider model.Provider, req model.CompletionRequest, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	resp, err := provider.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
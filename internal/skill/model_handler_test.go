@@ -0,0 +1,176 @@
+package skill
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/model"
+)
+
+type scriptedProvider struct {
+	mu       sync.Mutex
+	replies  []string
+	requests []model.CompletionRequest
+}
+
+func (p *scriptedProvider) Complete(_ context.Context, req model.CompletionRequest) (model.CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	if len(p.replies) == 0 {
+		return model.CompletionResponse{}, errors.New("no reply scripted")
+	}
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return model.CompletionResponse{Model: req.Model, Content: reply}, nil
+}
+
+func modelArtifact(t *testing.T) Artifact {
+	t.Helper()
+	dir := writeSnapshotSkill(t, map[string]string{
+		"prompt.md":          "Answer the question.\n# @section detail\nBe thorough.\n",
+		"schema.output.json": `{"type":"object","required":["answer"],"properties":{"answer":{"type":"string"}}}`,
+	})
+	spec, err := LoadSkillSpec(filepath.Join(dir, "skill.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := LoadSkillSchemas(dir, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Artifact{
+		ID:           "snap-skill",
+		Version:      "0.1.0",
+		PromptPath:   filepath.Join(dir, "prompt.md"),
+		InputSchema:  "schema.input.json",
+		OutputSchema: "schema.output.json",
+		Schemas:      schemas,
+		Model:        "gpt-4.1-mini",
+	}
+}
+
+func TestModelHandlerRendersPromptAndCallsSelectedModel(t *testing.T) {
+	a := modelArtifact(t)
+	provider := &scriptedProvider{replies: []string{"```json\n{\"answer\":\"42\"}\n```"}}
+	exec := NewExecutor()
+	exec.SetFallbackHandler(NewModelHandler(provider, ModelHandlerOptions{}))
+
+	out, err := exec.Execute(a, map[string]any{"q": "meaning of life"})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if out["answer"] != "42" {
+		t.Fatalf("unexpected output: %v", out)
+	}
+	if len(provider.requests) != 1 {
+		t.Fatalf("expected one completion, got %d", len(provider.requests))
+	}
+	req := provider.requests[0]
+	if req.Model != "gpt-4.1-mini" || !req.JSON {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	system, user := req.Messages[0].Content, req.Messages[1].Content
+	if !strings.Contains(system, "Answer the question.") || !strings.Contains(system, "Be thorough.") || !strings.Contains(system, `"required":["answer"]`) {
+		t.Fatalf("unexpected system prompt:\n%s", system)
+	}
+	if !strings.Contains(user, `"q": "meaning of life"`) {
+		t.Fatalf("unexpected user prompt:\n%s", user)
+	}
+}
+
+func TestModelHandlerRendersOnlyTriggeredSections(t *testing.T) {
+	a := modelArtifact(t)
+	if err := os.WriteFile(a.PromptPath, []byte("Answer {{ input.q }}.\n# @section detail\nBe thorough.\n# @section brief\nBe brief.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.Triggers = []string{"brief"}
+	provider := &scriptedProvider{replies: []string{`{"answer":"ok"}`}}
+	if _, err := NewModelHandler(provider, ModelHandlerOptions{})(context.Background(), a, map[string]any{"q": "why"}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	system := provider.requests[0].Messages[0].Content
+	if !strings.Contains(system, "Answer why.") || !strings.Contains(system, "Be brief.") || strings.Contains(system, "Be thorough.") {
+		t.Fatalf("unexpected system prompt:\n%s", system)
+	}
+}
+
+func TestModelHandlerRetriesInvalidOutput(t *testing.T) {
+	a := modelArtifact(t)
+	provider := &scriptedProvider{replies: []string{"sure thing", `{"answer":7}`, `{"answer":"seven"}`}}
+	out, err := NewModelHandler(provider, ModelHandlerOptions{})(context.Background(), a, map[string]any{"q": "x"})
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if out["answer"] != "seven" {
+		t.Fatalf("unexpected output: %v", out)
+	}
+	last := provider.requests[2].Messages
+	if len(last) != 6 || !strings.Contains(last[5].Content, "/answer: expected string") {
+		t.Fatalf("expected schema feedback in retry, got %+v", last)
+	}
+
+	provider = &scriptedProvider{replies: []string{"no", "still no"}}
+	_, err = NewModelHandler(provider, ModelHandlerOptions{MaxAttempts: 2})(context.Background(), a, map[string]any{"q": "x"})
+	if !errors.Is(err, ErrInvalidModelOutput) || !strings.Contains(err.Error(), "after 2 attempt(s)") {
+		t.Fatalf("expected invalid output error, got %v", err)
+	}
+}
+
+func TestModelHandlerRequiresModelAndPrompt(t *testing.T) {
+	a := modelArtifact(t)
+	handler := NewModelHandler(&scriptedProvider{}, ModelHandlerOptions{})
+	noModel := a
+	noModel.Model = ""
+	if _, err := handler(context.Background(), noModel, map[string]any{"q": "x"}); err == nil || !strings.Contains(err.Error(), "no model selected") {
+		t.Fatalf("expected missing model error, got %v", err)
+	}
+	if err := os.Remove(a.PromptPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handler(context.Background(), a, map[string]any{"q": "x"}); err == nil || !strings.Contains(err.Error(), "open prompt file") {
+		t.Fatalf("expected prompt error, got %v", err)
+	}
+}
+
+func TestModelExecutorRunsFixturesOnProvider(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"prompt.md":              "Answer the question.\n",
+		"tests/fixture_01.json":  `{"q":"why"}`,
+		"tests/expected_01.json": `{"answer":"because"}`,
+	})
+	provider := &scriptedProvider{replies: []string{`{"answer":"because"}`}}
+
+	results, err := RunFixtureSuiteWithOptions(dir, NewModelExecutor(provider, ExecutorOptions{}), FixtureOptions{})
+	if err != nil {
+		t.Fatalf("run suite: %v", err)
+	}
+	if len(results) != 1 || !results[0].Passed {
+		t.Fatalf("expected the provider's answer to pass, got %#v", results)
+	}
+	if len(provider.requests) != 1 {
+		t.Fatalf("expected one completion, got %d", len(provider.requests))
+	}
+	req := provider.requests[0]
+	if req.Model == "" {
+		t.Fatal("expected the routed model on the request")
+	}
+	if system := req.Messages[0].Content; !strings.Contains(system, "Answer the question.") {
+		t.Fatalf("expected the skill prompt in the request, got:\n%s", system)
+	}
+}
+
+func TestModelExecutorWithoutProviderUsesStub(t *testing.T) {
+	out, err := NewModelExecutor(nil, ExecutorOptions{}).Execute(Artifact{ID: "s", Version: "0.1.0", PromptPath: "prompt.md", InputSchema: "in.json", OutputSchema: "out.json"}, map[string]any{"q": "why"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out["status"] != "ok" {
+		t.Fatalf("expected stub output, got %#v", out)
+	}
+}
@@ -2,12 +2,73 @@ package skill
 
 import (
 	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
+const (
+	// ManifestName is the package entry listing every other entry.
+	ManifestName = "MANIFEST.json"
+	// IgnoreFile lists patterns of files to leave out of a package.
+	IgnoreFile = ".aiosignore"
+	// ManifestFormatVersion is the MANIFEST.json layout PackageSkill writes.
+	ManifestFormatVersion = 1
+	// maxPackageSize bounds the uncompressed size of a package's files.
+	maxPackageSize = 256 << 20
+)
+
+// packageEpoch is the modification time of every package entry, the
+// earliest a zip file can record, so packages of the same files are
+// byte-identical.
+var packageEpoch = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// packageJunk are file names never packaged: editor swap and backup files
+// and earlier packages. Dot files are left out as well.
+var packageJunk = []string{"*.swp", "*.swo", "*~", "#*#", "*.zip"}
+
+// Manifest describes a skill package: the skill's metadata and a checksum
+// of each file.
+type Manifest struct {
+	FormatVersion  int            `json:"format_version"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name,omitempty"`
+	Version        string         `json:"version"`
+	Description    string         `json:"description,omitempty"`
+	Author         string         `json:"author,omitempty"`
+	License        string         `json:"license,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	Homepage       string         `json:"homepage,omitempty"`
+	Clients        []string       `json:"clients,omitempty"`
+	MinAIOSVersion string         `json:"min_aios_version,omitempty"`
+	Files          []ManifestFile `json:"files"`
+}
+
+// ManifestFile is one packaged file.
+type ManifestFile struct {
+	// Path is slash-separated and relative to the skill directory.
+	Path       string `json:"path"`
+	SHA256     string `json:"sha256"`
+	Size       int64  `json:"size"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// PackageSkill validates the skill in skillDir and writes it to outputZip,
+// or <id>-<version>.zip beside skillDir when outputZip is empty. Entries are
+// sorted and carry fixed timestamps and modes, so packaging the same files
+// always yields the same bytes. MANIFEST.json comes first. Dot files, editor
+// swap files, zips and anything matching .aiosignore are left out.
 func PackageSkill(skillDir, outputZip string) error {
 	spec, err := LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
 	if err != nil {
@@ -20,41 +81,406 @@ func PackageSkill(skillDir, outputZip string) error {
 		outputZip = filepath.Join(filepath.Dir(skillDir), spec.ID+"-"+spec.Version+".zip")
 	}
 
-	out, err := os.OpenFile(filepath.Clean(outputZip), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	files, err := PackageFiles(skillDir, outputZip)
 	if err != nil {
+		return fmt.Errorf("package skill: %w", err)
+	}
+	manifest := newManifest(spec)
+	contents := make([][]byte, len(files))
+	for i, rel := range files {
+		p := filepath.Join(skillDir, filepath.FromSlash(rel))
+		info, err := os.Stat(p)
+		if err != nil {
+			return fmt.Errorf("package skill: %w", err)
+		}
+		// #nosec G304 -- rel comes from walking skillDir.
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("package skill: %w", err)
+		}
+		contents[i] = data
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Path:       rel,
+			SHA256:     sha256Hex(data),
+			Size:       int64(len(data)),
+			Executable: info.Mode().Perm()&0o111 != 0,
+		})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := writePackageEntry(zw, ManifestFile{Path: ManifestName}, append(manifestData, '\n')); err != nil {
+		return fmt.Errorf("package skill: %w", err)
+	}
+	for i, f := range manifest.Files {
+		if err := writePackageEntry(zw, f, contents[i]); err != nil {
+			return fmt.Errorf("package skill: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("package skill: %w", err)
+	}
+	if err := os.WriteFile(filepath.Clean(outputZip), buf.Bytes(), 0o600); err != nil {
 		return fmt.Errorf("create zip: %w", err)
 	}
-	defer out.Close()
+	return nil
+}
 
-	zw := zip.NewWriter(out)
-	defer zw.Close()
+func newManifest(spec SkillSpec) Manifest {
+	return Manifest{
+		FormatVersion:  ManifestFormatVersion,
+		ID:             spec.ID,
+		Name:           spec.Name,
+		Version:        spec.Version,
+		Description:    spec.Description,
+		Author:         spec.Author,
+		License:        spec.License,
+		Tags:           spec.Tags,
+		Homepage:       spec.Homepage,
+		Clients:        spec.Clients,
+		MinAIOSVersion: spec.MinAIOSVersion,
+		Files:          []ManifestFile{},
+	}
+}
 
-	err = filepath.WalkDir(skillDir, func(path string, d os.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
+func writePackageEntry(zw *zip.Writer, f ManifestFile, data []byte) error {
+	header := &zip.FileHeader{Name: f.Path, Method: zip.Deflate, Modified: packageEpoch}
+	mode := fs.FileMode(0o644)
+	if f.Executable {
+		mode = 0o755
+	}
+	header.SetMode(mode)
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// PackageFiles returns the files under skillDir that PackageSkill packages,
+// as sorted slash-separated paths. exclude, when set, is a file to leave out,
+// such as the package being written.
+func PackageFiles(skillDir, exclude string) ([]string, error) {
+	patterns, err := loadIgnorePatterns(skillDir)
+	if err != nil {
+		return nil, err
+	}
+	var excludeAbs string
+	if exclude != "" {
+		excludeAbs, _ = filepath.Abs(exclude)
+	}
+	var files []string
+	err = filepath.WalkDir(skillDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-		if d.IsDir() {
+		if p == skillDir {
+			return nil
+		}
+		rel, err := filepath.Rel(skillDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") || ignoredByPatterns(patterns, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		rel, err := filepath.Rel(skillDir, path)
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if ignoredByPatterns(packageJunk, rel) {
+			return nil
+		}
+		if abs, err := filepath.Abs(p); err == nil && abs == excludeAbs {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadIgnorePatterns reads .aiosignore in skillDir: one pattern per line,
+// with blank lines and lines starting with # skipped.
+func loadIgnorePatterns(skillDir string) ([]string, error) {
+	f, err := os.Open(filepath.Join(skillDir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "!") {
+			return nil, fmt.Errorf("%s: negated pattern %q is not supported", IgnoreFile, line)
+		}
+		if _, err := path.Match(strings.Trim(line, "/"), ""); err != nil {
+			return nil, fmt.Errorf("%s: pattern %q: %w", IgnoreFile, line, err)
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// ignoredByPatterns reports whether rel matches an ignore pattern. A pattern
+// with a slash other than a trailing one is matched from the skill directory
+// as install patterns are; one without matches a file or directory name at
+// any depth.
+func ignoredByPatterns(patterns []string, rel string) bool {
+	segs := strings.Split(rel, "/")
+	for _, p := range patterns {
+		trimmed := strings.TrimSuffix(p, "/")
+		if strings.Contains(trimmed, "/") {
+			if matchInstallPattern(strings.TrimPrefix(trimmed, "/"), rel) {
+				return true
+			}
+			continue
+		}
+		for _, seg := range segs {
+			if ok, _ := path.Match(trimmed, seg); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VerifyPackage checks a skill package without installing it: every entry
+// must have a safe relative path and appear in MANIFEST.json with a matching
+// checksum, and the skill spec it holds must validate.
+func VerifyPackage(packagePath string) (*Manifest, error) {
+	tmp, err := os.MkdirTemp("", "aios-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	return extractPackage(packagePath, filepath.Join(tmp, "skill"))
+}
+
+// UnpackSkill verifies a skill package and extracts it into destDir, or
+// into a directory named after the skill beside the package when destDir is
+// empty. destDir must not exist or be empty. It returns the directory the
+// skill was unpacked into.
+func UnpackSkill(packagePath, destDir string) (string, *Manifest, error) {
+	stageIn := filepath.Dir(filepath.Clean(packagePath))
+	if destDir != "" {
+		stageIn = filepath.Dir(filepath.Clean(destDir))
+		if err := os.MkdirAll(stageIn, 0o755); err != nil {
+			return "", nil, err
+		}
+	}
+	staging, err := os.MkdirTemp(stageIn, ".aios-unpack-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	staged := filepath.Join(staging, "skill")
+	manifest, err := extractPackage(packagePath, staged)
+	if err != nil {
+		return "", nil, err
+	}
+	if destDir == "" {
+		destDir = filepath.Join(filepath.Dir(packagePath), manifest.ID)
+	}
+	if entries, err := os.ReadDir(destDir); err == nil {
+		if len(entries) > 0 {
+			return "", nil, fmt.Errorf("destination %s is not empty", destDir)
+		}
+		if err := os.Remove(destDir); err != nil {
+			return "", nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return "", nil, err
+	}
+	if err := os.Rename(staged, destDir); err != nil {
+		// destDir may sit on another filesystem than the staging directory.
+		if copyErr := copyTree(staged, destDir); copyErr != nil {
+			_ = os.RemoveAll(destDir)
+			return "", nil, fmt.Errorf("unpack skill: %w", copyErr)
+		}
+	}
+	return destDir, manifest, nil
+}
+
+// extractPackage verifies the package at packagePath while extracting it
+// into dir, which must not exist, then validates the skill spec.
+func extractPackage(packagePath, dir string) (*Manifest, error) {
+	zr, err := zip.OpenReader(packagePath)
+	if err != nil {
+		return nil, fmt.Errorf("open package: %w", err)
+	}
+	defer zr.Close()
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if err := checkEntryPath(f.Name); err != nil {
+			return nil, err
+		}
+		if !f.Mode().IsRegular() {
+			return nil, fmt.Errorf("package entry %s is not a regular file", f.Name)
+		}
+		if _, dup := entries[f.Name]; dup {
+			return nil, fmt.Errorf("package entry %s appears twice", f.Name)
+		}
+		entries[f.Name] = f
+	}
+
+	mf, ok := entries[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("package has no %s", ManifestName)
+	}
+	data, err := readEntry(mf, maxPackageSize)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ManifestName, err)
+	}
+	if manifest.FormatVersion != ManifestFormatVersion {
+		return nil, fmt.Errorf("%s format_version %d is not supported", ManifestName, manifest.FormatVersion)
+	}
+
+	var total int64
+	listed := map[string]bool{ManifestName: true}
+	for _, f := range manifest.Files {
+		if err := checkEntryPath(f.Path); err != nil {
+			return nil, fmt.Errorf("%s: %w", ManifestName, err)
+		}
+		if listed[f.Path] {
+			return nil, fmt.Errorf("%s lists %s twice", ManifestName, f.Path)
+		}
+		listed[f.Path] = true
+		if total += f.Size; f.Size < 0 || total > maxPackageSize {
+			return nil, fmt.Errorf("package files exceed %d bytes", maxPackageSize)
+		}
+	}
+	for name := range entries {
+		if !listed[name] {
+			return nil, fmt.Errorf("package entry %s is not in %s", name, ManifestName)
+		}
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Files {
+		entry, ok := entries[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("package is missing %s", f.Path)
+		}
+		data, err := readEntry(entry, f.Size)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != f.Size || sha256Hex(data) != f.SHA256 {
+			return nil, fmt.Errorf("package entry %s does not match its checksum", f.Path)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return nil, err
+		}
+		mode := fs.FileMode(0o644)
+		if f.Executable {
+			mode = 0o755
+		}
+		if err := os.WriteFile(dst, data, mode); err != nil {
+			return nil, err
+		}
+	}
+
+	spec, err := LoadSkillSpec(filepath.Join(dir, "skill.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateSkillSpec(dir, spec); err != nil {
+		return nil, err
+	}
+	if spec.ID != manifest.ID || spec.Version != manifest.Version {
+		return nil, fmt.Errorf("%s names %s %s but skill.yaml holds %s %s", ManifestName, manifest.ID, manifest.Version, spec.ID, spec.Version)
+	}
+	return &manifest, nil
+}
+
+// checkEntryPath rejects package paths that could land outside the
+// directory they are extracted into.
+func checkEntryPath(name string) error {
+	if name == "" || strings.Contains(name, "\\") || path.IsAbs(name) || filepath.IsAbs(name) ||
+		filepath.VolumeName(name) != "" || path.Clean(name) != name {
+		return fmt.Errorf("package entry %q has an unsafe path", name)
+	}
+	for _, seg := range strings.Split(name, "/") {
+		if seg == ".." || seg == "." {
+			return fmt.Errorf("package entry %q has an unsafe path", name)
+		}
+	}
+	return nil
+}
+
+// readEntry reads a zip entry, failing when it holds more than limit bytes.
+func readEntry(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("package entry %s is larger than expected", f.Name)
+	}
+	return data, nil
+}
+
+// copyTree copies the regular files under src into dst, keeping modes.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		f, err := zw.Create(rel)
+		rel, err := filepath.Rel(src, p)
 		if err != nil {
 			return err
 		}
-		// #nosec G304 -- path originates from filepath.WalkDir on skillDir.
-		src, err := os.Open(filepath.Clean(path))
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		info, err := d.Info()
 		if err != nil {
 			return err
 		}
-		defer src.Close()
-		_, err = io.Copy(f, src)
-		return err
+		// #nosec G304 -- p comes from walking a staging directory.
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
 	})
-	if err != nil {
-		return fmt.Errorf("package skill: %w", err)
-	}
-	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
@@ -1,11 +1,48 @@
 package skill
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
+func writePackageSkill(t *testing.T, root string) string {
+	t.Helper()
+	skillDir := filepath.Join(root, "roadmap-reader")
+	files := map[string]string{
+		"skill.yaml":         "id: roadmap-reader\nversion: 0.1.0\nauthor: Platform Team\nlicense: MIT\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"schema.input.json":  `{"type":"object","properties":{"q":{"type":"string"}}}`,
+		"schema.output.json": `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		"prompt.md":          "Read the roadmap.\n",
+		"scripts/run.sh":     "#!/bin/sh\n",
+		"drafts/idea.md":     "draft\n",
+		"notes.log":          "log\n",
+		"prompt.md.swp":      "swap\n",
+		"old-0.0.1.zip":      "zip\n",
+		".env":               "SECRET=1\n",
+		".git/config":        "[core]\n",
+		IgnoreFile:           "# local files\ndrafts/\n*.log\n",
+	}
+	for name, body := range files {
+		p := filepath.Join(skillDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(skillDir, "scripts", "run.sh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return skillDir
+}
+
 func TestPackageSkill(t *testing.T) {
 	root := t.TempDir()
 	skillDir := filepath.Join(root, "roadmap-reader")
@@ -29,3 +66,209 @@ func TestPackageSkill(t *testing.T) {
 		t.Fatalf("missing zip: %v", err)
 	}
 }
+
+func TestPackageSkillIsReproducibleAndWritesManifest(t *testing.T) {
+	root := t.TempDir()
+	skillDir := writePackageSkill(t, root)
+	first := filepath.Join(root, "first.zip")
+	if err := PackageSkill(skillDir, first); err != nil {
+		t.Fatalf("package: %v", err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(skillDir, "prompt.md"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	// Packaging into the skill directory must not package the output.
+	second := filepath.Join(skillDir, "second.zip")
+	if err := PackageSkill(skillDir, second); err != nil {
+		t.Fatalf("package: %v", err)
+	}
+	a, _ := os.ReadFile(first)
+	b, _ := os.ReadFile(second)
+	if !bytes.Equal(a, b) {
+		t.Fatal("expected identical packages for identical files")
+	}
+
+	zr, err := zip.OpenReader(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if !f.Modified.Equal(packageEpoch) {
+			t.Errorf("%s: expected fixed timestamp, got %v", f.Name, f.Modified)
+		}
+	}
+	want := "MANIFEST.json,prompt.md,schema.input.json,schema.output.json,scripts/run.sh,skill.yaml"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("unexpected entries %s, want %s", got, want)
+	}
+	if mode := zr.File[4].Mode().Perm(); mode != 0o755 {
+		t.Errorf("expected executable mode 0755, got %v", mode)
+	}
+
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.ID != "roadmap-reader" || manifest.Author != "Platform Team" || manifest.License != "MIT" || len(manifest.Files) != 5 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	if f := manifest.Files[0]; f.Path != "prompt.md" || f.SHA256 != sha256Hex([]byte("Read the roadmap.\n")) || f.Size != 18 {
+		t.Errorf("unexpected manifest entry %+v", f)
+	}
+}
+
+func TestUnpackSkillRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "skill.zip")
+	if err := PackageSkill(writePackageSkill(t, root), pkg); err != nil {
+		t.Fatalf("package: %v", err)
+	}
+	if _, err := VerifyPackage(pkg); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	dest := filepath.Join(root, "out", "roadmap")
+	dir, manifest, err := UnpackSkill(pkg, dest)
+	if err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	if dir != dest || manifest.Version != "0.1.0" {
+		t.Fatalf("unexpected result %s %+v", dir, manifest)
+	}
+	info, err := os.Stat(filepath.Join(dest, "scripts", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("expected executable script, got %v (%v)", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ManifestName)); !os.IsNotExist(err) {
+		t.Error("expected the manifest to stay in the package")
+	}
+
+	if _, _, err := UnpackSkill(pkg, dest); err == nil || !strings.Contains(err.Error(), "is not empty") {
+		t.Fatalf("expected non-empty destination to be refused, got %v", err)
+	}
+
+	moved := filepath.Join(t.TempDir(), "skill.zip")
+	if err := os.Rename(pkg, moved); err != nil {
+		t.Fatal(err)
+	}
+	dir, _, err = UnpackSkill(moved, "")
+	if err != nil || dir != filepath.Join(filepath.Dir(moved), "roadmap-reader") {
+		t.Fatalf("expected unpack beside the package, got %s %v", dir, err)
+	}
+}
+
+// writeZip writes a package with the given entries in order.
+func writeZip(t *testing.T, entries [][2]string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "pkg.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestVerifyPackageRejectsTamperedPackages(t *testing.T) {
+	spec := "id: s\nversion: 0.1.0\ninputs:\n  schema: in.json\noutputs:\n  schema: out.json\n"
+	schema := `{"type":"object","properties":{"q":{"type":"string"}}}`
+	manifest := func(files map[string]string) string {
+		m := Manifest{FormatVersion: ManifestFormatVersion, ID: "s", Version: "0.1.0"}
+		for _, name := range []string{"../evil.sh", "in.json", "out.json", "skill.yaml"} {
+			if body, ok := files[name]; ok {
+				m.Files = append(m.Files, ManifestFile{Path: name, SHA256: sha256Hex([]byte(body)), Size: int64(len(body))})
+			}
+		}
+		data, _ := json.Marshal(m)
+		return string(data)
+	}
+	valid := map[string]string{"skill.yaml": spec, "in.json": schema, "out.json": schema}
+
+	cases := map[string]struct {
+		entries [][2]string
+		want    string
+	}{
+		"zip slip": {
+			[][2]string{{ManifestName, manifest(valid)}, {"../evil.sh", "x"}, {"skill.yaml", spec}},
+			`"../evil.sh" has an unsafe path`,
+		},
+		"absolute": {
+			[][2]string{{ManifestName, manifest(valid)}, {"/etc/passwd", "x"}},
+			`"/etc/passwd" has an unsafe path`,
+		},
+		"manifest slip": {
+			[][2]string{{ManifestName, manifest(map[string]string{"../evil.sh": "x"})}},
+			`"../evil.sh" has an unsafe path`,
+		},
+		"no manifest": {
+			[][2]string{{"skill.yaml", spec}},
+			"package has no MANIFEST.json",
+		},
+		"unlisted": {
+			[][2]string{{ManifestName, manifest(valid)}, {"skill.yaml", spec}, {"in.json", schema}, {"out.json", schema}, {"extra.md", "x"}},
+			"extra.md is not in MANIFEST.json",
+		},
+		"checksum": {
+			[][2]string{{ManifestName, manifest(valid)}, {"skill.yaml", spec}, {"in.json", schema}, {"out.json", `{"type":"string"}`}},
+			"out.json does not match its checksum",
+		},
+		"missing": {
+			[][2]string{{ManifestName, manifest(valid)}, {"skill.yaml", spec}, {"in.json", schema}},
+			"package is missing out.json",
+		},
+		"invalid spec": {
+			[][2]string{{ManifestName, manifest(map[string]string{"skill.yaml": spec})}, {"skill.yaml", spec}},
+			"in.json",
+		},
+	}
+	for name, tc := range cases {
+		_, err := VerifyPackage(writeZip(t, tc.entries))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+
+	entries := [][2]string{{ManifestName, manifest(valid)}, {"skill.yaml", spec}, {"in.json", schema}, {"out.json", schema}}
+	if _, err := VerifyPackage(writeZip(t, entries)); err != nil {
+		t.Fatalf("expected valid package, got %v", err)
+	}
+}
+
+func TestIgnoredByPatterns(t *testing.T) {
+	patterns := []string{"drafts/", "*.log", "/docs/internal", "tests/large/**"}
+	for rel, want := range map[string]bool{
+		"drafts/idea.md":       true,
+		"sub/drafts/x.md":      true,
+		"a/b/run.log":          true,
+		"docs/internal/x.md":   true,
+		"sub/docs/internal":    false,
+		"tests/large/a/b.json": true,
+		"tests/small.json":     false,
+		"prompt.md":            false,
+	} {
+		if got := ignoredByPatterns(patterns, rel); got != want {
+			t.Errorf("%s: expected %v, got %v", rel, want, got)
+		}
+	}
+}
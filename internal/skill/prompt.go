@@ -1,9 +1,9 @@
 package skill
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -11,36 +11,73 @@ type PromptSection struct {
 	Trigger  string
 	Content  string
 	Required bool
+	// Description says when the section applies, if the prompt says.
+	Description string
+	// Line is the line of the prompt file the content starts at.
+	Line int
 }
 
 type ProgressivePrompt struct {
 	Sections   []PromptSection
 	BasePrompt string
+	// Path is the prompt file the prompt was loaded from, if any.
+	Path string
+	// PartialDirs are searched in order for the partials the prompt
+	// includes.
+	PartialDirs []string
+}
+
+// PartialDirs returns where prompts of the skill in skillDir find partials:
+// the skill's own partials directory, then a partials directory shared with
+// its sibling skills.
+func PartialDirs(skillDir string) []string {
+	skillDir = filepath.Clean(skillDir)
+	return []string{
+		filepath.Join(skillDir, "partials"),
+		filepath.Join(filepath.Dir(skillDir), "partials"),
+	}
 }
 
 func LoadProgressivePrompt(promptPath string) (*ProgressivePrompt, error) {
-	file, err := os.Open(promptPath)
+	// #nosec G304 -- callers pass the prompt.md of a skill directory.
+	data, err := os.ReadFile(promptPath)
 	if err != nil {
 		return nil, fmt.Errorf("open prompt file: %w", err)
 	}
-	defer file.Close()
+	prompt := ParseProgressivePrompt(string(data))
+	prompt.Path = promptPath
+	prompt.PartialDirs = PartialDirs(filepath.Dir(promptPath))
+	return prompt, nil
+}
 
+// ParseProgressivePrompt splits prompt text into the base prompt and the
+// sections started by "# @section <trigger>" lines. A trigger starting with
+// "*" marks a required section, and text after a colon describes when the
+// section applies: "# @section code: writing or reviewing code".
+func ParseProgressivePrompt(text string) *ProgressivePrompt {
 	var basePrompt strings.Builder
 	var sections []PromptSection
 	var currentSection *PromptSection
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
+	text = strings.TrimSuffix(text, "\n")
+	var lines []string
+	if text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
 
 		if strings.HasPrefix(line, "# @section ") {
 			if currentSection != nil {
 				sections = append(sections, *currentSection)
 			}
-			trigger := strings.TrimSpace(strings.TrimPrefix(line, "# @section "))
+			trigger, description, _ := strings.Cut(strings.TrimPrefix(line, "# @section "), ":")
+			trigger = strings.TrimSpace(trigger)
 			currentSection = &PromptSection{
-				Trigger:  trigger,
-				Required: strings.HasPrefix(trigger, "*"),
+				Trigger:     trigger,
+				Description: strings.TrimSpace(description),
+				Required:    strings.HasPrefix(trigger, "*"),
+				Line:        i + 2,
 			}
 			if currentSection.Required {
 				currentSection.Trigger = strings.TrimPrefix(trigger, "*")
@@ -63,7 +100,7 @@ func LoadProgressivePrompt(promptPath string) (*ProgressivePrompt, error) {
 	return &ProgressivePrompt{
 		Sections:   sections,
 		BasePrompt: basePrompt.String(),
-	}, scanner.Err()
+	}
 }
 
 func (p *ProgressivePrompt) Base() string {
@@ -116,3 +153,94 @@ func (p *ProgressivePrompt) WithAllSections() string {
 
 	return result.String()
 }
+
+// Render evaluates the prompt's templates against input and returns the base
+// prompt followed by the sections whose trigger is in triggers, plus the
+// required ones. Those sections are the active triggers {{#if trigger.x}}
+// tests. Input values that are missing render as nothing.
+func (p *ProgressivePrompt) Render(input map[string]any, triggers []string) (string, error) {
+	env := p.templateEnv(input)
+	for _, t := range triggers {
+		env.triggers[t] = true
+	}
+	for _, section := range p.Sections {
+		if section.Required {
+			env.triggers[section.Trigger] = true
+		}
+	}
+
+	var result strings.Builder
+	nodes, err := parseTemplate(p.source(), 1, p.BasePrompt)
+	if err != nil {
+		return "", err
+	}
+	if err := env.render(&result, p.source(), nodes, 0); err != nil {
+		return "", err
+	}
+	result.WriteString("\n\n")
+
+	for _, section := range p.Sections {
+		if !env.triggers[section.Trigger] {
+			continue
+		}
+		nodes, err := parseTemplate(p.source(), section.Line, section.Content)
+		if err != nil {
+			return "", err
+		}
+		var body strings.Builder
+		if err := env.render(&body, p.source(), nodes, 0); err != nil {
+			return "", err
+		}
+		result.WriteString(strings.TrimSpace(body.String()))
+		result.WriteString("\n")
+	}
+	return result.String(), nil
+}
+
+// InputReferences parses every part of the prompt, and the partials it
+// includes, and returns the input references it makes, such as
+// "input.user.name", in order of first use.
+func (p *ProgressivePrompt) InputReferences() ([]string, error) {
+	env := p.templateEnv(nil)
+	var refs []string
+	seen := map[string]bool{}
+	collect := func(n templateNode) {
+		if (n.kind == valueNode || n.kind == ifNode) && n.ref.scope == "input" && !seen[n.ref.String()] {
+			seen[n.ref.String()] = true
+			refs = append(refs, n.ref.String())
+		}
+	}
+
+	nodes, err := parseTemplate(p.source(), 1, p.BasePrompt)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.walk(p.source(), nodes, 0, collect); err != nil {
+		return nil, err
+	}
+	for _, section := range p.Sections {
+		nodes, err := parseTemplate(p.source(), section.Line, section.Content)
+		if err != nil {
+			return nil, err
+		}
+		if err := env.walk(p.source(), nodes, 0, collect); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+func (p *ProgressivePrompt) templateEnv(input map[string]any) *templateEnv {
+	env := &templateEnv{input: input, triggers: map[string]bool{}, partialDirs: p.PartialDirs}
+	if p.Path != "" {
+		env.baseDir = filepath.Dir(p.Path)
+	}
+	return env
+}
+
+func (p *ProgressivePrompt) source() string {
+	if p.Path == "" {
+		return "prompt"
+	}
+	return filepath.Base(p.Path)
+}
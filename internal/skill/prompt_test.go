@@ -3,6 +3,7 @@ package skill
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -101,3 +102,165 @@ func contains(s, substr string) bool {
 			(s[:len(substr)] == substr ||
 				contains(s[1:], substr)))
 }
+
+func TestProgressivePromptRender(t *testing.T) {
+	root := t.TempDir()
+	writeInstallTree(t, root, map[string]string{
+		"roadmap/prompt.md": `Summarize {{ input.query }} for {{input.user.name}}.
+{{> tone }}
+{{#if input.limit}}
+Use at most {{ input.limit }} bullets.
+{{else}}
+Use as many bullets as needed.
+{{/if}}
+{{#unless trigger.security}}
+Skip the security review.
+{{/unless}}
+
+# @section code
+{{#if trigger.code}}Write clean code.{{/if}}
+
+# @section *security
+Flag secrets in {{ input.tags }}.
+`,
+		"roadmap/partials/tone.md":  "Be concise.\n{{> shared/footer }}\n",
+		"partials/shared/footer.md": "Cite sources.\n",
+	})
+	prompt, err := LoadProgressivePrompt(filepath.Join(root, "roadmap", "prompt.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := prompt.Render(map[string]any{
+		"query": "Q3",
+		"user":  map[string]any{"name": "Ada"},
+		"limit": float64(5),
+		"tags":  []any{"a", "b"},
+	}, []string{"code"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "Summarize Q3 for Ada.\nBe concise.\nCite sources.\nUse at most 5 bullets.\n\n\n\nWrite clean code.\nFlag secrets in [\"a\",\"b\"].\n"
+	if got != want {
+		t.Fatalf("unexpected render:\n%q\nwant:\n%q", got, want)
+	}
+
+	got, err = prompt.Render(map[string]any{"query": "Q4"}, nil)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(got, "Summarize Q4 for .") || !strings.Contains(got, "Use as many bullets as needed.") || strings.Contains(got, "Write clean code.") {
+		t.Fatalf("unexpected render without triggers:\n%s", got)
+	}
+}
+
+func TestProgressivePromptKeepsForeignTemplateSyntax(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"prompt.md": "Review the workflow for {{ input.q }}:\n" +
+			"token: ${{ secrets.TOKEN }}\n" +
+			"image: {{ .Values.image }}\n" +
+			"{{- if .Values.debug }}debug{{- end }}\n" +
+			"Hello {{ user.name }}\n",
+	})
+	prompt, err := LoadProgressivePrompt(filepath.Join(dir, "prompt.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := prompt.Render(map[string]any{"q": "ci.yml"}, nil)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "Review the workflow for ci.yml:\ntoken: ${{ secrets.TOKEN }}\nimage: {{ .Values.image }}\n{{- if .Values.debug }}debug{{- end }}\nHello {{ user.name }}"
+	if strings.TrimSpace(got) != want {
+		t.Fatalf("unexpected render:\n%q\nwant:\n%q", got, want)
+	}
+
+	res, err := LintSkillDir(dir)
+	if err != nil {
+		t.Fatalf("lint: %v", err)
+	}
+	for _, f := range res.Findings {
+		if strings.HasPrefix(f.RuleID, "prompt-") {
+			t.Errorf("unexpected prompt finding: %+v", f)
+		}
+	}
+}
+
+func TestProgressivePromptRenderErrors(t *testing.T) {
+	cases := map[string]struct {
+		prompt string
+		want   string
+	}{
+		"unclosed tag":   {"Hello {{ input.name\n", "prompt.md:1: unclosed {{"},
+		"unclosed block": {"a\n{{#if input.x}}\nb\n", "prompt.md:2: {{#if}} is not closed"},
+		"mismatch":       {"{{#if input.x}}a{{/unless}}", "{{/unless}} does not close {{#if}}"},
+		"stray else":     {"a\n\n{{else}}\n", "prompt.md:3: unexpected {{else}}"},
+		"bad reference":  {"{{ input.user name }}", `invalid reference "input.user name"`},
+		"trigger value":  {"{{ trigger.code }}", "only input values can be interpolated"},
+		"missing":        {"{{> nope }}", `partial "nope" not found`},
+		"section line":   {"base\n# @section code\nok\n{{ input. }}\n", "prompt.md:4: invalid reference"},
+		"cycle":          {"{{> loop }}", "nests more than 10 deep"},
+	}
+	for name, tc := range cases {
+		dir := t.TempDir()
+		writeInstallTree(t, dir, map[string]string{
+			"prompt.md":        tc.prompt,
+			"partials/loop.md": "{{> loop }}",
+		})
+		prompt, err := LoadProgressivePrompt(filepath.Join(dir, "prompt.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = prompt.Render(map[string]any{}, []string{"code"})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestProgressivePromptInputReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeInstallTree(t, dir, map[string]string{
+		"prompt.md":         "{{ input.query }}\n{{#if input.limit}}{{ input.query }}{{/if}}\n{{> extra }}\n# @section deep\n{{ input.user.name }}\n",
+		"partials/extra.md": "{{#unless trigger.deep}}{{ input.mode }}{{/unless}}\n",
+	})
+	prompt, err := LoadProgressivePrompt(filepath.Join(dir, "prompt.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs, err := prompt.InputReferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"input.query", "input.limit", "input.mode", "input.user.name"}
+	if strings.Join(refs, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, refs)
+	}
+}
+
+func TestExpandPartials(t *testing.T) {
+	root := t.TempDir()
+	writeInstallTree(t, root, map[string]string{
+		"roadmap/partials/tone.md":  "Be concise.\n{{> shared/footer }}\n",
+		"partials/shared/footer.md": "Cite {{ input.source }}.\n",
+	})
+	got, err := ExpandPartials("Hello {{ input.name }}.\n  {{> tone }}\n{{#if input.x}}Inline {{> shared/footer}}{{/if}}\n", PartialDirs(filepath.Join(root, "roadmap")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Hello {{ input.name }}.\nBe concise.\nCite {{ input.source }}.\n{{#if input.x}}Inline Cite {{ input.source }}.\n{{/if}}\n"
+	if got != want {
+		t.Fatalf("unexpected expansion:\n%q\nwant:\n%q", got, want)
+	}
+
+	writeInstallTree(t, root, map[string]string{"roadmap/partials/loop.md": "{{> loop }}"})
+	for src, msg := range map[string]string{
+		"a\n{{> nope }}\n": `prompt.md:2: partial "nope" not found`,
+		"{{> loop }}":      "nests more than 10 deep",
+		"{{> ../up }}":     `invalid partial name "../up"`,
+	} {
+		if _, err := ExpandPartials(src, PartialDirs(filepath.Join(root, "roadmap"))); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: expected error containing %q, got %v", src, msg, err)
+		}
+	}
+}
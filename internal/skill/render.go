@@ -0,0 +1,180 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Renderer names, selected per agent with the "renderer" field in
+// agents.json. An agent without one uses RendererSkillMd.
+const (
+	// RendererSkillMd adds nothing beyond the shared SKILL.md directory.
+	RendererSkillMd = "skill-md"
+	// RendererCursorMdc writes a Cursor rule to .cursor/rules/<id>.mdc.
+	RendererCursorMdc = "cursor-mdc"
+	// RendererWindsurfRules writes a Windsurf rule to .windsurf/rules/<id>.md.
+	RendererWindsurfRules = "windsurf-rules"
+	// RendererClineRules writes a Cline rule to .clinerules/<id>.md.
+	RendererClineRules = "clinerules"
+	// RendererCopilotInstructions writes GitHub Copilot path instructions
+	// to .github/instructions/<id>.instructions.md.
+	RendererCopilotInstructions = "copilot-instructions"
+)
+
+// RenderedFile is an agent-native file rendered from a skill.
+type RenderedFile struct {
+	// Path is the project-relative, slash-separated location of the file.
+	Path    string
+	Content string
+}
+
+// Renderer turns a skill spec and prompt body into an agent's native rule
+// or instruction format.
+type Renderer interface {
+	Render(spec SkillSpec, promptBody string) []RenderedFile
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(spec SkillSpec, promptBody string) []RenderedFile
+
+func (f RendererFunc) Render(spec SkillSpec, promptBody string) []RenderedFile {
+	return f(spec, promptBody)
+}
+
+var renderers = map[string]Renderer{
+	RendererSkillMd:             RendererFunc(func(SkillSpec, string) []RenderedFile { return nil }),
+	RendererCursorMdc:           RendererFunc(renderCursorMdc),
+	RendererWindsurfRules:       RendererFunc(renderWindsurfRule),
+	RendererClineRules:          RendererFunc(renderClineRule),
+	RendererCopilotInstructions: RendererFunc(renderCopilotInstructions),
+}
+
+// LookupRenderer returns the renderer registered under name. An empty name
+// selects RendererSkillMd.
+func LookupRenderer(name string) (Renderer, error) {
+	if name == "" {
+		name = RendererSkillMd
+	}
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer %q; available: %s", name, strings.Join(RendererNames(), ", "))
+	}
+	return r, nil
+}
+
+// RendererNames returns the registered renderer names, sorted.
+func RendererNames() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateID checks that a skill id can name the files rendered for it: a
+// single path element such as "roadmap-reader", never "../x" or "a/b".
+func ValidateID(id string) error {
+	if id == "" || id == "." || strings.ContainsAny(id, `/\`) || !filepath.IsLocal(id) {
+		return fmt.Errorf("skill id %q must be a single path element without slashes", id)
+	}
+	return nil
+}
+
+// yamlString quotes s as a YAML scalar, so descriptions containing ": " or
+// starting with "#" or "-" keep the frontmatter valid.
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// ruleBody is the markdown shared by every rule format: a heading with the
+// skill name followed by the prompt, or the description when there is no
+// prompt.
+func ruleBody(spec SkillSpec, promptBody string) string {
+	name := spec.Name
+	if name == "" {
+		name = spec.ID
+	}
+	body := strings.TrimRight(promptBody, "\n")
+	if body == "" {
+		body = spec.Description
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", name)
+	if body != "" {
+		b.WriteString("\n")
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderCursorMdc writes a Cursor project rule. Rules with globs attach to
+// matching files; otherwise the agent decides from the description.
+func renderCursorMdc(spec SkillSpec, promptBody string) []RenderedFile {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "description: %s\n", yamlString(spec.Description))
+	fmt.Fprintf(&b, "globs: %s\n", strings.Join(spec.Rules.Globs, ","))
+	fmt.Fprintf(&b, "alwaysApply: %t\n", spec.Rules.AlwaysApply)
+	b.WriteString("---\n\n")
+	b.WriteString(ruleBody(spec, promptBody))
+	return []RenderedFile{{Path: ".cursor/rules/" + spec.ID + ".mdc", Content: b.String()}}
+}
+
+// renderWindsurfRule writes a Windsurf workspace rule whose trigger follows
+// rules.always_apply and rules.globs.
+func renderWindsurfRule(spec SkillSpec, promptBody string) []RenderedFile {
+	var b strings.Builder
+	b.WriteString("---\n")
+	switch {
+	case spec.Rules.AlwaysApply:
+		b.WriteString("trigger: always_on\n")
+	case len(spec.Rules.Globs) > 0:
+		b.WriteString("trigger: glob\n")
+		fmt.Fprintf(&b, "globs: %s\n", strings.Join(spec.Rules.Globs, ","))
+	default:
+		b.WriteString("trigger: model_decision\n")
+	}
+	fmt.Fprintf(&b, "description: %s\n", yamlString(spec.Description))
+	b.WriteString("---\n\n")
+	b.WriteString(ruleBody(spec, promptBody))
+	return []RenderedFile{{Path: ".windsurf/rules/" + spec.ID + ".md", Content: b.String()}}
+}
+
+// renderClineRule writes a Cline rule. Globs become a paths condition;
+// without them the rule is always active.
+func renderClineRule(spec SkillSpec, promptBody string) []RenderedFile {
+	var b strings.Builder
+	if len(spec.Rules.Globs) > 0 && !spec.Rules.AlwaysApply {
+		b.WriteString("---\npaths:\n")
+		for _, g := range spec.Rules.Globs {
+			fmt.Fprintf(&b, "  - %q\n", g)
+		}
+		b.WriteString("---\n\n")
+	}
+	b.WriteString(ruleBody(spec, promptBody))
+	return []RenderedFile{{Path: ".clinerules/" + spec.ID + ".md", Content: b.String()}}
+}
+
+// renderCopilotInstructions writes a Copilot path-specific instructions
+// file. Without globs or always_apply the file has no applyTo and is only
+// used when attached by hand.
+func renderCopilotInstructions(spec SkillSpec, promptBody string) []RenderedFile {
+	var b strings.Builder
+	b.WriteString("---\n")
+	switch {
+	case spec.Rules.AlwaysApply:
+		b.WriteString("applyTo: \"**\"\n")
+	case len(spec.Rules.Globs) > 0:
+		fmt.Fprintf(&b, "applyTo: %q\n", strings.Join(spec.Rules.Globs, ","))
+	}
+	fmt.Fprintf(&b, "description: %s\n", yamlString(spec.Description))
+	b.WriteString("---\n\n")
+	b.WriteString(ruleBody(spec, promptBody))
+	return []RenderedFile{{Path: ".github/instructions/" + spec.ID + ".instructions.md", Content: b.String()}}
+}
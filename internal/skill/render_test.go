@@ -0,0 +1,145 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func renderTestSpec() SkillSpec {
+	spec := SkillSpec{ID: "go-review", Name: "Go Review", Description: "Review Go changes"}
+	spec.Rules.Globs = []string{"**/*.go", "go.mod"}
+	return spec
+}
+
+func renderOne(t *testing.T, name string, spec SkillSpec, prompt string) RenderedFile {
+	t.Helper()
+	r, err := LookupRenderer(name)
+	if err != nil {
+		t.Fatalf("LookupRenderer(%q): %v", name, err)
+	}
+	files := r.Render(spec, prompt)
+	if len(files) != 1 {
+		t.Fatalf("expected one file from %s, got %d", name, len(files))
+	}
+	return files[0]
+}
+
+func TestRenderCursorMdc(t *testing.T) {
+	f := renderOne(t, RendererCursorMdc, renderTestSpec(), "Check error handling.\n")
+	if f.Path != ".cursor/rules/go-review.mdc" {
+		t.Errorf("unexpected path %q", f.Path)
+	}
+	want := "---\ndescription: \"Review Go changes\"\nglobs: **/*.go,go.mod\nalwaysApply: false\n---\n\n# Go Review\n\nCheck error handling.\n"
+	if f.Content != want {
+		t.Errorf("unexpected content:\n%s", f.Content)
+	}
+}
+
+func TestRenderWindsurfRuleTriggers(t *testing.T) {
+	spec := renderTestSpec()
+	if f := renderOne(t, RendererWindsurfRules, spec, ""); !strings.Contains(f.Content, "trigger: glob\nglobs: **/*.go,go.mod\n") {
+		t.Errorf("expected glob trigger, got:\n%s", f.Content)
+	}
+	spec.Rules.AlwaysApply = true
+	if f := renderOne(t, RendererWindsurfRules, spec, ""); !strings.Contains(f.Content, "trigger: always_on\n") {
+		t.Errorf("expected always_on trigger, got:\n%s", f.Content)
+	}
+	spec.Rules.AlwaysApply = false
+	spec.Rules.Globs = nil
+	f := renderOne(t, RendererWindsurfRules, spec, "")
+	if !strings.Contains(f.Content, "trigger: model_decision\n") {
+		t.Errorf("expected model_decision trigger, got:\n%s", f.Content)
+	}
+	if f.Path != ".windsurf/rules/go-review.md" {
+		t.Errorf("unexpected path %q", f.Path)
+	}
+	// Without a prompt the description becomes the body.
+	if !strings.HasSuffix(f.Content, "# Go Review\n\nReview Go changes\n") {
+		t.Errorf("expected description body, got:\n%s", f.Content)
+	}
+}
+
+func TestRenderClineRule(t *testing.T) {
+	f := renderOne(t, RendererClineRules, renderTestSpec(), "Check error handling.")
+	if f.Path != ".clinerules/go-review.md" {
+		t.Errorf("unexpected path %q", f.Path)
+	}
+	if !strings.HasPrefix(f.Content, "---\npaths:\n  - \"**/*.go\"\n  - \"go.mod\"\n---\n\n# Go Review\n") {
+		t.Errorf("unexpected content:\n%s", f.Content)
+	}
+
+	spec := renderTestSpec()
+	spec.Rules.Globs = nil
+	if f := renderOne(t, RendererClineRules, spec, "Body"); f.Content != "# Go Review\n\nBody\n" {
+		t.Errorf("expected plain rule without globs, got:\n%s", f.Content)
+	}
+}
+
+func TestRenderCopilotInstructions(t *testing.T) {
+	f := renderOne(t, RendererCopilotInstructions, renderTestSpec(), "")
+	if f.Path != ".github/instructions/go-review.instructions.md" {
+		t.Errorf("unexpected path %q", f.Path)
+	}
+	if !strings.HasPrefix(f.Content, "---\napplyTo: \"**/*.go,go.mod\"\n") {
+		t.Errorf("unexpected content:\n%s", f.Content)
+	}
+
+	spec := renderTestSpec()
+	spec.Rules.Globs = nil
+	if f := renderOne(t, RendererCopilotInstructions, spec, ""); strings.Contains(f.Content, "applyTo") {
+		t.Errorf("expected no applyTo without globs, got:\n%s", f.Content)
+	}
+}
+
+func TestLookupRenderer(t *testing.T) {
+	r, err := LookupRenderer("")
+	if err != nil {
+		t.Fatalf("empty name should select %s: %v", RendererSkillMd, err)
+	}
+	if files := r.Render(renderTestSpec(), "x"); len(files) != 0 {
+		t.Errorf("expected %s to render nothing, got %v", RendererSkillMd, files)
+	}
+	if _, err := LookupRenderer("vim"); err == nil || !strings.Contains(err.Error(), RendererCursorMdc) {
+		t.Errorf("expected unknown renderer error listing names, got %v", err)
+	}
+}
+
+func TestRenderQuotesDescription(t *testing.T) {
+	spec := renderTestSpec()
+	spec.Description = "# Review: Go changes - carefully"
+	for _, name := range []string{RendererCursorMdc, RendererWindsurfRules, RendererCopilotInstructions} {
+		f := renderOne(t, name, spec, "Check error handling.\n")
+		// Only the description line is checked: Cursor reads its globs
+		// unquoted, which strict YAML parsers reject.
+		var line string
+		for _, l := range strings.Split(f.Content, "\n") {
+			if strings.HasPrefix(l, "description: ") {
+				line = l
+			}
+		}
+		var front struct {
+			Description string `yaml:"description"`
+		}
+		if err := yaml.Unmarshal([]byte(line), &front); err != nil {
+			t.Fatalf("%s: invalid description line %q: %v", name, line, err)
+		}
+		if front.Description != spec.Description {
+			t.Errorf("%s: description = %q", name, front.Description)
+		}
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"go-review", "notes_v2", "Notes.md"} {
+		if err := ValidateID(id); err != nil {
+			t.Errorf("ValidateID(%q): %v", id, err)
+		}
+	}
+	for _, id := range []string{"", ".", "..", "../evil", "a/b", `a\b`, "/abs"} {
+		if err := ValidateID(id); err == nil {
+			t.Errorf("ValidateID(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaStatus is the outcome of inferring one schema file.
+type SchemaStatus string
+
+const (
+	// SchemaUnchanged means the schema file already matches the inferred
+	// schema.
+	SchemaUnchanged SchemaStatus = "unchanged"
+	// SchemaCreated means a missing schema file was written.
+	SchemaCreated SchemaStatus = "created"
+	// SchemaUpdated means a differing schema file was rewritten.
+	SchemaUpdated SchemaStatus = "updated"
+	// SchemaStale means the schema file is missing or differs and was left
+	// alone because schemas were only being inferred.
+	SchemaStale SchemaStatus = "stale"
+	// SchemaSkipped means there were no samples to infer the schema from.
+	SchemaSkipped SchemaStatus = "skipped"
+)
+
+// inferEnumMax is the most distinct strings a property may take and still
+// be inferred as an enum. An enum needs at least two.
+const inferEnumMax = 5
+
+// InferredSchema reports one schema inferred from a skill's tests.
+type InferredSchema struct {
+	// Kind is "input" for the schema inferred from fixtures and "output"
+	// for the one inferred from expected files.
+	Kind   string       `json:"kind"`
+	Path   string       `json:"path"`
+	Status SchemaStatus `json:"status"`
+	// Samples lists the test files the schema was inferred from.
+	Samples []string `json:"samples"`
+	// Skipped lists expected files left out because they use assertions
+	// rather than literal outputs.
+	Skipped []string `json:"skipped,omitempty"`
+	// Diff is a line diff from the current schema file to the inferred
+	// schema, set when they differ.
+	Diff string `json:"diff,omitempty"`
+}
+
+// InferSchemas infers the input schema of the skill in skillDir from its
+// tests/fixture_*.json files and the output schema from its
+// tests/expected_*.json files, and compares them with the schema files
+// skill.yaml names. Properties get the types seen in the samples, are
+// required when every sample has them, and strings that repeat a few values
+// become enums. Keywords the inference does not cover, such as descriptions,
+// formats and additionalProperties, are kept from the current schema files,
+// and schemas that use $ref are left as they are. With write, differing
+// schema files are rewritten as indented JSON; a schema file that is not a
+// JSON object is an error rather than being replaced.
+func InferSchemas(skillDir string, write bool) ([]InferredSchema, error) {
+	spec, err := LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	testsDir := filepath.Join(skillDir, "tests")
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read tests dir: %w", err)
+	}
+	targets := []struct {
+		kind, path, prefix string
+	}{
+		{"input", spec.Inputs.Schema, "fixture_"},
+		{"output", spec.Outputs.Schema, "expected_"},
+	}
+	results := make([]InferredSchema, 0, len(targets))
+	for _, t := range targets {
+		if t.path == "" {
+			return nil, fmt.Errorf("skill.yaml names no %s schema; add %ss.schema: schema.%s.json", t.kind, t.kind, t.kind)
+		}
+		if !filepath.IsLocal(t.path) {
+			return nil, fmt.Errorf("%s schema %s is outside the skill directory", t.kind, t.path)
+		}
+		res := InferredSchema{Kind: t.kind, Path: t.path, Samples: []string{}}
+		root := &inferNode{}
+		for _, e := range entries {
+			name := e.Name()
+			if filepath.Ext(name) != ".json" || !strings.HasPrefix(name, t.prefix) {
+				continue
+			}
+			sample, err := readJSONMap(filepath.Join(testsDir, name))
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == "output" && usesAssertions(sample) {
+				res.Skipped = append(res.Skipped, name)
+				continue
+			}
+			root.add(sample)
+			res.Samples = append(res.Samples, name)
+		}
+		if len(res.Samples) == 0 {
+			res.Status = SchemaSkipped
+			results = append(results, res)
+			continue
+		}
+		if err := compareSchema(&res, filepath.Join(skillDir, t.path), root, write); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// compareSchema renders the schema inferred in root, diffs it against the
+// file at path and, with write, replaces the file when they differ.
+func compareSchema(res *InferredSchema, path string, root *inferNode, write bool) error {
+	path = filepath.Clean(path)
+	// #nosec G304 -- path is checked to be inside the skill directory.
+	data, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	var current any
+	if exists {
+		if err := json.Unmarshal(data, &current); err != nil {
+			current = nil
+		}
+	}
+	currentMap, _ := current.(map[string]any)
+	if exists && currentMap == nil && write {
+		return fmt.Errorf("%s is not a JSON schema object; fix or remove it before writing an inferred schema", res.Path)
+	}
+	body, err := json.MarshalIndent(root.schema(currentMap), "", "  ")
+	if err != nil {
+		return err
+	}
+	next := string(body) + "\n"
+
+	// Both sides of the diff are re-encoded with sorted keys so that only
+	// changes in content show, not in key order.
+	var inferred any
+	_ = json.Unmarshal(body, &inferred)
+	pretty, _ := json.MarshalIndent(inferred, "", "  ")
+	prev := ""
+	if exists {
+		if current != nil && reflect.DeepEqual(current, inferred) {
+			res.Status = SchemaUnchanged
+			return nil
+		}
+		prev = string(data)
+		if current != nil {
+			currentPretty, _ := json.MarshalIndent(current, "", "  ")
+			prev = string(currentPretty) + "\n"
+		}
+	}
+	res.Diff = lineDiff(prev, string(pretty)+"\n")
+
+	switch {
+	case !write:
+		res.Status = SchemaStale
+		return nil
+	case exists:
+		res.Status = SchemaUpdated
+	default:
+		res.Status = SchemaCreated
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(next), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// inferNode merges the values seen at one place in the samples.
+type inferNode struct {
+	types map[string]bool
+	// strings counts each string value; objects and present count the
+	// objects seen and how many of them had each property.
+	strings    map[string]int
+	objects    int
+	properties map[string]*inferNode
+	present    map[string]int
+	items      *inferNode
+}
+
+func (n *inferNode) add(v any) {
+	if n.types == nil {
+		n.types = map[string]bool{}
+	}
+	switch x := v.(type) {
+	case nil:
+		n.types["null"] = true
+	case bool:
+		n.types["boolean"] = true
+	case float64:
+		if x == math.Trunc(x) && !math.IsInf(x, 0) {
+			n.types["integer"] = true
+		} else {
+			n.types["number"] = true
+		}
+	case string:
+		n.types["string"] = true
+		if n.strings == nil {
+			n.strings = map[string]int{}
+		}
+		n.strings[x]++
+	case []any:
+		n.types["array"] = true
+		if n.items == nil {
+			n.items = &inferNode{}
+		}
+		for _, item := range x {
+			n.items.add(item)
+		}
+	case map[string]any:
+		n.types["object"] = true
+		n.objects++
+		if n.properties == nil {
+			n.properties = map[string]*inferNode{}
+			n.present = map[string]int{}
+		}
+		for key, child := range x {
+			if n.properties[key] == nil {
+				n.properties[key] = &inferNode{}
+			}
+			n.properties[key].add(child)
+			n.present[key]++
+		}
+	}
+}
+
+// inferredSchema is the JSON Schema rendering of an inferNode. It encodes
+// its keys in reading order: those in schemaKeyOrder first, then the rest
+// sorted.
+type inferredSchema map[string]any
+
+// inferredKeywords are the keywords schema infers; it keeps every other
+// keyword of the current schema.
+var inferredKeywords = []string{"type", "enum", "properties", "required", "items"}
+
+var schemaKeyOrder = []string{"$schema", "$id", "title", "type", "description", "enum", "properties", "required", "items"}
+
+func (s inferredSchema) MarshalJSON() ([]byte, error) {
+	keys := make([]string, 0, len(s))
+	ordered := map[string]bool{}
+	for _, k := range schemaKeyOrder {
+		ordered[k] = true
+		if _, ok := s[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range sortedKeys(s) {
+		if !ordered[k] {
+			keys = append(keys, k)
+		}
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(s[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// schemaTypeOrder is the order a property's types are listed in when it has
+// several.
+var schemaTypeOrder = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// schema renders n over current, the schema currently at the same place:
+// the inferred keywords replace those of current and its other keywords are
+// kept. A current schema that uses $ref is kept whole.
+func (n *inferNode) schema(current map[string]any) inferredSchema {
+	s := inferredSchema{}
+	for k, v := range current {
+		s[k] = v
+	}
+	if _, ok := current["$ref"]; ok {
+		return s
+	}
+	for _, k := range inferredKeywords {
+		delete(s, k)
+	}
+	var types []string
+	for _, t := range schemaTypeOrder {
+		// Numbers include integers.
+		if n.types[t] && (t != "integer" || !n.types["number"]) {
+			types = append(types, t)
+		}
+	}
+	switch len(types) {
+	case 0:
+	case 1:
+		s["type"] = types[0]
+	default:
+		s["type"] = types
+	}
+
+	if len(types) == 1 && types[0] == "string" && len(n.strings) >= 2 && len(n.strings) <= inferEnumMax {
+		total := 0
+		for _, count := range n.strings {
+			total += count
+		}
+		// Only values that repeat look like a fixed set rather than
+		// free text; a single repeated value is more likely copied
+		// between fixtures than a constant.
+		if total > len(n.strings) {
+			s["enum"] = sortedKeys(n.strings)
+		}
+	}
+	if n.properties != nil {
+		currentProps, _ := current["properties"].(map[string]any)
+		props := make(map[string]inferredSchema, len(n.properties))
+		var required []string
+		for key, child := range n.properties {
+			childCurrent, _ := currentProps[key].(map[string]any)
+			props[key] = child.schema(childCurrent)
+			if n.present[key] == n.objects {
+				required = append(required, key)
+			}
+		}
+		sort.Strings(required)
+		s["properties"] = props
+		if len(required) > 0 {
+			s["required"] = required
+		}
+	}
+	if n.items != nil && n.items.types != nil {
+		itemsCurrent, _ := current["items"].(map[string]any)
+		s["items"] = n.items.schema(itemsCurrent)
+	}
+	return s
+}
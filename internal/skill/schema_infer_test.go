@@ -0,0 +1,204 @@
+package skill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferSchemasFromFixtures(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"schema.input.json": `{"type":"object","properties":{"q":{"type":"string","description":"The question"}}}`,
+		"tests/fixture_01.json": `{"q":"one","mode":"fast","limit":3,"tags":["a"],
+			"filter":{"lang":"go"}}`,
+		"tests/fixture_02.json":  `{"q":"two","mode":"fast","limit":2.5,"filter":{"lang":"go","since":null}}`,
+		"tests/fixture_03.json":  `{"q":"three","mode":"deep","filter":{"lang":"rust"}}`,
+		"tests/expected_01.json": `{"answer":"re: one","count":1}`,
+		"tests/expected_02.json": `{"answer":{"$regex":"^re:"}}`,
+	})
+
+	results, err := InferSchemas(dir, false)
+	if err != nil {
+		t.Fatalf("infer: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected input and output results, got %+v", results)
+	}
+	in, out := results[0], results[1]
+	if in.Kind != "input" || in.Status != SchemaStale || len(in.Samples) != 3 {
+		t.Fatalf("unexpected input result %+v", in)
+	}
+	if !strings.Contains(in.Diff, `+      "enum": [`) || !strings.Contains(in.Diff, `"description": "The question"`) {
+		t.Fatalf("diff should add enums and keep descriptions:\n%s", in.Diff)
+	}
+	if out.Kind != "output" || out.Status != SchemaStale || !reflect.DeepEqual(out.Skipped, []string{"expected_02.json"}) {
+		t.Fatalf("unexpected output result %+v", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "schema.input.json"))
+	if strings.Contains(string(data), "mode") {
+		t.Fatal("schema file should not be written without write")
+	}
+
+	results, err = InferSchemas(dir, true)
+	if err != nil {
+		t.Fatalf("infer write: %v", err)
+	}
+	if results[0].Status != SchemaUpdated || results[1].Status != SchemaUpdated {
+		t.Fatalf("expected updated schemas, got %+v", results)
+	}
+	var schema map[string]any
+	data, _ = os.ReadFile(filepath.Join(dir, "schema.input.json"))
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"q":     map[string]any{"type": "string", "description": "The question"},
+			"mode":  map[string]any{"type": "string", "enum": []any{"deep", "fast"}},
+			"limit": map[string]any{"type": "number"},
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"filter": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"lang":  map[string]any{"type": "string", "enum": []any{"go", "rust"}},
+					"since": map[string]any{"type": "null"},
+				},
+				"required": []any{"lang"},
+			},
+		},
+		"required": []any{"filter", "mode", "q"},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Fatalf("unexpected input schema:\n%s", data)
+	}
+	if err := ValidateJSONSchema(filepath.Join(dir, "schema.input.json")); err != nil {
+		t.Fatalf("inferred schema is invalid: %v", err)
+	}
+	if _, err := RunFixtureSuite(dir); err != nil {
+		t.Fatalf("fixtures should still load: %v", err)
+	}
+
+	results, err = InferSchemas(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != SchemaUnchanged || results[0].Diff != "" || results[1].Status != SchemaUnchanged {
+		t.Fatalf("expected unchanged schemas, got %+v", results)
+	}
+}
+
+func TestInferSchemasMixedTypesAndMissingFiles(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"tests/fixture_01.json":  `{"id":1,"when":"today"}`,
+		"tests/fixture_02.json":  `{"id":"x-2","when":null}`,
+		"tests/expected_01.json": `{"$.answer":"re: one"}`,
+	})
+	if err := os.Remove(filepath.Join(dir, "schema.input.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := InferSchemas(dir, true)
+	if err != nil {
+		t.Fatalf("infer: %v", err)
+	}
+	if results[0].Status != SchemaCreated || !strings.HasPrefix(results[0].Diff, "+{") {
+		t.Fatalf("expected created input schema, got %+v", results[0])
+	}
+	if results[1].Status != SchemaSkipped || len(results[1].Samples) != 0 {
+		t.Fatalf("output schema without literal samples should be skipped, got %+v", results[1])
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "schema.input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"type": [`, `"null"`, `"integer"`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("schema missing %s:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "enum") {
+		t.Fatalf("values that never repeat should not become enums:\n%s", data)
+	}
+}
+
+func TestInferSchemasErrors(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"skill.yaml":            "id: snap-skill\nversion: 0.1.0\ninputs:\n  schema: ../schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"tests/fixture_01.json": `{"q":"one"}`,
+	})
+	if _, err := InferSchemas(dir, false); err == nil || !strings.Contains(err.Error(), "outside the skill directory") {
+		t.Fatalf("expected outside error, got %v", err)
+	}
+
+	dir = writeSnapshotSkill(t, map[string]string{"tests/fixture_01.json": `["q"]`})
+	if _, err := InferSchemas(dir, false); err == nil || !strings.Contains(err.Error(), "fixture_01.json") {
+		t.Fatalf("expected parse error naming the fixture, got %v", err)
+	}
+}
+
+func TestInferSchemasKeepsUnknownKeywords(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"schema.input.json": `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","additionalProperties":false,
+			"$defs":{"email":{"type":"string","format":"email"}},
+			"properties":{"q":{"type":"string","minLength":1,"pattern":"^[a-z]"},"from":{"$ref":"#/$defs/email"}}}`,
+		"tests/fixture_01.json": `{"q":"one","from":"a@example.com","limit":3}`,
+		"tests/fixture_02.json": `{"q":"two","from":"b@example.com"}`,
+	})
+	results, err := InferSchemas(dir, true)
+	if err != nil {
+		t.Fatalf("infer write: %v", err)
+	}
+	if results[0].Status != SchemaUpdated {
+		t.Fatalf("expected the input schema to be updated, got %+v", results[0])
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "schema.input.json"))
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"additionalProperties": false,
+		"$defs":                map[string]any{"email": map[string]any{"type": "string", "format": "email"}},
+		"properties": map[string]any{
+			"q":     map[string]any{"type": "string", "minLength": float64(1), "pattern": "^[a-z]"},
+			"from":  map[string]any{"$ref": "#/$defs/email"},
+			"limit": map[string]any{"type": "integer"},
+		},
+		"required": []any{"from", "q"},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Fatalf("unexpected schema:\n%s", data)
+	}
+	if !strings.HasPrefix(string(data), "{\n  \"$schema\"") {
+		t.Fatalf("keys should be in reading order:\n%s", data)
+	}
+
+	results, err = InferSchemas(dir, true)
+	if err != nil || results[0].Status != SchemaUnchanged {
+		t.Fatalf("expected the written schema to be unchanged, got %+v, %v", results, err)
+	}
+}
+
+func TestInferSchemasRefusesToOverwriteInvalidSchema(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"schema.input.json":     `{"type": "object",`,
+		"tests/fixture_01.json": `{"q":"one"}`,
+	})
+	results, err := InferSchemas(dir, false)
+	if err != nil || results[0].Status != SchemaStale {
+		t.Fatalf("expected a stale input schema, got %+v, %v", results, err)
+	}
+	if _, err := InferSchemas(dir, true); err == nil || !strings.Contains(err.Error(), "schema.input.json is not a JSON schema object") {
+		t.Fatalf("expected write to refuse the invalid schema, got %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "schema.input.json"))
+	if string(data) != `{"type": "object",` {
+		t.Fatalf("invalid schema should be left alone, got %s", data)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/felixgeelhaar/aios/internal/policy"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,12 +18,50 @@ type SkillSpec struct {
 	Name        string `yaml:"name"`
 	Version     string `yaml:"version"`
 	Description string `yaml:"description"`
-	Inputs      struct {
+	Author      string `yaml:"author"`
+	// License is an SPDX license identifier or expression, such as "MIT" or
+	// "Apache-2.0 OR MIT".
+	License  string   `yaml:"license"`
+	Tags     []string `yaml:"tags"`
+	Homepage string   `yaml:"homepage"`
+	// AllowedTools lists the agent tools the skill may use; SKILL.md
+	// declares them as allowed-tools. Empty means DefaultAllowedTools.
+	AllowedTools []string `yaml:"allowed_tools"`
+	// Connectors names the connectors, such as "google-drive", the skill
+	// needs credentials for.
+	Connectors []string `yaml:"connectors"`
+	// Clients lists the agents the skill is compatible with, such as
+	// "claude-code" or "cursor". It restricts sync and marketplace
+	// publishing to them; empty means every agent.
+	Clients []string `yaml:"clients"`
+	// MinAIOSVersion is the oldest aios release that can install the skill.
+	MinAIOSVersion string `yaml:"min_aios_version"`
+	// Model holds the skill's model routing preferences.
+	Model ModelPreferences `yaml:"model"`
+	// Timeout bounds each execution of the skill, as a Go duration such as
+	// "30s". Empty means the executor's default.
+	Timeout string `yaml:"timeout"`
+	// Guardrails names policy guardrails, such as "no-pii" or
+	// "content-filter", that run on every execution's input and output.
+	Guardrails []string `yaml:"guardrails"`
+	Inputs     struct {
 		Schema string `yaml:"schema"`
 	} `yaml:"inputs"`
 	Outputs struct {
 		Schema string `yaml:"schema"`
 	} `yaml:"outputs"`
+	// Install selects the supporting files sync copies into the installed
+	// skill directory. See InstallableFiles.
+	Install struct {
+		Include []string `yaml:"include"`
+		Exclude []string `yaml:"exclude"`
+	} `yaml:"install"`
+	// Rules controls when agent rule formats such as Cursor .mdc files
+	// apply the skill. See Renderer.
+	Rules struct {
+		Globs       []string `yaml:"globs"`
+		AlwaysApply bool     `yaml:"always_apply"`
+	} `yaml:"rules"`
 }
 
 func LoadSkillSpec(path string) (SkillSpec, error) {
@@ -45,10 +85,22 @@ var semverRe = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
 	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
 	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
+// ValidateSkillSpec checks spec, loaded from baseDir, with its guardrails
+// resolved against the built-in guardrails.
 func ValidateSkillSpec(baseDir string, spec SkillSpec) error {
+	return ValidateSkillSpecWithGuardrails(baseDir, spec, policy.NewGuardrailRegistry())
+}
+
+// ValidateSkillSpecWithGuardrails checks spec like ValidateSkillSpec, with
+// its guardrails resolved against guardrails, such as the registry of the
+// executor that will run the skill.
+func ValidateSkillSpecWithGuardrails(baseDir string, spec SkillSpec, guardrails *policy.GuardrailRegistry) error {
 	if spec.ID == "" {
 		return fmt.Errorf("id is required")
 	}
+	if err := ValidateID(spec.ID); err != nil {
+		return err
+	}
 	if spec.Version == "" {
 		return fmt.Errorf("version is required")
 	}
@@ -64,12 +116,40 @@ func ValidateSkillSpec(baseDir string, spec SkillSpec) error {
 	if err := ValidateJSONSchema(filepath.Join(baseDir, spec.Outputs.Schema)); err != nil {
 		return fmt.Errorf("invalid output schema: %w", err)
 	}
+	if _, err := LoadSkillSchemas(baseDir, spec); err != nil {
+		return err
+	}
+	if _, err := spec.ExecutionTimeout(); err != nil {
+		return err
+	}
+	if err := validateMetadata(spec, guardrails); err != nil {
+		return err
+	}
+	if err := validateInstallPatterns(spec); err != nil {
+		return err
+	}
 	return nil
 }
 
+// ExecutionTimeout parses Timeout, returning zero when it is empty.
+func (s SkillSpec) ExecutionTimeout() (time.Duration, error) {
+	if strings.TrimSpace(s.Timeout) == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s.Timeout))
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", s.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", s.Timeout)
+	}
+	return d, nil
+}
+
 // BuildSkillMd composes a SKILL.md from a spec and prompt body. The result
 // follows the Claude Code SKILL.md frontmatter format with name, description,
-// and allowed-tools fields followed by the prompt content.
+// and allowed-tools fields followed by the prompt content. allowed-tools
+// lists spec.AllowedTools, or DefaultAllowedTools when none are declared.
 func BuildSkillMd(spec SkillSpec, promptBody string) string {
 	name := spec.Name
 	if name == "" {
@@ -80,7 +160,11 @@ func BuildSkillMd(spec SkillSpec, promptBody string) string {
 	b.WriteString("---\n")
 	fmt.Fprintf(&b, "name: %s\n", name)
 	fmt.Fprintf(&b, "description: %s\n", desc)
-	b.WriteString("allowed-tools: Read, Grep, Glob\n")
+	tools := spec.AllowedTools
+	if len(tools) == 0 {
+		tools = DefaultAllowedTools
+	}
+	fmt.Fprintf(&b, "allowed-tools: %s\n", strings.Join(tools, ", "))
 	b.WriteString("---\n")
 	if promptBody != "" {
 		b.WriteString("\n")
@@ -97,14 +181,19 @@ func LoadAndBuildSkillMd(skillDir string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return BuildSkillMd(spec, LoadPrompt(skillDir)), nil
+}
+
+// LoadPrompt returns the prompt.md body in skillDir. prompt.md is optional;
+// a missing file yields an empty prompt.
+func LoadPrompt(skillDir string) string {
 	promptPath := filepath.Join(filepath.Clean(skillDir), "prompt.md")
 	// #nosec G304 -- path is derived from validated skill directory.
 	promptData, err := os.ReadFile(promptPath)
 	if err != nil {
-		// prompt.md is optional; fall back to spec-only SKILL.md.
-		return BuildSkillMd(spec, ""), nil
+		return ""
 	}
-	return BuildSkillMd(spec, string(promptData)), nil
+	return string(promptData)
 }
 
 func ValidateJSONSchema(path string) error {
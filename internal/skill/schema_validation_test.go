@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateSkillSpec(t *testing.T) {
@@ -31,6 +32,30 @@ func TestValidateSkillSpec(t *testing.T) {
 	}
 }
 
+func TestSkillSpecExecutionTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		timeout string
+		want    time.Duration
+		err     string
+	}{
+		{"", 0, ""},
+		{"45s", 45 * time.Second, ""},
+		{"soon", 0, `invalid timeout "soon"`},
+		{"-1s", 0, "must be positive"},
+	} {
+		got, err := SkillSpec{Timeout: tc.timeout}.ExecutionTimeout()
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%q: expected error containing %q, got %v", tc.timeout, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%q: expected %s, got %s (%v)", tc.timeout, tc.want, got, err)
+		}
+	}
+}
+
 func TestValidateJSONSchemaRejectsNonObject(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "schema.json")
@@ -127,6 +152,16 @@ func TestBuildSkillMd_WithDescriptionAndPrompt(t *testing.T) {
 	}
 }
 
+func TestBuildSkillMd_AllowedTools(t *testing.T) {
+	if got := BuildSkillMd(SkillSpec{ID: "s"}, ""); !strings.Contains(got, "allowed-tools: Read, Grep, Glob\n") {
+		t.Errorf("expected default allowed-tools, got:\n%s", got)
+	}
+	got := BuildSkillMd(SkillSpec{ID: "s", AllowedTools: []string{"Read", "Bash(git:*)"}}, "")
+	if !strings.Contains(got, "allowed-tools: Read, Bash(git:*)\n") {
+		t.Errorf("expected declared allowed-tools, got:\n%s", got)
+	}
+}
+
 func TestBuildSkillMd_UsesNameOverID(t *testing.T) {
 	spec := SkillSpec{ID: "my-skill", Name: "My Custom Skill", Description: "desc"}
 	got := BuildSkillMd(spec, "")
@@ -0,0 +1,243 @@
+package skill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// SnapshotStatus is the outcome of snapshotting one fixture.
+type SnapshotStatus string
+
+const (
+	// SnapshotUnchanged means the expected file already matches the output.
+	SnapshotUnchanged SnapshotStatus = "unchanged"
+	// SnapshotCreated means a missing expected file was written.
+	SnapshotCreated SnapshotStatus = "created"
+	// SnapshotUpdated means an outdated expected file was rewritten.
+	SnapshotUpdated SnapshotStatus = "updated"
+	// SnapshotStale means the expected file is missing or outdated and was
+	// left alone because snapshots were only being checked.
+	SnapshotStale SnapshotStatus = "stale"
+	// SnapshotSkipped means the expected file uses assertions such as
+	// $regex or JSONPath selectors and is maintained by hand.
+	SnapshotSkipped SnapshotStatus = "skipped"
+	// SnapshotFailed means the fixture could not be executed.
+	SnapshotFailed SnapshotStatus = "error"
+)
+
+// snapshotDiffContext is how many unchanged lines a snapshot diff keeps
+// around each change.
+const snapshotDiffContext = 3
+
+// SnapshotResult reports one fixture's expected file.
+type SnapshotResult struct {
+	Name     string         `json:"name"`
+	Expected string         `json:"expected"`
+	Status   SnapshotStatus `json:"status"`
+	// Diff is a line diff from the old expected file to the new output,
+	// set when they differ.
+	Diff  string `json:"diff,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErrNoHandler is returned by SnapshotFixtures when exec has neither a
+// handler nor a fallback for the skill, so its output would be StubOutput.
+var ErrNoHandler = errors.New("no handler configured")
+
+// SnapshotFixtures runs every fixture through exec and compares the output
+// with its expected file. With write, missing and outdated expected files are
+// (re)written as indented JSON; otherwise they are reported as stale. Expected
+// files are compared by value, so reformatting one does not make it stale.
+// Expected files that use assertions are skipped, never overwritten. Stub
+// output is never recorded: without a handler for the skill it returns
+// ErrNoHandler.
+func SnapshotFixtures(skillDir string, exec *Executor, write bool) ([]SnapshotResult, error) {
+	suite, err := loadFixtureSuite(skillDir, exec)
+	if err != nil {
+		return nil, err
+	}
+	if exec.handler(suite.artifact.ID) == nil {
+		return nil, fmt.Errorf("%w for skill %s", ErrNoHandler, suite.artifact.ID)
+	}
+	results := make([]SnapshotResult, 0, len(suite.cases))
+	for _, c := range suite.cases {
+		res := snapshotFixture(suite.artifact, exec, c, write)
+		if res.Error != "" {
+			res.Status = SnapshotFailed
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func snapshotFixture(artifact Artifact, exec *Executor, c fixtureCase, write bool) SnapshotResult {
+	res := SnapshotResult{Name: c.name, Expected: filepath.Base(c.expectedPath)}
+	input, err := readJSONMap(c.fixturePath)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	out, err := exec.Execute(artifact, input)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	normalized, err := normalizeJSON(out)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	body, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	next := string(body) + "\n"
+
+	path := filepath.Clean(c.expectedPath)
+	// #nosec G304 -- path is derived from validated tests directory entries.
+	data, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		res.Error = fmt.Sprintf("read %s: %v", path, err)
+		return res
+	}
+
+	prev := ""
+	if exists {
+		var current any
+		if json.Unmarshal(data, &current) == nil {
+			if usesAssertions(current) {
+				res.Status = SnapshotSkipped
+				return res
+			}
+			if reflect.DeepEqual(current, normalized) {
+				res.Status = SnapshotUnchanged
+				return res
+			}
+			pretty, _ := json.MarshalIndent(current, "", "  ")
+			prev = string(pretty) + "\n"
+		} else {
+			prev = string(data)
+		}
+	}
+	res.Diff = lineDiff(prev, next)
+
+	switch {
+	case !write:
+		res.Status = SnapshotStale
+	case exists:
+		res.Status = SnapshotUpdated
+	default:
+		res.Status = SnapshotCreated
+	}
+	if write {
+		if err := os.WriteFile(path, []byte(next), 0o600); err != nil {
+			res.Error = fmt.Sprintf("write %s: %v", path, err)
+		}
+	}
+	return res
+}
+
+// usesAssertions reports whether an expected document contains assertion
+// operators or JSONPath selectors, which only hand-written files have.
+func usesAssertions(v any) bool {
+	switch x := v.(type) {
+	case map[string]any:
+		for key, child := range x {
+			if strings.HasPrefix(key, "$") || usesAssertions(child) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range x {
+			if usesAssertions(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lineDiff renders the changes from a to b, one line per entry prefixed with
+// "-", "+" or " ", keeping snapshotDiffContext lines of context around each
+// change and eliding the rest with "...".
+func lineDiff(a, b string) string {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, diffLine{' ', x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', x[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', y[j]})
+			j++
+		}
+	}
+
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(0, k-snapshotDiffContext); c <= min(len(lines)-1, k+snapshotDiffContext); c++ {
+			keep[c] = true
+		}
+	}
+	var sb strings.Builder
+	elided := false
+	for k, l := range lines {
+		if !keep[k] {
+			if !elided {
+				sb.WriteString("...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
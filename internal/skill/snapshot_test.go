@@ -0,0 +1,140 @@
+package skill
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSnapshotSkill(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	base := map[string]string{
+		"skill.yaml":         "id: snap-skill\nversion: 0.1.0\ninputs:\n  schema: schema.input.json\noutputs:\n  schema: schema.output.json\n",
+		"schema.input.json":  `{"type":"object","properties":{"q":{"type":"string"}}}`,
+		"schema.output.json": `{"type":"object","properties":{"answer":{"type":"string"}}}`,
+	}
+	for name, body := range files {
+		base[name] = body
+	}
+	for name, body := range base {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func snapshotExecutor() *Executor {
+	exec := NewExecutor()
+	exec.RegisterHandler("snap-skill", func(_ Artifact, input map[string]any) (map[string]any, error) {
+		return map[string]any{"answer": "re: " + input["q"].(string), "count": 2}, nil
+	})
+	return exec
+}
+
+func TestSnapshotFixturesCheckAndUpdate(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"tests/fixture_01.json":  `{"q":"one"}`,
+		"tests/fixture_02.json":  `{"q":"two"}`,
+		"tests/expected_02.json": `{"answer":"re: old","count":2}`,
+		"tests/fixture_03.json":  `{"q":"three"}`,
+		"tests/expected_03.json": `{"count": 2, "answer": "re: three"}`,
+		"tests/fixture_04.json":  `{"q":"four"}`,
+		"tests/expected_04.json": `{"answer":{"$regex":"^re:"}}`,
+	})
+
+	results, err := SnapshotFixtures(dir, snapshotExecutor(), false)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	want := []SnapshotStatus{SnapshotStale, SnapshotStale, SnapshotUnchanged, SnapshotSkipped}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s", r.Name, want[i], r.Status)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tests", "expected_01.json")); !os.IsNotExist(err) {
+		t.Fatal("check must not write snapshots")
+	}
+	if !strings.Contains(results[1].Diff, `-  "answer": "re: old",`) || !strings.Contains(results[1].Diff, `+  "answer": "re: two",`) {
+		t.Fatalf("unexpected diff:\n%s", results[1].Diff)
+	}
+
+	results, err = SnapshotFixtures(dir, snapshotExecutor(), true)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	want = []SnapshotStatus{SnapshotCreated, SnapshotUpdated, SnapshotUnchanged, SnapshotSkipped}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s", r.Name, want[i], r.Status)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "tests", "expected_01.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{\n  \"answer\": \"re: one\",\n  \"count\": 2\n}\n" {
+		t.Fatalf("unexpected snapshot:\n%s", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "tests", "expected_04.json")); string(data) != `{"answer":{"$regex":"^re:"}}` {
+		t.Fatalf("assertion file was rewritten:\n%s", data)
+	}
+
+	fixtures, err := RunFixtureSuiteWithExecutor(dir, snapshotExecutor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fixtures {
+		if !f.Passed {
+			t.Errorf("%s should pass after update: %s", f.Name, f.Error)
+		}
+	}
+}
+
+func TestSnapshotFixturesReportsExecutionErrors(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{
+		"schema.input.json":     `{"type":"object","required":["q"],"properties":{"q":{"type":"string"}}}`,
+		"tests/fixture_01.json": `{"other":"x"}`,
+	})
+	results, err := SnapshotFixtures(dir, snapshotExecutor(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != SnapshotFailed || !strings.Contains(results[0].Error, "/q: required property is missing") {
+		t.Fatalf("unexpected result: %#v", results)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tests", "expected_01.json")); !os.IsNotExist(err) {
+		t.Fatal("failed fixture must not write a snapshot")
+	}
+}
+
+func TestSnapshotFixturesRefusesStubOutput(t *testing.T) {
+	dir := writeSnapshotSkill(t, map[string]string{"tests/fixture_01.json": `{"q":"x"}`})
+	for _, write := range []bool{true, false} {
+		if _, err := SnapshotFixtures(dir, NewExecutor(), write); !errors.Is(err, ErrNoHandler) {
+			t.Fatalf("expected ErrNoHandler with write=%v, got %v", write, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tests", "expected_01.json")); !os.IsNotExist(err) {
+		t.Fatal("stub output must not be recorded")
+	}
+}
+
+func TestLineDiffKeepsContext(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n"
+	want := "...\n 3\n 4\n 5\n-6\n+six\n 7\n 8\n 9\n...\n"
+	if got := lineDiff(a, b); got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	if got := lineDiff("", "{}\n"); got != "+{}\n" {
+		t.Fatalf("unexpected diff for new file: %q", got)
+	}
+}
@@ -0,0 +1,501 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Prompt templates interpolate values and include shared fragments:
+//
+//	{{ input.query }}              the input value at a dotted path
+//	{{#if trigger.code}}...{{/if}}  text kept when the code section is active
+//	{{#if input.verbose}}...{{else}}...{{/if}}
+//	{{#unless input.limit}}...{{/unless}}
+//	{{> tone }}                     the partial partials/tone.md
+//
+// Block tags and partials alone on a line take the line with them. Any other
+// {{ }} text, such as ${{ secrets.TOKEN }} in a GitHub Actions example or a
+// Helm {{ .Values.image }}, is kept as literal text.
+
+// maxPartialDepth bounds nested partial includes, which also stops cycles.
+const maxPartialDepth = 10
+
+var (
+	partialNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+(?:/[A-Za-z0-9_-]+)*$`)
+	refSegmentRe  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	partialTagRe  = regexp.MustCompile(`\{\{\s*>\s*([^}]*?)\s*\}\}`)
+)
+
+type templateNodeKind int
+
+const (
+	textNode templateNodeKind = iota
+	valueNode
+	ifNode
+	partialNode
+)
+
+type templateNode struct {
+	kind templateNodeKind
+	// text is the literal text of a text node.
+	text string
+	// ref is the reference of a value or if node.
+	ref templateRef
+	// name is the partial a partial node includes.
+	name string
+	// negate makes an if node an unless node.
+	negate    bool
+	then, els []templateNode
+	line      int
+}
+
+// templateRef is a dotted reference such as input.user.name or trigger.code.
+type templateRef struct {
+	scope string
+	path  []string
+}
+
+func (r templateRef) String() string {
+	return strings.Join(append([]string{r.scope}, r.path...), ".")
+}
+
+// TemplateError reports a malformed template or a failed include.
+type TemplateError struct {
+	// Source is the file or section the template came from.
+	Source string
+	Line   int
+	Msg    string
+}
+
+func (e *TemplateError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Source, e.Msg)
+}
+
+type templateToken struct {
+	tag  bool
+	text string
+	// raw is a tag as written, braces included.
+	raw  string
+	line int
+}
+
+// tokenizeTemplate splits src, whose first line is line, into literal text
+// and {{ }} tags, dropping the line around block tags and partials that stand
+// alone on it.
+func tokenizeTemplate(source string, line int, src string) ([]templateToken, error) {
+	var tokens []templateToken
+	for {
+		start := strings.Index(src, "{{")
+		if start < 0 {
+			tokens = append(tokens, templateToken{text: src, line: line})
+			break
+		}
+		end := strings.Index(src[start:], "}}")
+		if end < 0 {
+			return nil, &TemplateError{Source: source, Line: line + strings.Count(src[:start], "\n"), Msg: "unclosed {{"}
+		}
+		tokens = append(tokens, templateToken{text: src[:start], line: line})
+		line += strings.Count(src[:start], "\n")
+		tag := src[start+2 : start+end]
+		tokens = append(tokens, templateToken{tag: true, text: strings.TrimSpace(tag), raw: src[start : start+end+2], line: line})
+		line += strings.Count(tag, "\n")
+		src = src[start+end+2:]
+	}
+
+	// Tokens alternate text, tag, text, ..., text. Decide which tags stand
+	// alone on the original text, then cut their lines from the neighbours.
+	from := make([]int, len(tokens))
+	to := make([]int, len(tokens))
+	for i, tok := range tokens {
+		to[i] = len(tok.text)
+	}
+	for i := 1; i < len(tokens); i += 2 {
+		if !standaloneTag(tokens[i].text) {
+			continue
+		}
+		prev, next := tokens[i-1].text, tokens[i+1].text
+		lineStart := strings.LastIndexByte(prev, '\n') + 1
+		if strings.TrimSpace(prev[lineStart:]) != "" || (lineStart == 0 && i > 1) {
+			continue
+		}
+		lineEnd := strings.IndexByte(next, '\n')
+		if lineEnd < 0 && i+2 < len(tokens) {
+			continue
+		}
+		rest := next
+		if lineEnd >= 0 {
+			rest = next[:lineEnd+1]
+		}
+		if strings.TrimSpace(rest) != "" {
+			continue
+		}
+		to[i-1] = lineStart
+		from[i+1] = len(rest)
+	}
+	for i := 0; i < len(tokens); i += 2 {
+		if from[i] > to[i] {
+			to[i] = from[i]
+		}
+		tokens[i].line += strings.Count(tokens[i].text[:from[i]], "\n")
+		tokens[i].text = tokens[i].text[from[i]:to[i]]
+	}
+	return tokens, nil
+}
+
+func standaloneTag(tag string) bool {
+	return strings.HasPrefix(tag, "#") || strings.HasPrefix(tag, "/") || strings.HasPrefix(tag, ">") || tag == "else"
+}
+
+// parseTemplate parses src into a node tree. source and line, the line src
+// starts at, place it in errors.
+func parseTemplate(source string, line int, src string) ([]templateNode, error) {
+	tokens, err := tokenizeTemplate(source, line, src)
+	if err != nil {
+		return nil, err
+	}
+	p := templateParser{source: source, tokens: tokens}
+	nodes, closer, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if closer != nil {
+		return nil, &TemplateError{Source: source, Line: closer.line, Msg: fmt.Sprintf("unexpected {{%s}}", closer.text)}
+	}
+	return nodes, nil
+}
+
+type templateParser struct {
+	source string
+	tokens []templateToken
+	pos    int
+}
+
+// parse reads nodes until the tokens run out or a closing tag ({{else}} or
+// {{/...}}) is reached, which it returns.
+func (p *templateParser) parse() ([]templateNode, *templateToken, error) {
+	var nodes []templateNode
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		p.pos++
+		if !tok.tag {
+			if tok.text != "" {
+				nodes = append(nodes, templateNode{kind: textNode, text: tok.text, line: tok.line})
+			}
+			continue
+		}
+		switch {
+		case tok.text == "else" || strings.HasPrefix(tok.text, "/"):
+			return nodes, &tok, nil
+		case strings.HasPrefix(tok.text, ">"):
+			name := strings.TrimSpace(strings.TrimPrefix(tok.text, ">"))
+			if !partialNameRe.MatchString(name) {
+				return nil, nil, p.errorf(tok, "invalid partial name %q", name)
+			}
+			nodes = append(nodes, templateNode{kind: partialNode, name: name, line: tok.line})
+		case strings.HasPrefix(tok.text, "#"):
+			node, err := p.parseBlock(tok)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, node)
+		case !templateReference(tok.text):
+			nodes = append(nodes, templateNode{kind: textNode, text: tok.raw, line: tok.line})
+		default:
+			ref, err := p.parseRef(tok, tok.text)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ref.scope != "input" {
+				return nil, nil, p.errorf(tok, "only input values can be interpolated, got %q", tok.text)
+			}
+			nodes = append(nodes, templateNode{kind: valueNode, ref: ref, line: tok.line})
+		}
+	}
+	return nodes, nil, nil
+}
+
+// templateReference reports whether the tag s names an input or trigger
+// value, which makes it template syntax rather than literal text.
+func templateReference(s string) bool {
+	scope, _, _ := strings.Cut(s, ".")
+	return scope == "input" || scope == "trigger"
+}
+
+func (p *templateParser) parseBlock(open templateToken) (templateNode, error) {
+	keyword, arg, _ := strings.Cut(strings.TrimPrefix(open.text, "#"), " ")
+	if keyword != "if" && keyword != "unless" {
+		return templateNode{}, p.errorf(open, "unknown block {{#%s}}", keyword)
+	}
+	ref, err := p.parseRef(open, strings.TrimSpace(arg))
+	if err != nil {
+		return templateNode{}, err
+	}
+	node := templateNode{kind: ifNode, ref: ref, negate: keyword == "unless", line: open.line}
+	var closer *templateToken
+	if node.then, closer, err = p.parse(); err != nil {
+		return templateNode{}, err
+	}
+	if closer != nil && closer.text == "else" {
+		if node.els, closer, err = p.parse(); err != nil {
+			return templateNode{}, err
+		}
+	}
+	if closer == nil {
+		return templateNode{}, p.errorf(open, "{{#%s}} is not closed", keyword)
+	}
+	if strings.TrimSpace(strings.TrimPrefix(closer.text, "/")) != keyword {
+		return templateNode{}, p.errorf(*closer, "{{%s}} does not close {{#%s}}", closer.text, keyword)
+	}
+	return node, nil
+}
+
+func (p *templateParser) parseRef(tok templateToken, s string) (templateRef, error) {
+	parts := strings.Split(s, ".")
+	ref := templateRef{scope: parts[0], path: parts[1:]}
+	for _, seg := range ref.path {
+		if !refSegmentRe.MatchString(seg) {
+			return templateRef{}, p.errorf(tok, "invalid reference %q", s)
+		}
+	}
+	switch {
+	case ref.scope == "input":
+	case ref.scope == "trigger" && len(ref.path) == 1:
+	default:
+		return templateRef{}, p.errorf(tok, "invalid reference %q; use input.<field> or trigger.<section>", s)
+	}
+	return ref, nil
+}
+
+func (p *templateParser) errorf(tok templateToken, format string, args ...any) error {
+	return &TemplateError{Source: p.source, Line: tok.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// templateEnv holds what a template renders against.
+type templateEnv struct {
+	input       map[string]any
+	triggers    map[string]bool
+	partialDirs []string
+	// baseDir is what partial paths in errors are relative to.
+	baseDir string
+	// partials caches parsed partials by name.
+	partials map[string][]templateNode
+}
+
+// loadPartial parses the partial name from the first partial directory that
+// has it.
+func (env *templateEnv) loadPartial(name string) ([]templateNode, string, error) {
+	data, path, err := readPartial(env.partialDirs, name)
+	if err != nil {
+		return nil, path, err
+	}
+	if rel, err := filepath.Rel(env.baseDir, path); err == nil && env.baseDir != "" {
+		path = filepath.ToSlash(rel)
+	}
+	if nodes, ok := env.partials[name]; ok {
+		return nodes, path, nil
+	}
+	nodes, err := parseTemplate(path, 1, data)
+	if err != nil {
+		return nil, path, err
+	}
+	if env.partials == nil {
+		env.partials = make(map[string][]templateNode)
+	}
+	env.partials[name] = nodes
+	return nodes, path, nil
+}
+
+// readPartial reads the partial name from the first of dirs that has it.
+func readPartial(dirs []string, name string) (string, string, error) {
+	file := filepath.FromSlash(name) + ".md"
+	for _, dir := range dirs {
+		path := filepath.Join(dir, file)
+		// #nosec G304 -- name is restricted to path segments by partialNameRe.
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", path, fmt.Errorf("read partial %s: %w", name, err)
+		}
+		return string(data), path, nil
+	}
+	return "", "", fmt.Errorf("partial %q not found in %s", name, strings.Join(dirs, ", "))
+}
+
+// ExpandPartials returns the prompt src with every {{> name }} include
+// replaced by the partial it names, found in partialDirs, and every other
+// tag left as it is. Installed skills do not carry their partials
+// directories, so includes are inlined before the prompt is written out.
+func ExpandPartials(src string, partialDirs []string) (string, error) {
+	return expandPartials("prompt.md", src, partialDirs, 0)
+}
+
+func expandPartials(source, src string, dirs []string, depth int) (string, error) {
+	var b strings.Builder
+	pos := 0
+	for _, loc := range partialTagRe.FindAllStringSubmatchIndex(src, -1) {
+		start, end := loc[0], loc[1]
+		name := src[loc[2]:loc[3]]
+		line := strings.Count(src[:start], "\n") + 1
+		if !partialNameRe.MatchString(name) {
+			return "", &TemplateError{Source: source, Line: line, Msg: fmt.Sprintf("invalid partial name %q", name)}
+		}
+		if depth >= maxPartialDepth {
+			return "", &TemplateError{Source: source, Line: line, Msg: fmt.Sprintf("partial %q nests more than %d deep", name, maxPartialDepth)}
+		}
+		// An include alone on its line takes the line with it, as when
+		// rendering.
+		lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+		lineEnd := strings.IndexByte(src[end:], '\n')
+		rest := src[end:]
+		if lineEnd >= 0 {
+			rest = src[end : end+lineEnd+1]
+		}
+		if lineStart >= pos && strings.TrimSpace(src[lineStart:start]) == "" && strings.TrimSpace(rest) == "" {
+			start, end = lineStart, end+len(rest)
+		}
+		data, path, err := readPartial(dirs, name)
+		if err != nil {
+			return "", &TemplateError{Source: source, Line: line, Msg: err.Error()}
+		}
+		expanded, err := expandPartials(filepath.Base(path), data, dirs, depth+1)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(src[pos:start])
+		b.WriteString(expanded)
+		pos = end
+	}
+	b.WriteString(src[pos:])
+	return b.String(), nil
+}
+
+func (env *templateEnv) render(b *strings.Builder, source string, nodes []templateNode, depth int) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			b.WriteString(n.text)
+		case valueNode:
+			v, _ := lookupInput(env.input, n.ref.path)
+			b.WriteString(formatTemplateValue(v))
+		case ifNode:
+			branch := n.els
+			if env.truthy(n.ref) != n.negate {
+				branch = n.then
+			}
+			if err := env.render(b, source, branch, depth); err != nil {
+				return err
+			}
+		case partialNode:
+			if depth >= maxPartialDepth {
+				return &TemplateError{Source: source, Line: n.line, Msg: fmt.Sprintf("partial %q nests more than %d deep", n.name, maxPartialDepth)}
+			}
+			partial, path, err := env.loadPartial(n.name)
+			if err != nil {
+				return &TemplateError{Source: source, Line: n.line, Msg: err.Error()}
+			}
+			if err := env.render(b, path, partial, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every node reachable from nodes, following partials.
+func (env *templateEnv) walk(source string, nodes []templateNode, depth int, fn func(templateNode)) error {
+	for _, n := range nodes {
+		fn(n)
+		switch n.kind {
+		case ifNode:
+			if err := env.walk(source, n.then, depth, fn); err != nil {
+				return err
+			}
+			if err := env.walk(source, n.els, depth, fn); err != nil {
+				return err
+			}
+		case partialNode:
+			if depth >= maxPartialDepth {
+				return &TemplateError{Source: source, Line: n.line, Msg: fmt.Sprintf("partial %q nests more than %d deep", n.name, maxPartialDepth)}
+			}
+			partial, path, err := env.loadPartial(n.name)
+			if err != nil {
+				return &TemplateError{Source: source, Line: n.line, Msg: err.Error()}
+			}
+			if err := env.walk(path, partial, depth+1, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (env *templateEnv) truthy(ref templateRef) bool {
+	if ref.scope == "trigger" {
+		return env.triggers[ref.path[0]]
+	}
+	v, ok := lookupInput(env.input, ref.path)
+	if !ok {
+		return false
+	}
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case float64:
+		return t != 0
+	case int:
+		return t != 0
+	case []any:
+		return len(t) > 0
+	case map[string]any:
+		return len(t) > 0
+	default:
+		return true
+	}
+}
+
+func lookupInput(input map[string]any, path []string) (any, bool) {
+	var v any = input
+	for _, seg := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// formatTemplateValue writes strings as they are, missing values as nothing
+// and everything else as JSON.
+func formatTemplateValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(data)
+	}
+}
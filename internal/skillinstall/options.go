@@ -0,0 +1,112 @@
+// Package skillinstall builds the install options for a skill directory,
+// shared by the CLI and the MCP server so both install the same files.
+package skillinstall
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+	"github.com/felixgeelhaar/aios/internal/domain/agentregistry"
+	"github.com/felixgeelhaar/aios/internal/skill"
+)
+
+// Options loads the skill at skillDir and returns the install options
+// shared by sync, plan and lockfile installs: the target agents (names when
+// given, else the skill's clients, chosen from defs and, for global
+// installs, limited to those present on this machine), the SKILL.md
+// composed from skill.yaml and prompt.md with its partials inlined, the
+// supporting files to copy, the reference files of optional prompt
+// sections, the artifacts rendered for each target, and the version and
+// source recorded in the lockfile.
+// aiosVersion is checked against the skill's required aios version.
+func Options(projectDir, skillDir string, defs []agentregistry.AgentDefinition, names []string, global bool, aiosVersion string) (agents.InstallOptions, skill.SkillSpec, error) {
+	spec, err := skill.LoadSkillSpec(filepath.Join(skillDir, "skill.yaml"))
+	if err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	if err := skill.ValidateID(spec.ID); err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	if err := skill.CheckAIOSVersion(spec, aiosVersion); err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	targets, err := agents.ResolveTargets(defs, names, spec.Clients, global)
+	if err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	prompt, err := skill.ExpandPartials(skill.LoadPrompt(skillDir), skill.PartialDirs(skillDir))
+	if err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	files, err := skill.InstallableFiles(skillDir, spec)
+	if err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	disclosed := skill.DisclosePrompt(prompt)
+	var generated map[string]string
+	for _, ref := range disclosed.References {
+		for _, f := range files {
+			if f == ref.Path {
+				return agents.InstallOptions{}, spec, fmt.Errorf("prompt section %q would overwrite skill file %s", ref.Trigger, f)
+			}
+		}
+		if generated == nil {
+			generated = make(map[string]string)
+		}
+		generated[ref.Path] = ref.Content
+	}
+	artifacts, err := renderArtifacts(spec, disclosed.Body, targets)
+	if err != nil {
+		return agents.InstallOptions{}, spec, err
+	}
+	return agents.InstallOptions{
+		ProjectDir:   projectDir,
+		TargetAgents: targets,
+		Global:       global,
+		SkillContent: skill.BuildSkillMd(spec, disclosed.Body),
+		SourceDir:    skillDir,
+		Files:        files,
+		Generated:    generated,
+		Artifacts:    artifacts,
+		Version:      spec.Version,
+		Source:       lockSource(projectDir, skillDir),
+	}, spec, nil
+}
+
+// renderArtifacts renders the skill with each agent's configured renderer,
+// keyed by agent name, from the same prompt body as SKILL.md. Agents using
+// the default SKILL.md format get none.
+func renderArtifacts(spec skill.SkillSpec, body string, defs []agentregistry.AgentDefinition) (map[string][]agents.Artifact, error) {
+	out := make(map[string][]agents.Artifact)
+	for _, def := range defs {
+		renderer, err := skill.LookupRenderer(def.Renderer)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %w", def.Name, err)
+		}
+		for _, f := range renderer.Render(spec, body) {
+			out[def.Name] = append(out[def.Name], agents.Artifact{Path: f.Path, Content: f.Content})
+		}
+	}
+	return out, nil
+}
+
+// lockSource returns the skill directory as recorded in the lockfile:
+// relative to the project when inside it, so teammates can share the
+// lockfile, and absolute otherwise.
+func lockSource(projectDir, skillDir string) string {
+	absSkill, err := filepath.Abs(skillDir)
+	if err != nil {
+		return skillDir
+	}
+	absProject, err := filepath.Abs(projectDir)
+	if err != nil {
+		return absSkill
+	}
+	rel, err := filepath.Rel(absProject, absSkill)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return absSkill
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,99 @@
+package skillinstall
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/aios/internal/agents"
+)
+
+func writeSkill(t *testing.T, id, prompt string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "skill")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"skill.yaml": "id: " + id + "\nversion: 0.2.0\ndescription: Reviews code\n",
+		"prompt.md":  prompt,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestOptionsRenderArtifactsFromDisclosedBody(t *testing.T) {
+	defs, err := agents.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeSkill(t, "review", "Review the change.\n\n# @section code: when reviewing code\nCheck error handling.\n")
+	projectDir := filepath.Dir(dir)
+
+	opts, spec, err := Options(projectDir, dir, defs, []string{"cursor"}, false, "0.1.0")
+	if err != nil {
+		t.Fatalf("Options: %v", err)
+	}
+	if spec.ID != "review" || opts.Version != "0.2.0" || opts.Source != "skill" {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	rules := opts.Artifacts["cursor"]
+	if len(rules) != 1 {
+		t.Fatalf("expected one cursor rule, got %+v", opts.Artifacts)
+	}
+	for _, content := range []string{rules[0].Content, opts.SkillContent} {
+		if strings.Contains(content, "@section") || strings.Contains(content, "Check error handling.") {
+			t.Fatalf("optional section should move to a reference file:\n%s", content)
+		}
+		if !strings.Contains(content, "references/code.md") {
+			t.Fatalf("missing reference index:\n%s", content)
+		}
+	}
+	if opts.Generated["references/code.md"] == "" {
+		t.Fatalf("reference file not generated: %+v", opts.Generated)
+	}
+}
+
+func TestOptionsInlinesPartials(t *testing.T) {
+	defs, err := agents.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeSkill(t, "review", "Review {{ input.path }}.\n{{> tone }}\n\n# @section code: when reviewing code\n{{> tone }}\n")
+	shared := filepath.Join(filepath.Dir(dir), "partials")
+	if err := os.MkdirAll(shared, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "tone.md"), []byte("Be kind.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, _, err := Options(filepath.Dir(dir), dir, defs, []string{"cursor"}, false, "0.1.0")
+	if err != nil {
+		t.Fatalf("Options: %v", err)
+	}
+	for _, content := range []string{opts.SkillContent, opts.Artifacts["cursor"][0].Content, opts.Generated["references/code.md"]} {
+		if strings.Contains(content, "{{>") || !strings.Contains(content, "Be kind.") {
+			t.Fatalf("partial not inlined:\n%s", content)
+		}
+	}
+	if !strings.Contains(opts.SkillContent, "Review {{ input.path }}.") {
+		t.Fatalf("input tags should be kept:\n%s", opts.SkillContent)
+	}
+}
+
+func TestOptionsRejectsUnsafeID(t *testing.T) {
+	defs, err := agents.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeSkill(t, "../escape", "Prompt.\n")
+	if _, _, err := Options(filepath.Dir(dir), dir, defs, []string{"cursor"}, false, "0.1.0"); err == nil || !strings.Contains(err.Error(), "single path element") {
+		t.Fatalf("expected unsafe id error, got %v", err)
+	}
+}